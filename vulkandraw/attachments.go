@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// VulkanAttachmentImage is a color target owned by a framebuffer,
+// e.g. the albedo, normal or position target of a G-buffer, or a depth target.
+type VulkanAttachmentImage struct {
+	image  vk.Image
+	mem    vk.DeviceMemory
+	view   vk.ImageView
+	format vk.Format
+}
+
+// ColorAttachmentDescription describes a single-sampled color attachment which is cleared
+// when the render pass begins and stored when it ends, its previous contents are discarded.
+func ColorAttachmentDescription(format vk.Format) vk.AttachmentDescription {
+	return vk.AttachmentDescription{
+		Format:         format,
+		Samples:        vk.SampleCount1Bit,
+		LoadOp:         vk.AttachmentLoadOpClear,
+		StoreOp:        vk.AttachmentStoreOpStore,
+		StencilLoadOp:  vk.AttachmentLoadOpDontCare,
+		StencilStoreOp: vk.AttachmentStoreOpDontCare,
+		InitialLayout:  vk.ImageLayoutUndefined,
+		FinalLayout:    vk.ImageLayoutColorAttachmentOptimal,
+	}
+}
+
+// PresentAttachmentDescription is ColorAttachmentDescription for the swapchain image, the
+// render pass leaves it in vk.ImageLayoutPresentSrc so it can be presented right away.
+func PresentAttachmentDescription(format vk.Format) vk.AttachmentDescription {
+	desc := ColorAttachmentDescription(format)
+	desc.FinalLayout = vk.ImageLayoutPresentSrc
+	return desc
+}
+
+// DepthAttachmentDescription describes a single-sampled depth attachment which is cleared
+// when the render pass begins, its contents aren't needed after the render pass ends.
+func DepthAttachmentDescription(format vk.Format) vk.AttachmentDescription {
+	return vk.AttachmentDescription{
+		Format:         format,
+		Samples:        vk.SampleCount1Bit,
+		LoadOp:         vk.AttachmentLoadOpClear,
+		StoreOp:        vk.AttachmentStoreOpDontCare,
+		StencilLoadOp:  vk.AttachmentLoadOpClear,
+		StencilStoreOp: vk.AttachmentStoreOpDontCare,
+		InitialLayout:  vk.ImageLayoutUndefined,
+		FinalLayout:    vk.ImageLayoutDepthStencilAttachmentOptimal,
+	}
+}
+
+// depthFormats are the depth formats by preference, at least one of the first two
+// is supported as a depth attachment by every implementation.
+var depthFormats = []vk.Format{
+	vk.FormatD32Sfloat,
+	vk.FormatX8D24UnormPack32,
+	vk.FormatD24UnormS8Uint,
+	vk.FormatD32SfloatS8Uint,
+	vk.FormatD16Unorm,
+}
+
+// depthStencilFormats are the depth formats with a stencil aspect by preference, at least
+// one of the first two is supported as a depth-stencil attachment by every implementation.
+var depthStencilFormats = []vk.Format{
+	vk.FormatD24UnormS8Uint,
+	vk.FormatD32SfloatS8Uint,
+	vk.FormatD16UnormS8Uint,
+}
+
+// FindDepthFormat returns the first of the depth formats the GPU supports as an optimally
+// tiled depth attachment.
+func (v *VulkanDeviceInfo) FindDepthFormat() (vk.Format, error) {
+	if format, ok := v.findAttachmentFormat(depthFormats); ok {
+		return format, nil
+	}
+	err := fmt.Errorf("FindDepthFormat: no depth format is supported as an attachment")
+	return vk.FormatUndefined, err
+}
+
+// FindDepthStencilFormat is FindDepthFormat for a depth attachment that needs a stencil
+// aspect too, e.g. for VulkanOutlineInfo.
+func (v *VulkanDeviceInfo) FindDepthStencilFormat() (vk.Format, error) {
+	if format, ok := v.findAttachmentFormat(depthStencilFormats); ok {
+		return format, nil
+	}
+	err := fmt.Errorf("FindDepthStencilFormat: no depth-stencil format is supported as an attachment")
+	return vk.FormatUndefined, err
+}
+
+func (v *VulkanDeviceInfo) findAttachmentFormat(formats []vk.Format) (vk.Format, bool) {
+	for _, format := range formats {
+		var formatProps vk.FormatProperties
+		vk.GetPhysicalDeviceFormatProperties(v.gpu, format, &formatProps)
+		formatProps.Deref()
+		if formatProps.OptimalTilingFeatures&
+			vk.FormatFeatureFlags(vk.FormatFeatureDepthStencilAttachmentBit) != 0 {
+			return format, true
+		}
+	}
+	return vk.FormatUndefined, false
+}
+
+// CreateDepthImage creates a depth image of the swapchain extent, all the framebuffers share it
+// as their depth attachment, see CreateFramebuffers. It must be created again with the swapchain.
+func (s *VulkanSwapchainInfo) CreateDepthImage(format vk.Format) (VulkanAttachmentImage, error) {
+	a := VulkanAttachmentImage{
+		format: format,
+	}
+	imageCreateInfo := vk.ImageCreateInfo{
+		SType:     vk.StructureTypeImageCreateInfo,
+		ImageType: vk.ImageType2d,
+		Format:    format,
+		Extent: vk.Extent3D{
+			Width:  s.displaySize.Width,
+			Height: s.displaySize.Height,
+			Depth:  1,
+		},
+		MipLevels:     1,
+		ArrayLayers:   1,
+		Samples:       vk.SampleCount1Bit,
+		Tiling:        vk.ImageTilingOptimal,
+		Usage:         vk.ImageUsageFlags(vk.ImageUsageDepthStencilAttachmentBit),
+		SharingMode:   vk.SharingModeExclusive,
+		InitialLayout: vk.ImageLayoutUndefined,
+	}
+	if err := validateImageCreateInfo(s.gpu, &imageCreateInfo); err != nil {
+		err = fmt.Errorf("CreateDepthImage: %s", err)
+		return a, err
+	}
+	err := vk.Error(vk.CreateImage(s.device, &imageCreateInfo, nil, &a.image))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateImage failed with %s", err)
+		return a, err
+	}
+	a.mem, err = allocateTargetMemory(s.device, s.gpu, a.image)
+	if err != nil {
+		a.Destroy(s.device)
+		return a, err
+	}
+	aspect := vk.ImageAspectFlags(vk.ImageAspectDepthBit)
+	if hasStencil(format) {
+		aspect |= vk.ImageAspectFlags(vk.ImageAspectStencilBit)
+	}
+	viewCreateInfo := vk.ImageViewCreateInfo{
+		SType:    vk.StructureTypeImageViewCreateInfo,
+		Image:    a.image,
+		ViewType: vk.ImageViewType2d,
+		Format:   format,
+		SubresourceRange: vk.ImageSubresourceRange{
+			AspectMask: aspect,
+			LevelCount: 1,
+			LayerCount: 1,
+		},
+	}
+	err = vk.Error(vk.CreateImageView(s.device, &viewCreateInfo, nil, &a.view))
+	if err != nil {
+		a.Destroy(s.device)
+		err = fmt.Errorf("vk.CreateImageView failed with %s", err)
+		return a, err
+	}
+	return a, nil
+}
+
+// createAttachmentImage creates an image to back the attachment, besides being a color attachment
+// it may be read by a later subpass as an input attachment or by a later pass as a texture.
+func (s *VulkanSwapchainInfo) createAttachmentImage(
+	desc vk.AttachmentDescription) (VulkanAttachmentImage, error) {
+
+	a := VulkanAttachmentImage{
+		format: desc.Format,
+	}
+	imageCreateInfo := vk.ImageCreateInfo{
+		SType:     vk.StructureTypeImageCreateInfo,
+		ImageType: vk.ImageType2d,
+		Format:    desc.Format,
+		Extent: vk.Extent3D{
+			Width:  s.displaySize.Width,
+			Height: s.displaySize.Height,
+			Depth:  1,
+		},
+		MipLevels:   1,
+		ArrayLayers: 1,
+		Samples:     desc.Samples,
+		Tiling:      vk.ImageTilingOptimal,
+		Usage: vk.ImageUsageFlags(vk.ImageUsageColorAttachmentBit |
+			vk.ImageUsageInputAttachmentBit | vk.ImageUsageSampledBit),
+		SharingMode:   vk.SharingModeExclusive,
+		InitialLayout: vk.ImageLayoutUndefined,
+	}
+	if err := validateImageCreateInfo(s.gpu, &imageCreateInfo); err != nil {
+		err = fmt.Errorf("createAttachmentImage: %s", err)
+		return a, err
+	}
+	err := vk.Error(vk.CreateImage(s.device, &imageCreateInfo, nil, &a.image))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateImage failed with %s", err)
+		return a, err
+	}
+	a.mem, err = allocateTargetMemory(s.device, s.gpu, a.image)
+	if err != nil {
+		a.Destroy(s.device)
+		return a, err
+	}
+	viewCreateInfo := vk.ImageViewCreateInfo{
+		SType:    vk.StructureTypeImageViewCreateInfo,
+		Image:    a.image,
+		ViewType: vk.ImageViewType2d,
+		Format:   desc.Format,
+		Components: vk.ComponentMapping{
+			R: vk.ComponentSwizzleR,
+			G: vk.ComponentSwizzleG,
+			B: vk.ComponentSwizzleB,
+			A: vk.ComponentSwizzleA,
+		},
+		SubresourceRange: vk.ImageSubresourceRange{
+			AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+			LevelCount: 1,
+			LayerCount: 1,
+		},
+	}
+	err = vk.Error(vk.CreateImageView(s.device, &viewCreateInfo, nil, &a.view))
+	if err != nil {
+		a.Destroy(s.device)
+		err = fmt.Errorf("vk.CreateImageView failed with %s", err)
+		return a, err
+	}
+	return a, nil
+}
+
+func (a *VulkanAttachmentImage) Destroy(device vk.Device) {
+	vk.DestroyImageView(device, a.view, nil)
+	vk.DestroyImage(device, a.image, nil)
+	vk.FreeMemory(device, a.mem, nil)
+}
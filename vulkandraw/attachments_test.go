@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// The swapchain image is undefined when acquired and must be in vk.ImageLayoutPresentSrc
+// when presented, the render pass does both transitions.
+func TestPresentAttachmentDescription(t *testing.T) {
+	desc := PresentAttachmentDescription(vk.FormatB8g8r8a8Unorm)
+	if desc.InitialLayout != vk.ImageLayoutUndefined {
+		t.Errorf("initial layout is %d, want vk.ImageLayoutUndefined", desc.InitialLayout)
+	}
+	if desc.FinalLayout != vk.ImageLayoutPresentSrc {
+		t.Errorf("final layout is %d, want vk.ImageLayoutPresentSrc", desc.FinalLayout)
+	}
+	// an undefined initial layout discards the contents, they must be cleared
+	if desc.LoadOp != vk.AttachmentLoadOpClear {
+		t.Errorf("load op is %d, want vk.AttachmentLoadOpClear", desc.LoadOp)
+	}
+	if desc.StoreOp != vk.AttachmentStoreOpStore {
+		t.Errorf("store op is %d, want vk.AttachmentStoreOpStore", desc.StoreOp)
+	}
+}
@@ -1,9 +1,26 @@
 // Code generated by go-bindata.
 // sources:
-// shaders/.DS_Store
+// shaders/fullscreen-vert.spv
+// shaders/fullscreen.vert
+// shaders/outline-frag.spv
+// shaders/outline-vert.spv
+// shaders/outline.frag
+// shaders/outline.vert
+// shaders/particles-comp.spv
+// shaders/particles-frag.spv
+// shaders/particles-vert.spv
+// shaders/particles.comp
+// shaders/particles.frag
+// shaders/particles.vert
+// shaders/post-frag.spv
+// shaders/post.frag
 // shaders/tri-frag.spv
+// shaders/tri-tesc.spv
+// shaders/tri-tese.spv
 // shaders/tri-vert.spv
 // shaders/tri.frag
+// shaders/tri.tesc
+// shaders/tri.tese
 // shaders/tri.vert
 // DO NOT EDIT!
 
@@ -72,27 +89,287 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _shadersDs_store = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xec\x98\xcd\x4a\xc3\x40\x14\x85\xcf\x8d\x59\x04\x74\x31\x4b\x97\xb3\x74\x63\xc1\x37\x18\x4a\x15\xdc\xea\x0b\x68\xfd\x43\x28\x54\xb4\x76\x9d\x07\xf4\x01\x7c\x1b\x9d\xc9\x9c\x58\x9b\x34\x82\xab\x16\x3d\x1f\x84\x6f\x20\xf7\x64\x26\x9b\xcc\xdc\x00\xb0\xf1\xeb\xed\x09\xe0\xe2\xb0\x42\xb6\xa5\xc1\x06\x2a\x5e\x3d\x0a\xba\x4c\xe1\xe6\x19\xf7\x78\xc6\x35\x1e\x30\xc2\xcb\xe6\x67\xed\x1c\x69\xed\x07\x58\xc4\x75\x3f\xe2\x78\x6d\xfd\x4f\x58\x9e\xcf\xe6\x37\xd3\xd9\x7c\x8a\xe6\xe5\xec\x3d\xfa\xe8\xa3\xa1\xc9\xae\x72\x4b\xdc\xc5\xd1\x62\x20\x77\xd1\xc9\x55\xcc\x8d\xda\xf9\xd6\xeb\x71\x36\x58\xcf\x79\x3a\xf5\x6f\x9d\x7a\x21\x84\x10\xe2\x27\x2c\xab\xda\xdf\xee\x32\x84\x10\x3b\x48\xfa\x3e\x78\x3a\xd0\x75\xb6\xf1\x7e\x41\x97\xdf\x32\x8e\xf6\x74\xa0\xeb\x6c\x63\x5d\x41\x97\x74\x45\x3b\xda\xd3\x81\xae\xb3\xf9\xd1\x32\x36\x1f\xc6\x99\xdb\xe6\xc5\x1c\xed\xe9\xf0\xcb\x97\x16\xe2\x9f\xb0\x97\xe5\xd2\xfe\x7f\x3a\xdc\xff\x0b\x21\xfe\x30\x56\x4e\x2e\x27\x63\x7c\x35\x04\x3d\xd2\x5e\xeb\xe3\x75\xd5\x06\xc0\xdd\x1c\xfd\x43\x40\x91\x7f\x16\x1e\x62\x75\xdf\xd3\x81\xae\xb3\x75\x10\x10\x62\x5b\x7c\x06\x00\x00\xff\xff\xd9\x1b\x2a\x0a\x04\x18\x00\x00")
+var _shadersFullscreenVertSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\x92\x4b\x6f\xd3\x50\x10\x85\x8f\x1f\xd7\xa5\xa4\xaf\xf4\x91\xf2\x0c\x29\x61\x05\xa8\xaa\x50\x41\x48\x88\x47\x61\x11\x90\xb2\x88\x40\x62\x6b\x5d\xea\x2b\xd7\x10\xec\xc8\x36\x52\x96\x48\xec\xf9\x51\x08\x89\xbf\xc4\x06\x09\xcd\xf8\x24\x38\x78\xe3\x3b\xdf\x9c\x3b\x67\x66\xec\xc0\x1f\xae\x01\x1e\xe4\xb9\x83\xe6\xe9\xc2\x57\xd2\x41\xa4\xef\xd1\xf8\xdd\xf8\xb8\xaa\x93\xe3\xd3\x87\x27\x22\xd8\x42\x20\x2f\xcd\x6d\xe3\x92\x1c\xe1\x03\xf8\x6c\xb3\x5c\xce\x92\x0d\x01\x18\x3d\x07\x9a\xfb\xe1\x09\x5b\xc7\x68\x1c\x9f\xbd\x7d\x19\x57\x6e\x66\x4b\x5b\xbb\xb8\xba\xb0\x89\x2b\xe3\xe2\xc3\x47\x77\x5e\x57\xab\x9a\x0b\x9b\x64\x79\x1a\x4f\x6d\x9e\x7e\xb1\xa9\x8b\x4f\x1f\x9c\xcc\xec\xf9\x27\x18\x84\x2b\x7e\x06\x46\x3b\xaa\xdd\xfc\x55\x51\x94\x89\xf4\x60\x10\x69\x0f\xe9\x34\x7e\xef\xca\xda\xcd\xdf\xe4\x89\x9b\x37\x3c\x6a\xf8\xc4\x95\x4d\x4a\xf4\x11\x39\x98\x2b\xaa\xac\xce\x8a\x5c\x2b\x1b\xf2\x11\x42\xf5\xe9\xb7\x62\xf1\xe8\x00\xb8\x0b\xe0\xb5\x56\xd7\x14\x3a\x4b\x4d\xa0\x4c\xfa\xdd\x83\x8f\x35\x00\x47\x08\x74\x6b\x72\xee\x21\xc0\x3a\x80\x01\x80\x43\x84\xb8\x0c\x68\x2c\xfa\x01\x42\xad\x23\x9e\xc2\x9f\xb4\x62\x61\x07\x08\xb1\xc1\xbb\x1e\xf5\x9b\x3c\x6f\x50\xbf\xc9\x6f\x21\xf9\x7b\xd4\x6f\xfd\x17\x6f\xd3\x4f\xfc\x77\xe8\x2f\x77\xfa\xec\x7d\x87\xb5\xbb\xf4\x8d\x58\xbb\xbb\xfc\xc6\xff\x6a\xed\x72\x6e\x89\x65\x8e\x3d\x8d\xf0\x62\x11\xef\x4b\x84\xaf\xcf\xef\xc3\xe8\xac\x07\x64\xfb\xad\x3b\x3d\xd6\x10\xcf\x43\xd6\x97\x1e\x1e\xc1\xe8\xfe\x7c\xe6\x65\x87\xbf\xe1\xe3\x0a\x80\xa7\xf4\xbf\xca\xde\x7f\xc2\x68\x7c\x8d\x4c\x66\xfe\x45\x76\x9d\x5c\xe6\x2e\xe8\x79\x83\x7c\xa1\xb9\xc9\x7b\x6d\x4d\x9f\x7c\xc2\xde\x6f\xf1\x9e\xf0\x67\xfa\xa7\x37\xec\x3b\xf3\x03\x6a\x64\x07\xdf\xc8\x8e\xc8\x65\xee\x09\x22\x9d\xeb\x36\x79\x8f\x7b\x38\x83\xd1\xb9\x87\xdc\xef\x2e\xeb\x0f\xa9\xfd\x03\x0f\x8f\xe1\xe1\xef\x00\xff\x30\xe1\xa2\xbc\x03\x00\x00")
 
-func shadersDs_storeBytes() ([]byte, error) {
+func shadersFullscreenVertSpvBytes() ([]byte, error) {
 	return bindataRead(
-		_shadersDs_store,
-		"shaders/.DS_Store",
+		_shadersFullscreenVertSpv,
+		"shaders/fullscreen-vert.spv",
 	)
 }
 
-func shadersDs_store() (*asset, error) {
-	bytes, err := shadersDs_storeBytes()
+func shadersFullscreenVertSpv() (*asset, error) {
+	bytes, err := shadersFullscreenVertSpvBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "shaders/.DS_Store", size: 6148, mode: os.FileMode(420), modTime: time.Unix(1471188258, 0)}
+	info := bindataFileInfo{name: "shaders/fullscreen-vert.spv", size: 956, mode: os.FileMode(420), modTime: time.Unix(1792148660, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _shadersTriFragSpv = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x54\x51\x5b\x4a\xc3\x50\x10\x9d\x26\x26\x6d\x8c\x5a\x45\xd0\x3f\xa9\xf8\xa9\x94\x22\x55\x04\xd1\xa2\x82\xfe\x14\x04\x5d\xc0\xe5\xda\x5e\x62\xb5\x26\x35\x8f\x7f\x97\xe0\x36\x5c\x95\xdb\xf0\x43\xc1\x33\x93\x09\x68\x2f\xd3\xb9\x67\xe6\xe4\xcc\x99\xc4\xf7\xf6\xda\x44\x2d\x9c\x0e\x75\xa9\xfe\x6d\x90\x07\x4c\x14\x53\x28\xf9\x66\x7c\x3f\xee\x17\xe5\xb4\x3f\x3c\x1a\x70\x7f\x8d\x7c\xe1\x71\xaf\x0b\xce\x12\x32\xc7\x8b\x9d\xa5\x5c\x8f\x10\xeb\xe0\x70\x0d\xe2\xb8\xf9\x50\x24\x7a\x6f\x31\x2f\x82\x9e\xb9\xb8\xbb\x34\x85\x5b\xd8\xdc\x96\xce\x14\x8f\x76\xea\x72\x93\x3d\x3c\xb9\x49\x59\xfc\xe7\xa0\x35\x4b\x13\x33\xb7\x69\x52\xd9\xc4\x99\xe1\xe1\x60\x61\x27\xcf\x14\x80\xf5\x77\x66\x80\xc3\x73\xab\xeb\xdc\x26\x57\xd9\x3c\xcb\xe1\x5b\x94\x88\x76\x74\xaf\x4d\xb8\x60\x1f\xbb\xe2\x88\xe4\xbe\x85\x5b\x88\xdc\x43\x6c\x83\xcf\x7e\x43\xdd\xa7\x87\xff\x8e\xf8\xaf\xf7\x38\x55\x1c\x69\x6d\x1f\x98\xb9\xcb\xdc\xfb\xbc\x1d\x35\x38\xd6\x79\x0d\x5e\x41\x7c\x7f\xbc\x9e\x35\x78\x55\xba\x6f\xa3\x03\xa8\xb6\xe5\x7d\xd6\x1a\xb1\x72\xb9\x7f\x8c\x7d\x3c\xf5\x41\x3a\xef\x0b\x95\x00\xf9\x1c\x28\xd2\xe7\x7e\xf0\x15\x4e\x10\xbf\x01\x00\x00\xff\xff\xbb\x64\x7d\x87\xc8\x01\x00\x00")
+var _shadersFullscreenVert = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x90\x4f\xab\xd4\x30\x14\xc5\xf7\xfd\x14\x07\x1e\x3c\x5a\xa9\xf3\xd2\x50\x37\xaf\x6f\x16\xea\x42\x04\x17\xe2\xc2\x8d\x48\xc8\xa4\x77\xd2\x68\x4c\x4a\x92\x66\x2a\x32\xdf\x5d\xd2\x8e\x7f\x10\x37\xf9\x73\xee\x3d\xbf\x9b\x93\xbb\x4c\x21\x1a\xef\xd0\xbf\x60\xd5\x1d\xad\x89\xdc\x76\x7d\xf3\x4e\xbc\xfc\xf0\x4a\x44\x9a\x65\x90\x89\x44\x9c\xe4\x48\x41\xf8\xd3\x17\x52\x29\xe2\x11\xe4\xe4\xc9\xd2\xff\x2c\x93\x1c\x8d\xd3\xc2\x4a\xa7\x17\xa9\x49\xf4\x9c\xcd\x52\x7d\xfd\xe3\xb1\xf2\xbb\x5f\x12\x6a\xeb\x95\x4c\xc5\x79\x04\x6b\x50\xa4\x4c\x8a\x23\xd1\xfa\xda\xfb\x30\x0e\x55\x91\xb4\x15\xef\x29\x7c\xa4\x90\x68\xc5\x8f\x0a\x40\x26\xd5\x6f\xb2\x8f\xa6\xd8\x87\xea\x3a\x54\xd9\x9b\x11\xdf\xa4\x71\x75\xb3\x77\x3d\x3c\x40\x22\x1a\xa7\x2d\x21\x05\x23\xb7\x83\xf2\x99\x82\x71\x1a\x69\x22\x5c\x26\x6f\x09\xd9\xd0\x65\xf6\x21\xb5\x70\x1e\x79\x1f\x73\x5a\xce\x67\x0a\x70\x44\x23\x8d\x8f\x37\x5a\xa9\x19\x45\x11\xac\x45\xd7\x82\x43\x53\xc2\x92\x23\x6a\xd6\x82\x35\x2d\x6a\x7e\xdb\x59\x0b\xde\x20\x7a\x7c\x2a\xad\x9f\x11\x67\xe9\xe2\x36\x32\xaa\x40\xe4\x0a\xf0\x57\x48\x1c\x4b\x1e\x5e\xd7\xda\x8a\x3d\xe4\x5b\x37\xd2\x8a\xa7\x27\x74\x0d\xee\xc1\x5b\xfc\x53\xb9\x07\x6f\x86\x82\xf8\xeb\x0b\x76\x4a\x5f\xff\xa6\x3e\x03\x3f\x30\x3c\x47\x77\x28\x8f\x2b\x4b\x77\x60\xcd\x50\x5d\xab\x9f\x03\x00\x2c\x44\x67\x88\xf1\x01\x00\x00")
+
+func shadersFullscreenVertBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersFullscreenVert,
+		"shaders/fullscreen.vert",
+	)
+}
+
+func shadersFullscreenVert() (*asset, error) {
+	bytes, err := shadersFullscreenVertBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/fullscreen.vert", size: 497, mode: os.FileMode(420), modTime: time.Unix(1792148660, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersOutlineFragSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\x91\x51\xab\xd3\x40\x10\x85\xbf\x24\xbb\xb9\x5e\xaf\xf6\xb6\x8a\xfa\x26\x95\x3e\x0a\xa5\x48\x15\x41\x14\xaa\x60\x7d\x28\x08\xfa\x03\xc2\x9a\x86\xb4\x1a\x93\x92\xa4\x7f\xd0\x5f\xe5\x8b\x20\x33\x19\x4b\x6b\x5e\x76\xcf\x99\x33\xb3\x67\x4e\x92\x78\x76\x05\x11\xf2\x3d\x60\xf8\x26\xc4\xca\xdc\x90\xea\xb9\xde\x7c\xdd\xcc\xbb\x7e\x3b\x5f\xbe\x5c\x88\x60\x44\x22\x87\xd6\x6e\x49\x71\x40\x0c\xfc\x0c\xfb\x5a\x78\xa9\x8e\x49\x94\xbb\x52\x3c\xdc\x7f\x45\xe0\xb8\x66\xbd\xc9\x56\x5f\xde\x67\x5d\x71\x08\x6d\xe8\x8b\xac\xdb\x85\x6d\xd1\x66\xcd\xb7\xef\x45\xde\x77\x97\x9a\x5d\xd8\xee\xeb\x32\xab\x42\x5d\x1e\x43\x59\x64\xcb\x17\x8b\x43\xc8\x7f\xe0\x71\x17\x6f\x7a\xbc\xba\x3a\x7e\x6c\x43\xf9\xa1\xa9\x9a\x16\xd5\x88\xb7\xcf\xc7\xbe\xda\xd7\x05\xa9\x32\xe2\x10\xf2\x41\x72\xe2\x64\x97\x2e\x0f\x55\xa1\xb3\x1c\x1e\x68\xac\x6f\x8d\xd3\xd9\x4f\x2d\x9f\x4f\x67\x73\x66\xff\x71\x91\x71\x63\xc9\x8d\xe4\x94\xcd\x43\x62\x52\xe0\x19\x89\x66\x22\xf7\xc7\x24\xdc\x01\xa6\xc0\x13\xdd\x1a\xc5\xd2\x33\xc5\x71\xd7\xb2\xbc\x06\xde\x9c\xe1\xc1\x8b\xd3\xd9\xff\x7a\x44\x7f\x63\xd8\x99\x5e\xb0\x37\xee\x11\x8e\x7b\xf6\x96\x78\x7c\x6e\xf8\xbe\xf9\x97\xfe\x91\x69\x45\xff\x0a\xaf\x7e\x63\xab\x8b\xe7\xdf\xc4\xdc\x02\x2b\x3c\x23\xdb\xd1\xdb\x8c\xb7\xe6\x7f\x62\xfc\x3b\xfd\xeb\x03\xfe\x43\xc4\x6b\x22\xfe\x0e\x00\x1f\x00\x33\x96\x6c\x02\x00\x00")
+
+func shadersOutlineFragSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersOutlineFragSpv,
+		"shaders/outline-frag.spv",
+	)
+}
+
+func shadersOutlineFragSpv() (*asset, error) {
+	bytes, err := shadersOutlineFragSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/outline-frag.spv", size: 620, mode: os.FileMode(420), modTime: time.Unix(1792149039, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersOutlineVertSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\x52\x5d\x6b\x13\x41\x14\x3d\xb3\xb3\xbb\x69\x13\x4d\xbf\x4c\x53\x6b\x8d\x95\x3e\x0a\xa5\x48\x15\x41\x54\xaa\x42\x7c\x08\xb4\x58\xf0\x75\x19\x37\x43\xba\xba\xee\x86\xdd\x0d\x88\x4f\xfe\x02\x7f\x94\xbf\xca\x17\x41\xee\xcd\xd9\x90\xba\xb4\xcc\xcc\x39\xf7\x9e\x73\xee\x4c\x6c\x70\xd2\x01\x0c\xe4\x7b\x8c\xe5\xb7\x83\x40\x91\x1e\x62\x5d\xc7\x93\xeb\xc9\x69\xdd\x4c\x4f\xcf\x9f\x9d\x49\x41\x1f\x56\x16\xe5\xb6\xd0\x91\x2d\x02\x00\xdf\x5c\x56\xc8\x5e\xd8\x50\x57\xab\xf8\x6f\x23\xe7\x4d\x8c\x27\xc9\xc5\xc7\xb7\x49\xed\xe7\xae\x72\x8d\x4f\xea\x1b\x37\xf5\x55\x52\x7e\xfe\xe2\xd3\xa6\xbe\x5d\x73\xe3\xa6\x59\x31\x4b\x72\x57\xcc\x16\x6e\xe6\x93\xf3\xa7\x67\x73\x97\x7e\x45\x84\xf0\x96\x57\x84\x18\x11\x80\x59\x9e\x5c\xf9\xea\x93\xaf\x1a\xff\x5d\x32\xc4\xc4\x41\xae\xac\xb3\x26\x2b\x0b\xc4\xe8\x28\x6e\x5a\x3c\x2b\x9a\xeb\xec\x87\x97\xba\x96\x0b\x96\xdc\xbb\x3c\x9b\xbf\xcf\xea\xc6\x15\xa9\x5f\x71\x96\xdc\x22\xcf\x57\x5c\xa4\x93\x8a\x02\x74\x2f\xb3\xcf\xcb\x5a\xb3\xc6\x00\x2e\x17\x4d\x9e\x15\xa2\x21\x69\xa5\x0a\x48\xcb\xbc\xac\xd4\x73\x89\x49\x9e\x3a\x75\xb9\x04\x91\x3e\xb9\xd5\x92\x7d\x1f\x10\xad\x66\xe9\x71\x6d\x31\x43\xcc\xac\x61\x01\x31\xbb\x86\x59\x62\x92\x6d\x0c\xbb\xaa\x1b\x23\x54\x6c\xb4\xa6\xdb\x66\x3c\xf9\x0f\x33\xc4\xb6\xa9\x11\x53\x63\x0f\x01\x36\xf4\xf7\x63\xb1\x09\xe8\x7e\x1f\x16\x5d\x00\xc7\x00\x86\x08\xd5\xbb\x4b\xff\x01\x42\xdc\x21\x27\x7f\x4f\x78\xbe\x4b\x8f\x23\x84\xe8\xb3\x5e\xb0\x11\xdf\xb2\xd5\xe8\xf3\xff\x18\x21\xb6\x38\x9b\xcc\xf3\x72\xed\x6c\xe9\xb3\x4d\x1f\x43\x1f\x39\xef\xd0\x57\xfa\x77\xc9\xf5\xd8\xbf\xcb\x8c\x86\xb9\xf7\xe8\x29\x7a\x23\xbe\x67\x9b\x43\xfa\xef\x01\x3a\x73\xcc\x7e\x39\x77\x88\xb5\x7e\x03\xea\x49\xfd\x3e\xb9\x2e\x79\x59\x87\x9a\xe6\xe7\x1b\xe1\x0f\xe8\x25\x1e\xcf\x11\xe9\x5d\x06\xcc\x2b\x9a\x7f\x10\xe0\x3e\x80\x57\xbc\xd3\x43\xe6\xbd\xc4\x86\x66\x7d\x40\xec\x90\x3d\x86\xfd\x17\x88\xd4\xfb\x88\xf9\x06\xd4\x10\xff\x87\xc4\x7f\x21\x52\x8d\x11\x75\x04\xbf\x42\xa4\x3e\x8f\x88\x0f\xa9\x75\xc0\x7b\xb5\xbc\xcf\xd7\xb0\xfa\x9e\x52\xf7\x17\x06\x2f\x60\xf0\x6f\x00\xc4\xe6\x56\x34\x5c\x04\x00\x00")
+
+func shadersOutlineVertSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersOutlineVertSpv,
+		"shaders/outline-vert.spv",
+	)
+}
+
+func shadersOutlineVertSpv() (*asset, error) {
+	bytes, err := shadersOutlineVertSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/outline-vert.spv", size: 1116, mode: os.FileMode(420), modTime: time.Unix(1792149039, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersOutlineFrag = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8e\xc1\x4a\xc4\x40\x0c\x86\xef\xf3\x14\x81\xbd\x6c\x2f\x52\xa4\x5e\x2c\x7b\x50\x41\x2f\x82\xe0\x0b\x0c\xd9\x69\xb6\x1d\xcd\x26\x65\x26\x53\x14\xd9\x77\x97\x3a\x68\x2f\x1e\x7f\xfe\x7c\x5f\xfe\xdd\x42\x29\x47\x15\xe8\x6e\x5a\xb7\xa3\x0f\x23\xf9\x89\x4f\xcf\xfe\xee\xf5\xde\x67\x9a\x31\xa1\x91\xcf\x13\x0e\x94\xbc\x1e\xdf\x28\x58\x86\x5b\x20\xc1\x23\xd3\x7f\xc8\x84\x43\x94\xd1\x33\xca\x58\x70\x24\xdf\x5d\xb7\x33\x86\xf7\x8d\x61\xfc\xd4\x62\xb0\x9f\x4b\x9e\x7c\x50\xc9\x86\x62\x0d\x14\x89\x27\x4d\x67\x78\x29\xc6\x51\x08\xbe\x1c\x00\x2c\x14\x3a\x08\xca\x9a\xfa\x35\x9e\x58\xd1\x20\x07\x64\xea\xdd\x05\xb4\x9e\xf6\x7f\x4a\xd6\x80\xb6\x8e\x39\x40\xdb\xac\x75\x15\x94\xc7\x84\xe3\x43\xb5\x2c\x1a\x07\x38\x63\x94\x7d\x53\x5f\x6c\x25\x1c\x7e\x8d\x57\x41\x59\x53\xef\x2e\xee\x7b\x00\x3f\xfe\x71\x04\x21\x01\x00\x00")
+
+func shadersOutlineFragBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersOutlineFrag,
+		"shaders/outline.frag",
+	)
+}
+
+func shadersOutlineFrag() (*asset, error) {
+	bytes, err := shadersOutlineFragBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/outline.frag", size: 289, mode: os.FileMode(420), modTime: time.Unix(1792149039, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersOutlineVert = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8e\xbd\x4e\xc3\x30\x10\xc7\xf7\x3c\xc5\x49\x5d\x1a\x54\xb5\x01\x95\x85\x88\x01\x16\x16\x24\x10\x2f\x60\x5d\x9d\xab\x63\xea\xde\x59\x3e\xbb\xb4\xa0\xbe\x3b\x4a\xc2\xd7\xc0\x76\xff\xd3\xff\xe3\x37\x3b\x50\x52\x2f\x0c\xeb\xeb\xa6\x9a\xd1\x31\x13\x8f\xf2\xe1\xd1\xdc\xbd\xdc\x1b\xa5\x88\x09\x33\x19\xed\xb1\xa3\x64\x64\xf3\x4a\x36\x2b\xdc\x00\x31\x6e\x02\xfd\x17\xe9\xb1\xf3\xec\x4c\x40\x76\x05\x1d\x99\xf5\x55\x13\xd1\xee\x7e\x33\x01\x4f\x52\x32\xcc\x63\xd1\xde\x58\x61\xcd\xc8\xb9\x86\xc2\x7e\x2b\x69\x0f\x4f\x25\x07\xcf\x04\x1f\x15\x00\x1c\xc8\xae\xc1\x4a\x90\xd4\x0e\x72\x1b\x04\x33\xa8\xc5\x40\x6d\x75\x06\x99\xac\xed\x4f\x65\x10\x8b\x79\x80\xb9\x85\xa6\x06\xcf\x53\x3e\x8a\xb6\xd5\x41\x7c\x07\x7b\xf4\x3c\xaf\xa7\xea\xd5\x0a\x5c\x92\x37\xc8\x3d\x81\xf6\x18\x09\x30\x49\xe1\x6e\x7c\x48\xf2\xce\xf3\x62\xbc\x35\x13\x5b\x1f\x60\x8f\xba\xd3\x61\xf3\x8f\x03\xc3\x50\xe5\x82\x79\x16\xf5\x5f\xcb\xc3\xe6\x3c\x8a\x2e\x8f\xa7\x77\xb8\xf8\x86\x5c\x8e\xd4\x0b\xb8\x5c\x36\x75\x5b\x9d\xab\xcf\x01\x00\x2b\x8c\x14\xb8\x7a\x01\x00\x00")
+
+func shadersOutlineVertBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersOutlineVert,
+		"shaders/outline.vert",
+	)
+}
+
+func shadersOutlineVert() (*asset, error) {
+	bytes, err := shadersOutlineVertBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/outline.vert", size: 378, mode: os.FileMode(420), modTime: time.Unix(1792149039, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesCompSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x52\xdd\x4e\x13\x61\x10\x3d\xfb\xf3\xed\xb6\x82\x40\xa5\x80\x20\x7f\x82\x8a\x5a\x45\xe4\x4f\xb4\x02\x62\x4c\x2a\x09\x17\x28\x0f\xb0\x59\xda\x4d\xa9\xd6\xb6\xa1\x0b\xb7\xea\x0b\xf8\x1e\xde\xf8\x00\xde\x98\xf8\x52\x1a\x13\x12\x33\xd3\xf3\xe1\x42\x6c\xb7\xd9\x6f\xce\xcc\x39\xdf\x99\x99\x7a\xee\x7c\x08\x38\x90\xcf\x0b\xf4\x3e\x05\xb8\x8a\xf4\x21\xd0\x77\x65\xef\x60\x6f\xb1\x9b\xd6\x16\x57\xd7\x96\xa4\x60\x00\x9e\xbc\x34\x37\x88\x00\x06\x80\x0b\xe0\x43\xdc\x68\x09\x2e\xd9\x21\x04\x8a\x15\xa8\xeb\xf0\xe7\xc1\x53\xfc\xa7\x03\xf8\xc8\xa3\xb2\x17\xed\xbc\x7d\x19\x75\x93\x4e\x7c\x1c\xa7\x49\xd4\x3d\x8a\x6b\xc9\x71\xd4\x3e\x7c\x97\x54\xd3\xee\xc5\x9a\xa3\xb8\xd6\x68\xd5\xa3\x66\xdc\xaa\x9f\xc4\xf5\x24\x5a\x5d\x5e\xea\xc4\xd5\xf7\x30\xf0\x2f\xdc\x6f\x90\x53\x87\xf5\x66\x54\x69\xb6\x0f\xe3\xe6\x6e\xeb\xb4\x5d\x8d\xd3\x46\xbb\xb5\xfb\x4a\xf3\x3e\x7c\x00\x07\x69\xd2\x11\xbf\x01\x63\xf9\xd6\x52\x89\x8d\xc6\xe2\xb7\xda\x3e\x69\xa5\xca\xf1\xb4\x4f\x79\x0c\x0c\x02\x00\xfb\xf1\x71\xda\xa8\x36\x13\xab\x11\x50\xa3\xd3\xee\x9e\xc7\xa2\x71\x9a\x34\x95\x13\x66\x38\x5d\xe5\x04\x08\x2d\x27\x83\x1b\x78\xc8\x11\xaf\xc0\xd7\x5e\xfa\x00\x4c\x02\x78\x4d\x6f\xf2\xcc\xf3\x6d\x31\x87\x98\x9c\x2b\xf0\xb4\xce\x65\x3e\xf8\x0f\xc7\xfa\x13\x2c\xc7\xbb\xf2\xea\x4b\xf6\xd7\xab\x09\x2f\xf1\x44\x37\xe4\x8e\xa5\x5e\x78\x73\xe7\xb9\x5e\x7c\x93\xf1\x30\x5c\x5c\xd1\xd8\x53\xff\x72\x1e\x81\x8f\x7e\x00\xb3\xac\x19\x83\x8f\xab\x80\x62\xa2\x39\x0b\x1f\x03\xf4\x25\x78\x99\xb1\x47\xac\x44\xfe\x20\xf9\x52\x3f\xc4\x7a\xd1\x18\x85\x87\x02\xf5\xa7\xb9\xd7\x02\x73\x52\x7b\x0d\x40\x9e\x33\x2a\x33\x36\xc4\xc4\xdb\x30\xb9\xf6\x2e\x89\x8b\xbc\xcb\xc6\x23\xcc\x8b\xde\x28\xb9\x56\x7f\x8c\xb1\xdc\x59\x84\x8b\xeb\xec\x71\x9c\x98\x4b\x5f\x32\xfb\x71\xfe\xa6\xe0\x9d\xcf\x7d\x9a\xf3\xcd\x53\x6f\x82\x9c\x90\x7e\x27\xb8\x2b\x97\xf9\x1b\x3c\x8f\xd3\x5f\x81\xff\x13\xe0\xd3\xb6\x8d\xa7\x7a\xf1\x8f\x75\x18\xdd\x87\xcb\x7e\x64\x27\xbf\xe1\x62\x1a\xc0\x0e\x8c\xee\x7c\x86\xb3\x96\xf9\x6e\x66\x76\x35\xc3\x9a\x51\xee\xd7\x70\x0e\xb6\x66\x8e\xf8\x37\x18\xed\x79\x9e\x3c\xc1\x7f\xc1\xc3\x2d\xde\xf9\x07\xbe\xe6\x6e\x03\x8a\xc9\xfd\x72\x3e\x83\xa3\x67\xc1\x76\x10\x6a\x5f\x77\xd8\x6b\x51\x7b\x95\x79\xfe\xcb\x2d\x5c\xca\x59\x2f\x32\x87\xbb\xe4\xda\xf8\x1e\xeb\xc5\xbf\xec\xe7\x3e\xfd\x17\xe9\xbf\xa0\xb3\xeb\xe1\x5f\x60\x94\xf3\x80\xbc\x12\x80\xcf\xc4\x1e\x52\x5b\x72\x6f\x60\x74\x9f\x8b\xc4\xe5\xe9\x47\xa0\xd8\x23\xfe\x3f\x7c\xe6\xbf\x73\x26\x4b\xcc\x4d\x66\xf8\x8f\x79\x8f\x3c\x1f\xe9\x65\x99\x78\x3f\x72\x1a\xaf\x50\xaf\x44\xbd\x29\x6a\x7c\xe5\x7d\xab\xd4\xb6\x3c\x8b\xaf\x11\x5f\x21\xcf\xde\xb9\x4e\xcf\x4e\xc6\xf3\x13\xc6\x3e\xf3\xd6\xf3\x06\x73\x59\xcf\x4f\xe9\xd9\xc9\x78\x7e\x46\xdc\x7a\x2e\x33\x5f\xa2\xde\x65\xcf\xcf\xa9\x6d\x79\x16\xdf\x24\x5e\x26\x6f\x9f\xb3\xdf\x62\x3f\x9b\x19\x6c\x9b\xbd\x8b\xd6\x16\x3c\xdd\xf9\x16\xcf\x0b\xcc\x9f\xc1\xc1\x06\x1c\xfc\x1d\x00\x8c\x6d\xb6\x7f\xe8\x06\x00\x00")
+
+func shadersParticlesCompSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesCompSpv,
+		"shaders/particles-comp.spv",
+	)
+}
+
+func shadersParticlesCompSpv() (*asset, error) {
+	bytes, err := shadersParticlesCompSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles-comp.spv", size: 1768, mode: os.FileMode(420), modTime: time.Unix(1792149817, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesFragSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x90\x4f\x4b\xc3\x40\x10\xc5\x7f\xc9\x26\xad\xb6\xfe\x45\xd0\x9b\x54\xbc\x97\x22\x55\x04\x51\x50\xc1\x5c\x7a\xd2\x0f\xb0\xac\x49\x48\xab\x35\x29\x49\xfa\x05\xfd\x54\x5e\x04\x99\xc9\x16\xec\xe6\xb0\x99\xf7\xde\xec\xcc\x7b\x26\xbc\xec\x43\x80\x9c\x7d\xba\x73\x4c\xa8\xc8\x90\x9e\xde\xc9\xec\x6d\x36\x6e\xda\x6c\x3c\xbd\x9e\x88\xe0\x00\x23\x97\x72\x87\xf4\x89\x80\x10\xf8\x72\x8b\x52\x70\x61\x05\x3b\xc2\x28\xde\x57\xac\xfb\xff\x0e\x84\xdb\x25\x99\xd9\xc7\xd7\x27\xdb\xe4\x2b\x57\xbb\x36\xb7\xcd\xdc\x65\x79\x6d\xab\xf7\x8f\x3c\x6d\x9b\x6d\xcd\xdc\x65\x8b\xb2\xb0\x4b\x57\x16\x6b\x57\xe4\x76\x7a\x35\x59\xb9\xf4\x93\x98\x68\x6b\x6e\x4c\xac\x9b\xad\x5f\x6a\x57\x3c\x57\xcb\xaa\x46\x35\xb2\x4b\xda\x95\x24\x44\xaa\x39\xf7\x5e\x13\xcf\x6f\xea\x13\x42\x62\xe0\x02\x43\x4f\xdf\x84\x53\x8c\x7a\x18\x01\x67\x44\xec\x78\x4f\xd2\x37\xd2\x4d\xc5\x1f\x8a\xdf\xfd\xab\x8d\xe7\x07\x3e\xab\x0d\x3f\xf0\xbd\x92\xdf\x8d\x6e\xdd\xe5\x27\x9f\xcc\xfc\x21\x64\x08\xdc\xfb\x59\x7b\x5e\xff\xa0\x29\x76\xf5\x2f\x01\xb7\x04\xfc\x0d\x00\x27\xe2\x61\x94\xc0\x01\x00\x00")
+
+func shadersParticlesFragSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesFragSpv,
+		"shaders/particles-frag.spv",
+	)
+}
+
+func shadersParticlesFragSpv() (*asset, error) {
+	bytes, err := shadersParticlesFragSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles-frag.spv", size: 448, mode: os.FileMode(420), modTime: time.Unix(1792149817, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesVertSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5c\x53\x4d\x6f\xd3\x40\x10\x7d\xfe\x4c\x9b\x86\x86\x36\x4d\x5a\x28\x94\x94\x40\x39\x14\x45\x15\x2a\x08\x09\x51\x68\x2f\xe1\x90\x43\x44\x25\xae\xd6\x92\xac\x52\x43\xb0\x23\xdb\x20\xc4\x89\xdf\xc6\xb9\xfc\x1f\x2e\x48\x68\xa6\x6f\x2b\x17\x47\xab\xdd\x79\x33\xf3\xe6\xcd\xec\x26\xf0\x07\x0d\xc0\x83\x7c\x4f\x70\xf5\x6d\xc0\x57\x64\x0d\xb1\xee\xa3\xf1\xf9\x78\x58\x56\xb3\xe1\xf1\xf3\x23\x09\x58\x47\x20\x9b\xfa\xda\x58\x95\x23\x7c\x00\x5f\x4c\x9a\xc9\x59\xbc\x21\x80\x08\x40\xac\x76\xa0\xfe\x5f\x9e\xe0\xab\x18\x8d\x93\xd3\xf7\x67\x49\x69\x97\xa6\x30\x95\x4d\xca\x0b\x33\xb3\x45\x92\x7f\xfc\x64\xa7\x55\x79\x33\xe6\xc2\xcc\xd2\x6c\x9e\x2c\x4c\x36\xff\x6a\xe6\x36\x39\x7e\x76\xb4\x34\xd3\xcf\x88\x10\xde\xa8\x29\xb6\xd4\x9d\xe6\x8b\xbc\x50\x3b\x50\x0d\xdf\xec\x02\x11\x62\x34\x00\xcc\x17\xc9\xc4\x16\x1f\x6c\x51\xd9\xef\xa2\x33\x26\x0e\xfa\xf2\x32\xad\xd2\x3c\x53\xb4\xc1\xfe\x14\x4f\xb3\xea\x3c\xfd\x61\x41\xde\x88\x39\x72\x96\xfe\x96\x79\x89\x11\xeb\xef\xd1\x27\x76\x48\x5b\x78\xde\x21\xba\xae\xb5\xc6\xdd\x61\x1e\x31\xd9\x47\x08\x34\xce\x27\x47\x4c\x0e\xf9\x75\xe0\x63\x05\xc0\x3e\x02\x9d\xba\x9c\x7b\x08\xd0\x04\xd0\x07\xb0\x8d\x50\x79\x9a\x9c\x7f\x1f\x21\x5a\xbc\x0f\xc1\x5f\xd5\xec\x80\xf1\xb7\x18\x2f\xf6\x21\x42\x3d\xaf\x03\xf8\x7d\x39\x3e\x71\x76\x5b\xed\xcb\x6b\xfb\xb6\x88\xc1\xcf\x37\x4f\x11\x6b\xfe\x06\x73\xda\xf4\x39\x7c\x93\xb6\x5b\x52\xaf\xc3\x7a\x3e\xf5\x6d\xb1\xef\x0e\xf5\x6d\x51\xbb\x57\xd3\xd3\x65\xff\x7b\x08\x75\x36\xae\x47\xc9\xef\x51\x7b\x83\xf9\x3d\xbe\x3b\xe9\xa7\x8b\x10\xdb\x9c\x8d\xe3\x13\x7b\xe7\x3f\xfe\x3b\xca\x8e\xb7\xc2\x77\x97\xb9\xcd\x5a\xfc\x2e\xeb\x3b\x7d\x31\xf5\xbd\x40\xa4\xf7\xe1\xd3\x2f\x77\xf2\x07\x3e\xee\x01\x78\xcd\x5e\xef\xb3\x9f\x16\x62\x34\x6b\xef\xe1\x8c\xbe\x16\x56\x14\x7f\x40\xfc\x90\x31\x5d\xce\x6c\xc2\x59\xf6\x19\xe3\x96\xe4\xc9\x8c\xf7\x99\x37\xe4\x3d\x6c\x32\x76\x82\x48\xdf\xc2\x43\xc6\x08\xd7\x89\xfe\x13\xaf\xb0\x53\x44\xda\xeb\x80\xf3\xda\xa1\x7f\xc0\x79\x38\xfd\x8f\xd8\xaf\xe8\x10\xbe\xc7\xc4\x9c\x3e\xe1\x91\x37\x76\x40\x9e\x5d\xf2\x1c\x30\xf6\x2f\x3c\xbc\x84\x87\x7f\x03\x00\xd8\xc7\xdd\xdc\x68\x04\x00\x00")
+
+func shadersParticlesVertSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesVertSpv,
+		"shaders/particles-vert.spv",
+	)
+}
+
+func shadersParticlesVertSpv() (*asset, error) {
+	bytes, err := shadersParticlesVertSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles-vert.spv", size: 1128, mode: os.FileMode(420), modTime: time.Unix(1792149817, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesComp = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x91\x51\x6f\xd3\x30\x14\x85\xdf\xf3\x2b\x8e\xb4\x97\x16\xba\x2c\x1b\x85\x07\xa2\x4c\x02\x21\x4d\x93\x78\x40\xf0\x88\x26\xcb\x71\x6e\x5a\x83\x6b\x5b\xf6\x75\x68\x40\xfd\xef\x68\xce\x9a\xae\x68\xbc\xe5\xc6\xf7\x7c\xdf\x49\x7c\x31\x50\x88\xda\x59\xac\xdf\x56\xc5\x05\xed\x99\x6c\x1e\xef\x3e\x8b\x0f\x5f\x3f\x8a\x48\x5e\x06\xc9\x24\xe2\x56\x76\x14\x84\x6b\x7f\x90\xe2\x88\xf7\x20\x2b\x5b\x43\x2f\x45\xb6\xb2\xd3\x76\x23\x8c\xb4\x9b\x24\x37\x24\xd6\x37\x95\x97\xea\xe7\x29\x63\xe4\xe8\x12\x63\x61\x9c\x92\x46\x44\xfd\x9b\xc4\x1e\x0d\xde\xad\x97\xd0\xb6\x2e\x22\x87\xa4\x18\x5f\x64\x60\xad\x0c\xe1\x4f\x01\x60\x20\x75\x03\xef\x62\x3d\x0f\x03\x99\xba\x38\xd4\x33\x2d\x72\xb7\x7e\x53\xad\xd0\x6a\xfb\xe8\x47\x83\x6a\x89\x36\xf5\x3d\x85\x99\x15\x27\xd8\x8c\xf6\xc7\xf7\xdf\x1f\xce\x58\x3e\xc5\xad\x50\xce\x46\x96\x96\x97\x48\x56\xf7\x2e\xec\xf0\x8d\xc9\x4f\x84\xde\x38\xc9\xe8\x38\xd7\x49\xda\x32\x94\x4b\x96\x33\x64\x70\xba\xc3\x4e\x6a\xbb\x58\x4e\xcb\xf9\x5c\xa3\xc1\xc6\x88\x3b\xe3\x5a\x69\xee\xed\xe0\x94\x64\xed\xec\xfd\xa7\x72\x9f\x21\xba\xc7\x42\xe3\xb6\x99\x40\x4f\x49\x00\x81\x38\x05\x9b\x57\x0e\xe7\xdd\xd1\x3c\xeb\xaf\x1f\xf2\x8a\x2f\xbd\x8b\x78\xdd\xc0\x97\x03\x19\xbc\x3a\x56\xbc\xba\x42\xeb\x92\x55\x04\xd7\xf7\xe0\x2d\x81\xba\x0d\x45\xb8\x69\x18\x34\xfd\xf2\x2e\xf0\xb1\x88\x6c\xe3\x22\xa3\xca\xfd\x12\xb7\xb8\x2e\xab\x53\xa1\x4c\x2e\x1f\x2f\xec\xf2\xe9\xb1\x9e\x4f\x72\x02\x0d\x94\x91\x3b\x7f\x24\xac\x70\x79\x5d\x56\xab\x4c\x39\x7d\xc7\xb9\x66\xfc\x8f\x66\x3c\x69\xc6\x73\xcd\xf8\x8f\x66\x7c\x59\xf3\xfc\x0f\xa1\x81\xaf\x8b\x43\xf1\x77\x00\xba\x90\x40\xb9\xf4\x02\x00\x00")
+
+func shadersParticlesCompBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesComp,
+		"shaders/particles.comp",
+	)
+}
+
+func shadersParticlesComp() (*asset, error) {
+	bytes, err := shadersParticlesCompBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles.comp", size: 756, mode: os.FileMode(420), modTime: time.Unix(1792149817, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesFrag = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\xcb\xb1\xee\x82\x30\x10\xc7\xf1\xbd\x4f\x71\x09\x0b\x6c\xe4\x1f\xfe\x8b\x84\x41\x4d\x74\x71\xf2\x05\x9a\xa3\x5c\x4a\xb5\xde\x91\xb6\x10\x8d\xf1\xdd\x0d\x38\xe8\xa0\xe3\xdd\xef\xfb\xc9\x26\x0a\xd1\x09\x43\xf5\x5f\xaa\x8c\xae\x89\x78\x39\xf7\x07\xbd\x3e\x6e\x74\xa4\x01\x03\x26\xd2\xb1\xc7\x8e\x82\x96\xf6\x44\x26\x45\x58\x01\x31\xb6\x9e\xbe\x91\x1e\x3b\xc7\x56\x7b\x64\x3b\xa2\x25\x5d\xfd\x95\x03\x9a\xf3\xdb\x78\xbc\xc9\x98\x20\xf7\x62\x30\xcd\xb2\x81\xb2\x00\xc7\x30\x91\xa9\xc0\x88\x97\x50\xff\x88\xe6\xd7\x52\x8d\xbb\x80\x76\xfb\x4a\x27\x71\x1d\x5c\xd0\x71\x5e\xc0\x5d\x01\x7c\x8c\xd0\x80\x11\x2f\xa1\x56\x0f\xf5\x1c\x00\x84\x42\xba\xf7\xeb\x00\x00\x00")
+
+func shadersParticlesFragBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesFrag,
+		"shaders/particles.frag",
+	)
+}
+
+func shadersParticlesFrag() (*asset, error) {
+	bytes, err := shadersParticlesFragBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles.frag", size: 235, mode: os.FileMode(420), modTime: time.Unix(1792149817, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersParticlesVert = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8f\x31\x6f\xf3\x20\x10\x86\x77\x7e\xc5\x49\x59\xb0\x14\x39\xc4\xf1\xb7\x7c\x56\x86\x76\xe9\xd2\xa1\x6a\xa5\xae\xe8\x42\x2e\x36\x2d\xe1\x2c\x20\x28\x6d\x95\xff\x5e\x61\xab\x4a\x86\x4c\x70\xc7\xf3\xdc\x7b\x2c\x32\x85\x68\xd9\x43\xfb\x4f\x89\x05\x9d\x13\xf9\xa9\x7c\x7a\xd6\x0f\xaf\x8f\x3a\xd2\x88\x01\x13\xe9\x38\xe0\x9e\x82\xe6\xdd\x07\x99\x14\xe1\x3f\x90\xc7\x9d\xa3\x7b\xca\x80\x7b\xeb\x7b\xed\xd0\xf7\x27\xec\x49\xb7\x8d\x1a\xd1\x7c\x5e\x1d\x87\x5f\x7c\x4a\x20\x1d\x1b\x4c\xc5\xdc\x82\xaa\xc0\x7a\xc8\x64\x1a\x18\x39\x76\xf7\x90\xf5\x15\xc9\xe4\xee\x22\xaa\x82\x62\x65\x32\x2d\x18\x76\x1c\x3a\x51\xea\xde\xe9\x17\x0a\xef\x14\x12\x9d\xe1\x47\x00\x94\x29\xed\xd4\xe6\x68\x8b\xdb\x95\xe6\xc1\x31\xce\x30\x5b\x9f\xde\xec\x37\x75\xe2\xd2\x89\xcc\x76\x0f\x47\xb4\x5e\x56\xb3\xbc\x5a\xc1\x01\x63\xa2\x00\x23\x86\x64\x8d\xa3\x08\x18\x08\x76\xc1\xf6\x43\xa2\x50\x98\x29\x1d\xb6\x53\x90\x3c\xda\xb3\xcc\x64\x36\x52\xd5\xcd\x12\x54\xdd\x2e\x61\x5d\xab\x6a\x59\x5e\x37\x72\xbe\x1a\x87\xc7\x51\x3a\xf2\x7d\x1a\x64\x26\x57\x15\x50\xcd\x60\x35\x1f\xd3\x92\xb7\xeb\xc1\x16\x9a\x5a\x5d\xdb\xf3\x5f\xfe\x52\x47\x8e\x37\x33\x3a\x71\x11\xbf\x03\x00\x3c\x7b\x9b\x05\xec\x01\x00\x00")
+
+func shadersParticlesVertBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersParticlesVert,
+		"shaders/particles.vert",
+	)
+}
+
+func shadersParticlesVert() (*asset, error) {
+	bytes, err := shadersParticlesVertBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/particles.vert", size: 492, mode: os.FileMode(420), modTime: time.Unix(1792149817, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersPostFragSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x92\xcd\x4f\x13\x51\x14\xc5\x7f\xf3\xf1\x28\x15\x69\xf9\x50\x0a\xa2\x88\xba\xd4\x10\x62\xd0\x98\x18\x24\x4a\x62\x37\x4d\xfc\x5a\xb8\x9c\x3c\xdb\x49\x41\xb1\x43\x66\x4a\xe2\xd2\xc4\xbd\x2b\xff\x22\xff\x2a\x37\x26\xe6\x5e\x4e\x4d\x79\x9b\xf7\xce\xb9\xe7\x9e\x77\xee\x9b\xc9\xd2\x07\x2d\x48\xb0\x75\x97\xcb\xb5\x4a\xea\xcc\x12\x0b\xbe\xf7\x07\x1f\x06\x7b\xcd\x74\xb4\x77\xf0\x64\xdf\x04\x1d\x32\xdb\xbc\xd6\xa5\x45\x0e\xa4\xc0\xd7\x78\x3a\x31\xde\xaa\xc6\xad\x90\x39\xdf\x72\xee\xf2\xfc\x3b\xb1\x5a\x9b\xfe\xa0\x78\xf9\xfe\x55\xd1\x94\xe7\xb1\x8e\xd3\xb2\x68\x4e\xe2\xa8\xac\x8b\xea\xd3\xe7\x72\x38\x6d\xae\x6a\x4e\xe2\xe8\x74\x32\x2e\xce\xe2\x64\x7c\x11\xc7\x65\x71\xf0\x78\xff\x3c\x0e\xbf\x10\xc8\xaf\xdc\x6b\x38\x00\xcd\xb0\x9c\x94\x8e\x83\x27\x9d\x96\xdf\x8e\xab\xaa\x1e\x21\xce\xb2\x5d\xbc\xae\xe3\xf8\xb8\x3a\xab\x6a\xe8\xab\xef\xbe\xe6\x9f\xe1\x7b\x73\xd8\x7c\x76\xe6\x70\x3e\x87\xd7\x49\x59\x70\x7d\xe6\xb3\xda\x79\x83\x8c\x45\x60\x17\xe8\xf9\x34\x38\xb6\xbe\x2d\xda\x5c\x13\x4e\xe4\x31\x5b\x33\xbc\x4d\xc6\x12\xb8\x6e\x97\x9c\xeb\xe2\x8d\x7b\x2e\x1c\xc4\x99\xff\xb2\xfc\x52\xe9\x3b\xf2\x5a\x96\xbe\xa3\xef\x62\xfe\x0f\xc9\x5d\xdb\xb5\x66\x38\x7a\x44\x70\xdd\x8a\xb8\xee\x9c\x66\xd5\x14\x7c\x3f\x32\xcf\x35\x79\xb4\xe5\xb9\xa6\x79\x32\x65\x58\x57\x06\xc3\x4f\x09\xfe\x26\xa9\x32\xda\xbb\xfc\x21\xe5\x06\x70\x48\xee\xb3\xdd\xd4\x0c\x87\xca\xbf\xa1\xde\x8f\x04\x7f\xaf\x9e\x34\xc6\xff\x50\xc6\x4d\x61\xcb\xfa\x8b\xe0\xf7\x6d\x89\xdf\x94\xce\xb8\x5b\xca\x6e\xb5\x37\x2c\x7a\xb6\x6d\x79\xf6\x94\x29\x51\xbe\x9f\x04\xaf\xdf\x96\xc6\x7a\xdf\xc9\xe7\x8e\xf4\x36\xd3\x5b\xe5\xda\x91\xd6\x6a\x2f\xc8\xfe\xff\x0b\x7f\x49\x78\x46\xc2\xbf\x01\x00\xc3\xb1\x78\x23\x54\x03\x00\x00")
+
+func shadersPostFragSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersPostFragSpv,
+		"shaders/post-frag.spv",
+	)
+}
+
+func shadersPostFragSpv() (*asset, error) {
+	bytes, err := shadersPostFragSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/post-frag.spv", size: 852, mode: os.FileMode(420), modTime: time.Unix(1792147097, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersPostFrag = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x8e\xb1\x6e\xf2\x30\x14\x46\xf7\x3c\xc5\x27\xb1\x24\xbf\x20\xe4\x8f\xc2\xd2\x28\x43\x4b\xd5\x2e\x9d\xfa\x02\xd1\x8d\x7d\x09\x2e\xc6\x46\xb6\x13\x51\x55\x7d\xf7\xca\x06\xc1\xd2\x8e\x96\xcf\x39\xf7\x5b\xcc\xec\xbc\xb2\x06\xcd\xa6\xca\x16\x7c\x0e\x6c\xd2\xf3\xf5\xad\x7f\x7c\x7f\xea\x3d\x9f\xc8\x51\xe0\xde\xef\x49\xb2\xeb\xed\xf0\xc1\x22\x78\x3c\x80\x0d\x0d\x9a\x7f\x53\xf6\x24\x95\x19\x7b\x4d\x66\x9c\x68\xe4\xbe\xa9\xab\x13\x89\xc3\xdd\xd1\xf4\x69\xa7\x80\x7c\x50\x26\x92\xe8\x50\x15\x98\x8c\xda\x59\x77\x84\xa7\xe3\x49\xb3\xab\x9f\xe1\x05\x1b\x6e\x6f\xb4\xb6\x82\x42\xbc\x93\x70\x65\x30\xb3\xa8\x11\xf8\xbc\xb5\xd6\xc9\xbf\xb8\xa8\xce\x2c\x1a\x4c\x2f\x8e\xc6\xad\xd5\xd6\xb5\xd9\x6c\x95\xc4\x91\x94\xc9\x0b\x7c\x65\x40\x4c\x35\x10\xf1\x13\x5d\x4c\x86\xc9\x71\x9e\xee\x2f\x6f\x17\x8a\x36\x92\xeb\x35\x24\xb9\x03\x1b\x84\x3d\x43\x58\x67\xd8\x79\x10\x06\x15\xae\xa1\x1a\xf2\x12\x49\x16\x56\xa8\xca\x4d\x52\x77\xda\x52\xc0\xac\x46\xc3\x21\x30\x3a\xfc\x2f\x2b\xac\x20\x6d\xc8\xe5\x12\xd7\xfe\x7d\x26\xba\x34\x2b\x4f\xb3\x4a\x37\x0e\xf8\x77\x93\x97\x97\xb1\x25\x15\x6d\xf6\x9d\xfd\x0c\x00\xe5\xd3\x9a\xde\xc4\x01\x00\x00")
+
+func shadersPostFragBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersPostFrag,
+		"shaders/post.frag",
+	)
+}
+
+func shadersPostFrag() (*asset, error) {
+	bytes, err := shadersPostFragBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/post.frag", size: 452, mode: os.FileMode(420), modTime: time.Unix(1792147097, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersTriFragSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x8f\xd1\x4a\x3a\x41\x18\xc5\x7f\xb3\x33\xbb\xea\xdf\x7f\x5a\x04\x75\x17\x46\x97\x85\x48\x58\x04\x51\x52\x41\xde\x08\x41\x3d\xc0\x30\xe9\xb2\x5a\xa6\xb6\xab\xf7\x3d\x42\xaf\xd1\x53\xf5\x1a\x5d\x14\xc4\x7c\xcd\x5e\xb8\xb0\xcc\x9c\x73\xbe\x39\xe7\x7c\x3a\x3a\xa8\x80\x42\x51\xa5\xc9\xdf\xb7\x45\x84\x02\xea\x24\x72\xf6\x07\x0f\x83\x76\xb1\x1c\xb5\xbb\x27\x1d\xaf\x37\xd0\xfe\x10\xad\x49\x82\x01\xf9\x5f\xdc\x64\xe6\xf9\x1a\xb0\x89\x16\xae\x02\x68\x34\x11\xf0\xae\xfc\x5c\x8d\xfe\xc0\x5e\xdd\x5f\xdb\x22\x5d\xb8\xdc\x2d\x53\x5b\x8c\xdd\x28\xcd\xed\xfc\xf1\x29\x1d\x2e\x8b\xf5\x99\xb1\x1b\x4d\x66\x99\x9d\xba\x59\xb6\x72\x59\x6a\xbb\xc7\x9d\x85\x1b\x3e\x13\x63\xd6\x32\x63\x62\xc9\x5d\xdd\xe6\x2e\xbb\x99\x4f\xe7\x39\xf4\xc5\x09\xf6\xc2\x5e\xdb\x44\xd2\x63\x5f\x1a\x21\xf7\x1d\x34\x09\xd0\x02\x76\x31\xd2\x37\x09\xfb\xb4\x30\x54\xa5\x3f\xc2\x9f\x07\x5c\x0b\xdc\x21\x46\xde\xfe\xf3\xda\xe7\x5d\xaf\xc4\xf5\x90\x57\xe2\xff\xc0\xf7\xc7\xeb\x45\x89\x37\x44\x7d\xeb\x1d\x51\x11\xdf\x46\xf0\xa8\x87\x59\xaf\x9f\x12\x4b\x3f\x13\xbc\x7c\xde\x17\x11\x31\x70\x89\x96\xbd\xfc\xbb\x1f\x14\x67\x28\x7e\x07\x00\xbb\x64\x7d\x87\xc8\x01\x00\x00")
 
 func shadersTriFragSpvBytes() ([]byte, error) {
 	return bindataRead(
@@ -112,7 +389,47 @@ func shadersTriFragSpv() (*asset, error) {
 	return a, nil
 }
 
-var _shadersTriVertSpv = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x54\x52\x5d\x4b\xeb\x40\x10\x9d\x24\x6d\xd3\x8f\xdb\xde\xde\xdb\xf6\xde\x17\x11\xc5\x47\xa1\x14\xa9\x22\x88\x42\x55\xa8\x0f\x7d\x10\x0b\xbe\x86\xb5\x5d\xe2\x6a\x4d\x42\x12\x41\xfc\x15\xfe\x5c\x5f\x04\xcf\xec\x4e\xa0\x6e\xd8\xcc\xce\x39\x33\x73\x66\x96\x0d\xfc\x83\x90\xc8\xc3\xd7\xa4\x11\xb9\xf5\x87\x7c\xf8\x44\x1d\x6a\x58\x3b\x5f\x2c\x17\xe3\xa2\x5c\x8f\xa7\xc7\x13\xe6\x7b\x14\xd8\x38\xe6\x7e\x53\x68\xcf\x35\xec\x17\x65\x12\x3e\x77\x6d\x0d\x42\x54\x80\x4a\x44\x1f\x1e\xf3\x2d\xd4\x89\x66\x77\x97\x51\xa1\x33\x95\xab\x52\x47\xc5\xa3\x5a\xeb\x3c\x4a\x1f\x9e\xf4\xaa\x2c\x7e\xc6\x80\x32\x49\x1c\x6d\x54\x12\xbf\xaa\x58\x47\xd3\xa3\x49\xa6\x56\xcf\x54\x47\xd4\xb6\x56\x1d\x3d\x76\x60\xe3\x4d\x74\xab\xf3\x7b\x9d\x97\xfa\x8d\xf1\x86\xe0\x24\x5c\x5a\x98\xd2\xa4\x09\xd0\xd0\xe2\x5e\x85\x9b\xa4\x5c\x9a\x77\xed\x72\x1c\xe7\x3b\xee\x6a\x63\xb2\x6b\x53\x94\x2a\x59\x69\xe8\x04\x76\x2e\xa7\x19\xd8\xf9\xb2\xb4\xa0\x1b\x78\x95\x4e\x65\x2b\xcc\x13\xcc\xdb\xc2\x7c\xc1\xf8\x06\xe7\xf8\x57\xd8\x1c\x53\x71\xcd\x5d\xa9\x31\x00\xca\xf8\xbe\xbd\x45\x17\xf3\x0f\xa7\x06\xec\x1e\xf6\x7f\xc4\x87\xb6\x67\x77\xf7\x23\xfc\x9b\xc2\xf1\x3a\x14\xbf\x25\xfa\x3b\xf0\xdb\x12\xdf\xb2\x3a\xae\x9f\xaa\x46\xdb\xe6\xd6\xe8\x97\xf4\xc6\xdc\x99\xf8\x5d\xc1\x58\xa3\x27\x1a\x9e\x68\xf4\xec\x1b\x70\x8b\xf3\xfb\xc2\x85\x92\xdf\x97\xb7\xe0\x09\x3f\x90\x5a\xcc\x9f\xa0\x07\x5f\xfa\x27\xc1\x3f\x81\xd4\x61\xcf\x65\xbe\xbf\x92\x3f\x03\xca\xb9\x43\xe9\x87\x35\x2f\x90\x31\x94\x98\x2f\x28\x9c\x62\x7f\x07\x00\x00\xff\xff\xa4\x8d\xc5\x57\xd0\x02\x00\x00")
+var _shadersTriTescSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x94\xeb\x4e\xd4\x5e\x14\xc5\x57\xef\x94\x3b\x7f\xee\xfc\x61\xb8\x23\x8a\xc2\x80\x68\x4c\x0c\x46\x94\x64\x24\x99\x44\x23\xc6\xaf\x4d\x1d\x4e\x4a\xb5\x76\xc8\xb4\x43\x8c\x9f\x7c\x04\x9f\xc3\x17\xf4\x92\x98\x98\x7d\x66\x75\x52\x26\x03\x13\x48\x7b\xd6\x6f\x9f\xbd\xd7\x59\xed\x8c\x65\xae\x7b\x80\x01\xf9\x3c\x44\xe7\x33\x01\x13\x16\x80\x21\xb8\x9a\xd4\xea\x67\xf5\xdd\x2c\x3f\xdf\x3d\x7c\x54\x95\x82\x51\x4d\x3b\xbb\xc6\x30\xa8\xaf\x26\x80\xcf\x61\x9c\x0a\x10\x6a\x03\x70\x00\xb8\x00\x3c\x00\xe3\xb0\x75\xcd\x02\xb9\x05\x4b\xaf\xbf\x1b\x52\xeb\xa3\x56\x0f\x8e\xdf\xbe\x08\x32\x75\x19\xb6\xc2\x5c\x05\xd9\x45\x78\xae\x5a\x41\xf3\xc3\x47\xd5\xc8\xb3\xeb\x35\x17\xe1\x79\x9c\x46\x41\x12\xa6\x51\x3b\x8c\x54\x70\x78\x50\xbd\x0c\x1b\x9f\xe0\xc0\xbe\xe6\xc3\x81\x8b\x01\x00\x51\x12\xbc\x51\xad\xf7\xaa\x95\xab\x2f\xe2\xcf\xa5\x0e\xb2\x66\x16\xe7\x71\x33\x85\x0b\x4f\xeb\x46\xa1\xc7\x69\x7e\x16\x7f\x55\x52\x57\x30\xb3\xc3\x5e\x26\xf1\xe5\x49\x9c\xe5\x61\xda\x50\x7a\xae\x9c\x39\x4a\x82\x66\x3b\x97\x73\xbb\xfa\xfc\x51\x12\x9c\xa6\x57\xcd\x46\x28\xdd\x4f\x4f\xb4\xee\xdf\xe0\xc7\xbf\xc1\x8f\x7f\x8b\x1f\xff\x16\x3f\x4e\xc7\x8f\x7e\x20\x0e\x3c\xfd\x1c\xa2\x24\x78\xa7\xb2\xac\xae\xae\x54\x72\x9a\xa6\xaa\x45\xe6\xf5\xb0\xd7\xed\x5c\xb3\x57\x70\xba\x39\x0d\xf1\x5a\x68\x06\x35\xa3\xa4\x99\xd4\x24\x8b\x1a\xac\xae\x56\x83\xad\xf3\x10\x36\xc0\x7a\xbf\x4f\x5f\xbf\x4f\x5f\xbf\x4f\xdf\x42\x93\x7b\x39\xd7\x18\x67\xb8\x9c\x31\x4c\xe6\x95\x98\x47\x26\xff\x93\x30\x31\x08\x60\x15\x96\x5e\xcb\xfd\x0c\x2c\xbd\x6f\x05\xc0\x2c\x6c\x8c\xb0\x8f\xf8\x9e\x86\x8d\x51\x32\xf9\xdb\xe1\x7a\x8c\x3e\x17\x61\x63\x9c\xf5\xa2\x55\x98\x47\xd1\x63\xbc\xb4\x67\x82\xe7\x90\x3d\xff\x31\x0f\xd1\x56\x60\x63\x92\x4c\xf4\xa7\xa5\xb5\x45\x0f\x53\xf4\x60\xb0\x7e\x9a\xf7\x53\xac\x9f\xa6\x5f\x83\xf3\x44\x9f\xa1\xe7\x0a\xb3\xec\xe7\x69\x96\x7d\xc5\xd3\x1c\x00\xbf\xab\xd9\x98\x67\xbf\x39\xce\x98\xe7\xf7\xba\xf0\xb0\x40\x0f\x23\x5c\xff\x4f\xbf\xb2\x9e\x82\x89\xc5\xd2\x9c\x25\x3e\x37\x99\x53\xa1\x8f\x25\xee\x5b\xe6\xbe\x0a\xe7\x2c\xf3\xb7\xc3\xe2\xfe\xe2\xd9\x00\xdf\x9e\x4b\xfd\x2a\xd9\x70\xa9\xff\x1a\xcf\x2f\xfd\xd7\xc9\xd6\xd8\x7f\x83\xf5\xeb\xec\xbf\xc1\xdf\xa5\xa2\xbf\x64\xb5\xd9\x93\xdd\x16\xfd\x3e\x86\xa3\xdf\x17\x93\x59\xca\x3b\xf3\x0b\x26\xee\x00\x38\x62\xed\x36\x67\x1f\xc3\xd5\x99\xdc\x65\x4e\xdb\x7c\x06\x47\x7c\xa7\xee\x91\x49\x9d\x64\xb5\x43\x0f\x45\xdd\x33\x58\xd8\x61\xdd\x0f\x38\x3a\xbf\xfb\x25\xfe\x13\x16\x1e\xd0\xc7\x1f\xd8\x9a\xed\x02\x5a\x13\x4f\x72\x7f\x0c\x47\xe7\xb3\xc7\x0c\x8b\xbe\x7b\xcc\xb0\xe0\x55\x66\x50\xf0\x6a\x0f\xdf\x27\xdf\x24\xdf\xef\xe1\x07\xe4\x5b\xe4\x07\xe4\xbf\x61\x76\xfd\xc8\xf5\x2f\x0c\x3c\x81\x81\x7f\x03\x00\xa3\xe1\x47\xe1\x6c\x06\x00\x00")
+
+func shadersTriTescSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersTriTescSpv,
+		"shaders/tri-tesc.spv",
+	)
+}
+
+func shadersTriTescSpv() (*asset, error) {
+	bytes, err := shadersTriTescSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/tri-tesc.spv", size: 1644, mode: os.FileMode(420), modTime: time.Unix(1792147665, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersTriTeseSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x53\xdd\x6e\xd3\x4c\x14\x9c\xf5\xda\xde\xfe\x7c\xfd\xff\x92\xb4\xd0\x86\x96\x02\x05\x82\xaa\x0a\x15\x84\x84\x40\x0a\x45\x0a\x17\xb9\x00\x8a\xb8\xb5\x4c\xb2\x72\x0d\xc1\x89\x62\x23\x21\xee\x78\x01\xc4\xe3\x72\x83\x84\xce\x66\x5c\xdc\x28\xc1\x4a\xa2\xdd\x99\xd9\x73\x66\xce\x3a\xda\x3b\x34\x80\x82\x3c\x0f\x30\x79\x36\xe0\x41\x03\x58\x46\xe8\x98\x4e\xf7\xbc\x7b\x9c\x17\xfd\xe3\xd3\x47\x27\x22\x58\x75\xec\xe4\xd4\x1a\x16\xe0\x01\xee\xfb\x39\x4e\x33\x21\x84\xf5\x01\x04\x00\xd6\xa1\x1d\x57\xaf\xac\x55\x65\x2d\x3a\xcd\xf5\x4f\x25\xfb\x45\x74\xba\x51\xfb\xed\x8b\x28\xb7\xa3\x78\x1c\x17\x36\xca\x2f\xe2\xbe\x1d\x47\xc3\x0f\x1f\x6d\xaf\xc8\xaf\x6a\x2e\xe2\x7e\x9a\x25\xd1\x20\xce\x92\x2f\x71\x62\xa3\xd3\x87\x27\xa3\xb8\xf7\x09\x01\xfc\x2b\x9e\x02\x84\x08\x01\x24\x83\xe8\xb5\x1d\xbf\xb7\xe3\xc2\x7e\x15\xaf\x21\x71\x90\x1b\xe6\x69\x91\x0e\x33\x84\x30\x4e\xaf\x4a\x3c\xcd\x8a\xf3\xf4\x9b\x15\x5d\xc9\x79\x13\xee\x6c\x90\x8e\x5e\xa6\x79\x11\x67\x3d\x8b\x00\x9a\xd3\x91\xfc\xa1\xcb\x97\x0c\xa2\x77\x36\xcf\xcf\x86\xc3\x71\xbf\xc4\xcd\x1c\x2f\x66\x8e\x17\xf3\x0f\x2f\x66\xae\x17\xdf\xdd\x41\x32\x88\xdc\xc5\xbc\x72\x8e\xe4\x94\xdc\xad\xfc\xfe\xc5\x14\x31\x55\xc1\x3c\x62\x92\xa7\x03\x7d\x89\x75\xe0\xbb\x5c\xc2\xad\x50\x6f\x66\xd4\x35\x33\xea\x9a\x19\x75\x4b\x6c\x0b\x1e\x16\x00\x1c\x40\x63\x11\x70\xeb\x3a\x34\x96\x00\xec\x03\x68\xc0\x77\xbe\x97\xf8\xde\xd4\xe0\xe3\x3f\x72\xf2\x69\x71\xbf\xc2\x7e\xbb\xf0\xb1\x4a\xbd\x60\x4d\xe6\x2a\x6b\x08\xb7\x0f\x1f\x6b\xf4\x22\xf9\x9e\x56\xf6\x9a\x3d\xd6\xd9\x43\xb1\x87\xec\x37\xd8\x53\x3c\x6d\xb2\x9e\x66\xbd\x2d\x6a\x37\x59\x6f\x8b\x7e\x15\x3d\x98\x29\x0f\xa5\xef\xff\xd9\x47\x7c\xd7\x00\x98\x4b\xcc\x47\x9d\xe7\x6b\xac\x59\xe7\xff\x4b\x91\x6f\x70\xbd\x5c\xf1\xb8\x3d\xe5\x79\x87\x73\x16\xfd\x35\xfa\x15\xfd\x63\x04\x6e\xd6\x1e\x33\xc9\xec\x7f\xc1\xc3\x75\x00\xcf\x98\x6f\x97\x19\xde\x20\x70\x59\xf7\x88\xa1\x82\x35\x89\xa9\x0a\x76\x83\x98\xd4\x6e\x23\x44\x83\x19\x03\xce\x70\x83\x3d\xc4\xc7\x01\xb9\x52\x77\x93\xba\xed\x29\xdd\x21\xb9\x52\x77\x8b\xba\x9d\x29\xdd\x6d\x72\x3f\x10\xb8\xf7\xe6\x0e\x7b\xec\x55\xb0\x23\xd6\x6b\x56\xb0\xbb\x3c\x2b\xde\xbf\x13\xbb\xc7\xf3\x47\x15\xec\x3e\x71\xd1\xb7\x11\xb8\x99\xb6\x38\x57\xf1\xf1\x1c\x1a\x2d\xea\x7e\x43\xe1\x09\x14\xfe\x0c\x00\x63\x59\x7d\x83\x70\x05\x00\x00")
+
+func shadersTriTeseSpvBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersTriTeseSpv,
+		"shaders/tri-tese.spv",
+	)
+}
+
+func shadersTriTeseSpv() (*asset, error) {
+	bytes, err := shadersTriTeseSpvBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/tri-tese.spv", size: 1392, mode: os.FileMode(420), modTime: time.Unix(1792147665, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersTriVertSpv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x92\xd1\x6b\xd4\x40\x10\xc6\x7f\x9b\xdd\xe4\x5a\xeb\xb5\xd5\xb6\xfa\x22\xa5\xe2\xa3\x50\x8a\x54\x11\x44\xa1\x2a\xc4\x87\x7b\x10\x0b\xbe\x86\xf5\x6e\x49\x57\xcf\x24\x24\x11\xc4\xbf\xc2\x3f\xd7\x17\x41\x66\x6f\x52\xbc\xe3\x60\x67\xbe\x6f\xbe\xf9\x66\x86\xd8\xec\xc9\x0c\x0c\x86\x1d\x4e\xd8\xfc\xee\x91\x61\x80\x3d\x8a\xf4\x96\x8b\xeb\xc5\xf9\x30\xae\xce\x2f\x9f\x5f\x08\xbf\x8f\x95\x27\x71\x07\xcc\x24\xc4\x01\xdf\x7d\x6c\x24\x9e\xa7\x1e\x60\xb1\x64\xc0\x6f\x03\x8e\x5d\xca\x45\x75\xf5\xe9\x6d\x35\x84\xce\xf7\x7e\x0c\xd5\x70\xe3\x57\xa1\xaf\xda\x2f\x5f\xc3\x72\x1c\xb6\x6b\x6e\xfc\x2a\x36\x75\xb5\xf6\x4d\xfd\xc3\xd7\xa1\xba\x7c\x76\xd1\xf9\xe5\x37\x72\xdc\x96\x57\x4e\xc1\x1e\x50\xaf\xab\x8f\xa1\xff\x1c\xfa\x31\xfc\x94\x19\x0a\xc5\x51\xae\x1d\xe2\x18\xdb\x86\x82\x59\xc2\xcd\x84\xc7\x66\xbc\x8e\xbf\x82\xd4\x4d\x5c\xb6\xe1\xde\xad\x63\xf7\x3e\x0e\xa3\x6f\x96\x81\x1c\xcb\x5c\xfb\x49\x2c\xfb\x75\xed\xc0\x07\xf2\x5b\x9f\xe9\x9d\x30\xa3\x98\xf9\x0f\xcb\x14\x93\x0b\x96\xd8\x5b\xac\xc4\xa5\x9b\x9d\x6a\x8f\x23\xb2\x84\x3f\x4e\x57\xdc\xe8\x1e\x60\x29\x80\x33\xe0\x21\x2e\x5d\xbe\xd0\xdb\x9f\xe0\xd8\x51\x4e\xfe\x4f\x35\xdf\x55\xff\x47\x38\xee\x68\xbd\x60\xa7\x3a\xcf\xd4\x43\xb8\x33\x1c\x77\x75\x36\x99\xeb\x95\xe6\x73\xc5\xc4\x63\x5f\x3d\x8c\x7a\x48\x7e\xa0\x9e\xa2\x3f\x54\x6e\xa6\xfa\x43\xfd\x16\x8c\xf2\x47\xda\x4b\xf8\x17\xe4\x69\x2f\xa7\x7a\xf1\xf8\x43\x46\x0e\xbc\xd6\xfd\xee\xab\xfe\x8a\x3c\x69\x8f\x75\x1e\xf1\x7c\x83\xe5\x58\x6b\xfe\x62\x78\x89\xe1\xdf\x00\xa4\x8d\xc5\x57\xd0\x02\x00\x00")
 
 func shadersTriVertSpvBytes() ([]byte, error) {
 	return bindataRead(
@@ -132,7 +449,7 @@ func shadersTriVertSpv() (*asset, error) {
 	return a, nil
 }
 
-var _shadersTriFrag = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x6c\x92\x5f\x6b\xdb\x3c\x14\xc6\xef\xf3\x29\x1e\xd2\x9b\x14\xf2\xda\x6e\xe8\x3b\xb6\x96\x5d\xb8\xe9\x9f\x99\x95\x04\xe2\x74\xa5\x57\x46\x91\x4f\x1c\x6d\x8e\xe4\x49\x72\xdc\x30\xf6\xdd\x77\x94\xb8\xb4\x65\x33\x02\x23\x9d\xe7\x3c\xfa\x9d\xa3\x13\xc7\x98\x9a\x66\x6f\x55\xb5\xf1\x98\x24\x67\x1f\x70\x67\x4c\x55\x13\x32\x2d\x23\xa4\x75\x8d\x45\x08\x39\x2c\xc8\x91\xdd\x51\x19\x0d\xe2\x98\x17\xee\x95\x24\xed\xa8\x44\xab\x4b\xb2\xf0\x1b\x42\xda\x08\xc9\xbf\x3e\x32\xc6\x37\xb2\x4e\x19\x8d\x49\x94\x60\x14\x04\xc3\x3e\x34\x3c\xbd\x0c\x16\x7b\xd3\x62\x2b\xf6\xd0\xc6\xa3\x75\xc4\x1e\xca\x61\xad\xf8\x72\x7a\x96\xd4\x78\x28\x0d\x69\xb6\x4d\xad\x84\x96\x84\x4e\xf9\xcd\xe1\x9e\xde\x25\x90\xe0\xa9\xf7\x30\x2b\x2f\x58\x2e\x38\xa1\xe1\xdd\xfa\xad\x10\xc2\xf7\xd0\xe1\xdb\x78\xdf\x5c\xc4\x71\xd7\x75\x91\x38\x00\x47\xc6\x56\x71\x7d\x94\xba\xf8\x3e\x9b\xde\xcc\xf2\x9b\xff\x18\xba\x4f\x7a\xd0\x35\x39\x07\x4b\x3f\x5b\x65\xb9\xe0\xd5\x1e\xa2\x61\x28\x29\x56\x8c\x5a\x8b\x0e\xc6\x42\x54\x96\x38\xe6\x4d\x80\xee\xac\xf2\x4a\x57\x63\x38\xb3\xf6\x9d\xb0\x14\x6c\x4a\xe5\xbc\x55\xab\xd6\xbf\xeb\xd9\x0b\x22\x57\xfe\x56\xc0\x5d\x13\x1a\xc3\x34\x47\x96\x0f\x71\x95\xe6\x59\x3e\x0e\x26\x8f\xd9\xf2\xcb\xfc\x61\x89\xc7\x74\xb1\x48\x67\xcb\xec\x26\xc7\x7c\x81\xe9\x7c\x76\x9d\x2d\xb3\xf9\x8c\x77\xb7\x48\x67\x4f\xf8\x9a\xcd\xae\xc7\x20\xee\x18\xdf\x43\xcf\x8d\x0d\x15\x30\xa6\x0a\xdd\x3c\x3e\x22\x72\xa2\x77\x08\x6b\x73\x44\x72\x0d\x49\xb5\x56\x92\x4b\xd3\x55\x2b\x2a\x42\x65\x76\x64\x35\x57\x84\x86\xec\x56\xb9\xf0\xaa\x8e\x01\xcb\x60\x53\xab\xad\xf2\xc2\x1f\x8e\xfe\xaa\x2b\x1a\x9c\xec\xfa\x29\x38\x4f\x92\xc1\x09\x3d\x7b\x3e\x0e\xdb\xbb\xfb\x22\x5d\x5c\x15\x8e\x1a\x61\x85\xa7\xc2\x6d\x04\xe7\x16\x66\xf5\x9d\x24\x8f\xdb\x05\x48\x87\xfe\xfe\x2b\x85\x95\xcc\x52\xbc\xe0\x15\xe7\x93\x84\x1f\xf2\xc7\x6b\x4e\x2d\x78\xb4\x3c\x46\xb5\x91\x07\x30\x7c\x46\x72\x8a\x70\xb4\x23\x79\x8e\xf6\xd6\x8a\x6a\x6a\x6a\x63\x2f\x07\x3b\xa3\x4a\x1e\x20\xa5\x47\xa7\xf8\x35\xe0\xf1\x78\x0d\x72\x56\x90\x8f\x92\xe8\xe3\xd9\x64\x8c\x84\x57\x94\xfc\xff\x69\x8c\xb3\x28\xe1\x01\xfe\x3d\xf8\x13\x00\x00\xff\xff\x06\x48\xb5\x12\x3c\x03\x00\x00")
+var _shadersTriFrag = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x92\x4f\x6f\xda\x40\x10\xc5\xef\xfe\x14\x4f\xe4\x02\x92\x6b\x3b\x28\xad\xda\x44\x39\x38\xe4\x4f\xad\x46\x20\x61\xd2\x28\x27\xb4\xac\x07\x7b\xda\x65\xd7\xdd\x5d\xe3\xa0\xaa\xdf\xbd\x5a\x42\x94\xa0\xf6\xe8\x79\x6f\xc6\xbf\x99\xb7\x69\x8a\x89\x69\x77\x96\xeb\xc6\x63\x9c\x9d\x7e\xc2\x9d\x31\xb5\x22\x14\x5a\x26\xc8\x95\xc2\x3c\x48\x0e\x73\x72\x64\xb7\x54\x25\x51\x9a\x46\x69\x8a\x7b\x96\xa4\x1d\x55\xe8\x74\x45\x16\xbe\x21\xe4\xad\x90\x0d\xbd\x2a\x31\xbe\x93\x75\x6c\x34\xc6\x49\x86\x61\x30\x0c\x0e\xd2\x60\x74\x11\x46\xec\x4c\x87\x8d\xd8\x41\x1b\x8f\xce\x11\x7c\xc3\x0e\x6b\x56\x04\x7a\x96\xd4\x7a\xb0\x86\x34\x9b\x56\xb1\xd0\x92\xd0\xb3\x6f\xe0\xdf\x7e\x10\x48\xf0\x74\x98\x61\x56\x5e\xb0\x86\x80\x34\xed\x0e\x66\xfd\xde\x08\xe1\x0f\xd0\x00\xd0\x78\xdf\x9e\xa7\x69\xdf\xf7\x89\xd8\x03\x27\xc6\xd6\xa9\x7a\xb1\xba\xf4\xbe\x98\xdc\x4c\xcb\x9b\x0f\xe3\x24\x3b\x34\x3d\x68\x45\xce\xc1\xd2\xaf\x8e\x2d\x55\x58\xed\x20\xda\x56\xb1\x14\x2b\x45\x50\xa2\x87\xb1\x10\xb5\x25\xaa\xe0\x4d\x80\xee\x2d\x7b\xd6\x75\x0c\x67\xd6\xbe\x17\x96\x02\x69\xc5\xce\x5b\x5e\x75\xfe\xe8\x66\xaf\x88\xec\x8e\x0c\x46\x43\x68\x0c\xf2\x12\x45\x39\xc0\x55\x5e\x16\x65\x1c\x86\x3c\x16\x8b\xaf\xb3\x87\x05\x1e\xf3\xf9\x3c\x9f\x2e\x8a\x9b\x12\xb3\x39\x26\xb3\xe9\x75\xb1\x28\x66\xd3\x12\xb3\x5b\xe4\xd3\x27\x7c\x2b\xa6\xd7\x31\x88\x7d\x43\x16\xf4\xdc\xda\xb0\x81\xb1\xe0\x70\xcd\x97\x10\x51\x12\x1d\x21\xac\xcd\x4b\x8c\xae\x25\xc9\x6b\x96\x50\x42\xd7\x9d\xa8\x09\xb5\xd9\x92\xd5\xac\x6b\xb4\x64\x37\xec\x42\xaa\x0e\x42\x57\x01\x49\xf1\x86\xbd\xf0\xfb\xd2\x3f\x7b\x25\xd1\xc9\xf6\xf0\x0a\xce\xb2\x2c\x3a\xa1\x67\x4f\x7a\xff\x79\x77\xbf\xcc\xe7\x57\x4b\x47\xad\xb0\xc2\xd3\xd2\x35\xa2\x22\xbb\x34\xab\x1f\x24\xbd\xc3\x39\x48\x87\xfb\xfe\xaf\xa5\x11\x15\xeb\x7a\xf9\x8a\xb7\x3c\x1b\x67\xad\x90\x3f\xdf\x7a\x94\xd8\x99\xce\x63\xa8\x8c\xdc\x83\xe1\x12\xd9\x08\xa1\xb4\x25\x79\x86\xee\xd6\x8a\x7a\x62\x94\xb1\x17\xd1\xd6\x70\x85\x8d\x60\x3d\x1c\xe1\x77\x04\xbc\x13\x71\xb9\xb7\x0f\xb3\xe4\xf3\xe9\x38\x46\x16\x23\x4b\xb2\x8f\x5f\x62\x9c\x26\xd9\xe8\x22\xfa\x13\xfd\x1d\x00\x06\x48\xb5\x12\x3c\x03\x00\x00")
 
 func shadersTriFragBytes() ([]byte, error) {
 	return bindataRead(
@@ -152,7 +469,47 @@ func shadersTriFrag() (*asset, error) {
 	return a, nil
 }
 
-var _shadersTriVert = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x6c\x92\xdf\x6f\xda\x30\x10\xc7\xdf\xf3\x57\x9c\xe8\x4b\x91\x58\x92\x21\xb4\x87\x56\x7b\x48\x29\xeb\xa2\xa1\x30\x25\x74\x55\x9f\x22\xe3\x1c\xc1\x9b\xb1\x3d\xdb\x21\xa0\x69\xff\xfb\xce\x90\x6a\xad\x36\x84\x14\xd9\xf7\xbd\xaf\x3f\xf7\x23\x49\x60\xae\xcd\xc9\x8a\x76\xe7\x61\x9a\xbe\xff\x00\x0f\x5a\xb7\x12\x21\x57\x3c\x86\x4c\x4a\x28\x43\xc8\x41\x89\x0e\xed\x01\x9b\x38\x4a\x12\xfa\xc3\x52\x70\x54\x0e\x1b\xe8\x54\x83\x16\xfc\x0e\x21\x33\x8c\xd3\x67\x88\x4c\xe0\x1b\x5a\x27\xb4\x82\x69\x9c\xc2\x75\x10\x8c\x86\xd0\x68\x7c\x1b\x2c\x4e\xba\x83\x3d\x3b\x81\xd2\x1e\x3a\x87\xe4\x21\x1c\x6c\x05\x3d\x8e\x47\x8e\xc6\x83\x50\xc0\xf5\xde\x48\xc1\x14\x47\xe8\x85\xdf\x9d\xdf\x19\x5c\x02\x09\x3c\x0f\x1e\x7a\xe3\x19\xc9\x19\x25\x18\x3a\x6d\x5f\x0b\x81\xf9\x01\x3a\xfc\x76\xde\x9b\x9b\x24\xe9\xfb\x3e\x66\x67\xe0\x58\xdb\x36\x91\x17\xa9\x4b\x96\xf9\x7c\x51\x54\x8b\x77\x04\x3d\x24\x3d\x2a\x89\xce\x81\xc5\x9f\x9d\xb0\x54\xf0\xe6\x04\xcc\x10\x14\x67\x1b\x42\x95\xac\x07\x6d\x81\xb5\x16\x29\xe6\x75\x80\xee\xad\xf0\x42\xb5\x13\x70\x7a\xeb\x7b\x66\x31\xd8\x34\xc2\x79\x2b\x36\x9d\x7f\xd3\xb3\x17\x44\xaa\xfc\xb5\x80\xba\xc6\x14\x8c\xb2\x0a\xf2\x6a\x04\x77\x59\x95\x57\x93\x60\xf2\x94\xaf\x3f\xaf\x1e\xd7\xf0\x94\x95\x65\x56\xac\xf3\x45\x05\xab\x12\xe6\xab\xe2\x3e\x5f\xe7\xab\x82\x4e\x9f\x20\x2b\x9e\xe1\x4b\x5e\xdc\x4f\x00\xa9\x63\xf4\x0e\x1e\x8d\x0d\x15\x10\xa6\x08\xdd\xbc\x0c\x11\x2a\xc4\x37\x08\x5b\x7d\x41\x72\x06\xb9\xd8\x0a\x4e\xa5\xa9\xb6\x63\x2d\x42\xab\x0f\x68\x15\x55\x04\x06\xed\x5e\xb8\x30\x55\x47\x80\x4d\xb0\x91\x62\x2f\x3c\xf3\xe7\xab\x7f\xea\x8a\xa3\xab\xc3\xb0\x05\xb3\x34\x8d\xae\xf0\xe8\xe9\x3a\x1c\x1f\x96\x75\x56\xde\xd5\x0e\x0d\xb3\xcc\x63\xed\x76\x8c\x72\x6b\xbd\xf9\x8e\x9c\xd6\xed\x06\x50\x85\xfe\xfe\x2f\x85\x94\xc4\x52\xbf\xe0\xd5\xb3\x69\x4a\x83\xfc\xf1\x37\x47\x32\x5a\x2d\x0f\xd7\x52\xf3\x33\x18\x7c\x84\x74\x1c\x06\x73\x40\x3e\x03\xa3\xdd\x6d\x74\xd0\xa2\xa1\xc5\x11\xea\x7a\x0c\xbf\x22\x5a\x8b\x56\xd6\x5f\xb5\x13\x83\xfc\xac\xf9\x1d\xfd\x09\x00\x00\xff\xff\x29\xec\xb2\xf5\x1e\x03\x00\x00")
+var _shadersTriTesc = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\xcd\x41\x4b\x03\x31\x10\x05\xe0\x7b\x7e\xc5\x40\x2f\xdb\x4b\x89\x75\x4f\x2e\x39\x28\x82\x2c\x14\x14\xf1\x56\x4a\x98\xee\x8e\x69\x34\x4e\x4a\x32\x09\x8a\xf4\xbf\xcb\xaa\xa0\x48\x51\x8f\x0f\xbe\xf7\xde\xac\x52\xca\x3e\x32\xb4\x5a\xab\x19\x3d\x0b\xf1\x7b\xbc\x5a\xd9\xf3\xdb\x0b\x9b\x69\x8f\x09\x85\x6c\xde\xe1\x48\xc9\xc6\xed\x03\x0d\x92\xe1\x0c\x88\x71\x1b\xe8\x58\x65\x87\xa3\x67\x67\x03\xb2\x2b\xe8\xc8\xb6\x4b\xbd\xc7\xe1\xf1\xab\x13\xf0\x25\x16\x81\xa6\x52\x12\x3f\x50\x06\x03\xa7\x73\x88\x45\x3a\x55\xa3\x1f\xe1\x09\x3d\x37\x73\x78\x55\x00\xe0\x82\x8d\x45\xd6\x2e\xd8\x9e\x6b\x1c\x50\x7c\xe4\xfe\x72\xb3\x70\xc1\xde\xc4\xec\xa7\x08\x66\x52\x9e\x7f\x45\xdd\x34\xe6\xef\xa1\xf9\x81\xc0\x18\xd0\x9f\x5f\x1f\x77\x77\x94\xf3\x8a\x2a\x85\x9e\x99\xd2\x5a\x6f\xc0\x40\xbb\xd0\xdd\x11\x71\x5d\xe4\x5f\xe2\xe4\x4f\xb1\xfc\x2e\x0e\xea\xa0\xde\x06\x00\xe2\x9b\xed\xff\x98\x01\x00\x00")
+
+func shadersTriTescBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersTriTesc,
+		"shaders/tri.tesc",
+	)
+}
+
+func shadersTriTesc() (*asset, error) {
+	bytes, err := shadersTriTescBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/tri.tesc", size: 408, mode: os.FileMode(420), modTime: time.Unix(1792147665, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersTriTese = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\xcd\x31\x4b\x43\x41\x0c\xc0\xf1\xfd\x3e\x45\xa0\x4b\xab\xa5\x3c\x4b\x27\x8b\x83\x3a\xb8\x38\x88\xb8\x89\x84\xf4\x5d\xb8\x46\xcf\xdc\xf3\x72\xaf\xb6\x8a\xdf\x5d\xce\xe1\xe9\x03\x97\x66\xfb\x87\xfc\xc8\x64\xc7\xd9\x24\x29\xac\x9a\xc6\x4d\x78\x5f\x58\x7f\xf2\xe6\x16\x2f\xef\xaf\xd0\xb8\xa3\x4c\x85\xd1\xb6\xe4\x39\x63\xda\x3c\x73\x5b\x0c\xce\x81\x95\x36\x91\xff\x23\x5b\xf2\xa2\x01\x23\x69\xe8\x29\x30\xae\x96\x4d\x47\xed\xcb\xaf\x89\x74\x48\x7d\x81\x69\xc9\x42\x1a\x22\xdb\x1c\xf8\xad\xa7\x88\xd6\x51\x2b\x1a\xe6\xd0\xbe\xcf\x40\x74\xed\x76\x49\x3c\xbc\x92\xe8\x74\x06\x9f\x0e\x00\x42\xc4\xbb\x64\x52\xea\xbf\x8b\x5a\x0f\x6c\x76\x9d\x52\xf6\x8b\x3d\x9c\xd4\x85\xe8\x63\xf3\xb4\xf8\x7b\x77\x5a\xe1\x78\x46\xf0\x30\xc0\xb3\xe3\xe0\xc7\x00\x97\x23\xb8\x76\x5f\xee\x7b\x00\x62\x94\x8d\xf5\x58\x01\x00\x00")
+
+func shadersTriTeseBytes() ([]byte, error) {
+	return bindataRead(
+		_shadersTriTese,
+		"shaders/tri.tese",
+	)
+}
+
+func shadersTriTese() (*asset, error) {
+	bytes, err := shadersTriTeseBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "shaders/tri.tese", size: 344, mode: os.FileMode(420), modTime: time.Unix(1792147665, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _shadersTriVert = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x92\xcd\x6e\xdb\x30\x10\x84\xef\x7a\x8a\x81\x73\x49\x00\x57\x52\x8d\xa0\x87\x04\x3d\x28\x3f\x4d\x85\x06\x76\x61\x39\x0d\x72\x12\x68\x6a\x2d\x6d\x4b\x93\x2c\x49\x59\x31\x8a\xbe\x7b\x41\x47\x41\x6a\xb4\x47\x72\x67\x97\xdf\xce\x30\xcb\x70\x6d\xec\xde\x71\xdb\x05\xcc\xf2\xf7\x1f\x70\x67\x4c\xab\x08\xa5\x96\x29\x0a\xa5\xb0\x8c\x25\x8f\x25\x79\x72\x3b\x6a\xd2\x24\xcb\x92\x2c\xc3\x3d\x4b\xd2\x9e\x1a\xf4\xba\x21\x87\xd0\x11\x0a\x2b\x64\x47\xaf\x95\x29\xbe\x91\xf3\x6c\x34\x66\x69\x8e\xd3\x28\x98\x8c\xa5\xc9\xd9\x65\x1c\xb1\x37\x3d\xb6\x62\x0f\x6d\x02\x7a\x4f\x08\x1d\x7b\x6c\x58\x11\xe8\x59\x92\x0d\x60\x0d\x69\xb6\x56\xb1\xd0\x92\x30\x70\xe8\x10\xde\x1e\x88\x24\x78\x1a\x67\x98\x75\x10\xac\x21\x20\x8d\xdd\xc3\x6c\xfe\x16\x42\x84\x11\x1a\x00\xba\x10\xec\x45\x96\x0d\xc3\x90\x8a\x03\x70\x6a\x5c\x9b\xa9\x17\xa9\xcf\xee\xcb\xeb\xdb\x79\x75\xfb\x6e\x96\xe6\x63\xd3\x83\x56\xe4\x3d\x1c\xfd\xec\xd9\x51\x83\xf5\x1e\xc2\x5a\xc5\x52\xac\x15\x41\x89\x01\xc6\x41\xb4\x8e\xa8\x41\x30\x11\x7a\x70\x1c\x58\xb7\x53\x78\xb3\x09\x83\x70\x14\x49\x1b\xf6\xc1\xf1\xba\x0f\x47\x9e\xbd\x22\xb2\x3f\x12\x18\x0d\xa1\x31\x29\x2a\x94\xd5\x04\x57\x45\x55\x56\xd3\x38\xe4\xb1\x5c\x7d\x5e\x3c\xac\xf0\x58\x2c\x97\xc5\x7c\x55\xde\x56\x58\x2c\x71\xbd\x98\xdf\x94\xab\x72\x31\xaf\xb0\xf8\x84\x62\xfe\x84\x2f\xe5\xfc\x66\x0a\xe2\xd0\x91\x03\x3d\x5b\x17\x37\x30\x0e\x1c\xdd\x7c\x09\x11\x15\xd1\x11\xc2\xc6\xbc\xc4\xe8\x2d\x49\xde\xb0\x84\x12\xba\xed\x45\x4b\x68\xcd\x8e\x9c\x66\xdd\xc2\x92\xdb\xb2\x8f\xa9\x7a\x08\xdd\x44\x24\xc5\x5b\x0e\x22\x1c\xae\xfe\xd9\x2b\x4d\x4e\x76\xe3\x2f\x38\xcf\xf3\xe4\x84\x9e\x03\xe9\xc3\xf1\xee\xbe\x2e\x96\x57\xb5\x27\x2b\x9c\x08\x54\xfb\x4e\x34\xe4\x6a\xb3\xfe\x4e\x32\x78\x5c\x80\x74\xf4\xf7\x7f\x2d\x9d\x68\x58\xb7\xf5\x2b\x5e\x7d\x3e\xcb\xad\x90\x3f\xde\x7a\x94\xd8\x9b\x3e\xe0\x54\x19\x79\x00\xc3\x47\xe4\x67\x31\x98\x1d\xc9\x73\x58\xe3\x2f\x93\x9d\xe1\x06\x5b\xc1\xfa\xf4\x0c\xbf\x12\x00\xad\xaa\xbf\x1a\xcf\xa3\xdc\x1a\x7f\x99\xfc\x4e\xfe\x0c\x00\x29\xec\xb2\xf5\x1e\x03\x00\x00")
 
 func shadersTriVertBytes() ([]byte, error) {
 	return bindataRead(
@@ -224,10 +581,27 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"shaders/.DS_Store": shadersDs_store,
+	"shaders/fullscreen-vert.spv": shadersFullscreenVertSpv,
+	"shaders/fullscreen.vert": shadersFullscreenVert,
+	"shaders/outline-frag.spv": shadersOutlineFragSpv,
+	"shaders/outline-vert.spv": shadersOutlineVertSpv,
+	"shaders/outline.frag": shadersOutlineFrag,
+	"shaders/outline.vert": shadersOutlineVert,
+	"shaders/particles-comp.spv": shadersParticlesCompSpv,
+	"shaders/particles-frag.spv": shadersParticlesFragSpv,
+	"shaders/particles-vert.spv": shadersParticlesVertSpv,
+	"shaders/particles.comp": shadersParticlesComp,
+	"shaders/particles.frag": shadersParticlesFrag,
+	"shaders/particles.vert": shadersParticlesVert,
+	"shaders/post-frag.spv": shadersPostFragSpv,
+	"shaders/post.frag": shadersPostFrag,
 	"shaders/tri-frag.spv": shadersTriFragSpv,
+	"shaders/tri-tesc.spv": shadersTriTescSpv,
+	"shaders/tri-tese.spv": shadersTriTeseSpv,
 	"shaders/tri-vert.spv": shadersTriVertSpv,
 	"shaders/tri.frag": shadersTriFrag,
+	"shaders/tri.tesc": shadersTriTesc,
+	"shaders/tri.tese": shadersTriTese,
 	"shaders/tri.vert": shadersTriVert,
 }
 
@@ -272,10 +646,27 @@ type bintree struct {
 }
 var _bintree = &bintree{nil, map[string]*bintree{
 	"shaders": &bintree{nil, map[string]*bintree{
-		".DS_Store": &bintree{shadersDs_store, map[string]*bintree{}},
+		"fullscreen-vert.spv": &bintree{shadersFullscreenVertSpv, map[string]*bintree{}},
+		"fullscreen.vert": &bintree{shadersFullscreenVert, map[string]*bintree{}},
+		"outline-frag.spv": &bintree{shadersOutlineFragSpv, map[string]*bintree{}},
+		"outline-vert.spv": &bintree{shadersOutlineVertSpv, map[string]*bintree{}},
+		"outline.frag": &bintree{shadersOutlineFrag, map[string]*bintree{}},
+		"outline.vert": &bintree{shadersOutlineVert, map[string]*bintree{}},
+		"particles-comp.spv": &bintree{shadersParticlesCompSpv, map[string]*bintree{}},
+		"particles-frag.spv": &bintree{shadersParticlesFragSpv, map[string]*bintree{}},
+		"particles-vert.spv": &bintree{shadersParticlesVertSpv, map[string]*bintree{}},
+		"particles.comp": &bintree{shadersParticlesComp, map[string]*bintree{}},
+		"particles.frag": &bintree{shadersParticlesFrag, map[string]*bintree{}},
+		"particles.vert": &bintree{shadersParticlesVert, map[string]*bintree{}},
+		"post-frag.spv": &bintree{shadersPostFragSpv, map[string]*bintree{}},
+		"post.frag": &bintree{shadersPostFrag, map[string]*bintree{}},
 		"tri-frag.spv": &bintree{shadersTriFragSpv, map[string]*bintree{}},
+		"tri-tesc.spv": &bintree{shadersTriTescSpv, map[string]*bintree{}},
+		"tri-tese.spv": &bintree{shadersTriTeseSpv, map[string]*bintree{}},
 		"tri-vert.spv": &bintree{shadersTriVertSpv, map[string]*bintree{}},
 		"tri.frag": &bintree{shadersTriFrag, map[string]*bintree{}},
+		"tri.tesc": &bintree{shadersTriTesc, map[string]*bintree{}},
+		"tri.tese": &bintree{shadersTriTese, map[string]*bintree{}},
 		"tri.vert": &bintree{shadersTriVert, map[string]*bintree{}},
 	}},
 }}
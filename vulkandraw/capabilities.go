@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// DeviceCapabilities is what NewVulkanDeviceAndroid learned about the chosen GPU,
+// so callers don't need to query it again.
+type DeviceCapabilities struct {
+	Properties vk.PhysicalDeviceProperties
+	Limits     vk.PhysicalDeviceLimits
+
+	SupportedFeatures vk.PhysicalDeviceFeatures
+	EnabledFeatures   vk.PhysicalDeviceFeatures
+
+	// Extensions are the device extensions available.
+	Extensions []string
+	// QueueFamilies maps each created queue to its family index.
+	QueueFamilies map[QueuePurpose]uint32
+	// Headless is true when VK_KHR_swapchain is missing.
+	Headless bool
+}
+
+// HasExtension reports whether the device extension is available.
+func (c *DeviceCapabilities) HasExtension(name string) bool {
+	return hasExtension(c.Extensions, name)
+}
+
+// sampleCounts lists the sample count bits from the highest, their values are the counts.
+var sampleCounts = []vk.SampleCountFlagBits{
+	vk.SampleCount64Bit, vk.SampleCount32Bit, vk.SampleCount16Bit,
+	vk.SampleCount8Bit, vk.SampleCount4Bit, vk.SampleCount2Bit, vk.SampleCount1Bit,
+}
+
+// sampleCountsString decodes sample count flags into the supported counts, e.g. "1, 2, 4, 8".
+func sampleCountsString(flags vk.SampleCountFlags) string {
+	var counts []string
+	for i := len(sampleCounts) - 1; i >= 0; i-- {
+		if flags&vk.SampleCountFlags(sampleCounts[i]) != 0 {
+			counts = append(counts, strconv.Itoa(int(sampleCounts[i])))
+		}
+	}
+	if len(counts) == 0 {
+		return "NONE"
+	}
+	return strings.Join(counts, ", ")
+}
+
+// MaxColorSampleCount returns the highest sample count usable for color attachments, e.g. for MSAA.
+func (c *DeviceCapabilities) MaxColorSampleCount() vk.SampleCountFlagBits {
+	counts := c.Limits.FramebufferColorSampleCounts
+	for _, samples := range sampleCounts {
+		if counts&vk.SampleCountFlags(samples) != 0 {
+			return samples
+		}
+	}
+	return vk.SampleCount1Bit
+}
+
+// ClampSampleCount returns the highest sample count up to requested that color attachments,
+// and depth ones too when withDepth is set, support. MSAA falls back to it when
+// the requested count isn't available.
+func (c *DeviceCapabilities) ClampSampleCount(requested vk.SampleCountFlagBits,
+	withDepth bool) vk.SampleCountFlagBits {
+
+	counts := c.Limits.FramebufferColorSampleCounts
+	if withDepth {
+		counts &= c.Limits.FramebufferDepthSampleCounts
+	}
+	for _, samples := range sampleCounts {
+		if samples <= requested && counts&vk.SampleCountFlags(samples) != 0 {
+			if samples != requested {
+				log.Printf("[WARN] %d samples unsupported, using %d of %s",
+					requested, samples, sampleCountsString(counts))
+			}
+			return samples
+		}
+	}
+	return vk.SampleCount1Bit
+}
+
+// BufferOffsetAlignment returns the alignment the offsets of uniform or storage buffers,
+// dynamic offsets included, must have when bound to descriptors of the type. Sub-allocations
+// from a shared buffer must be placed at multiples of it, see alignUp. Other types get 1.
+func (c *DeviceCapabilities) BufferOffsetAlignment(descriptorType vk.DescriptorType) vk.DeviceSize {
+	switch descriptorType {
+	case vk.DescriptorTypeUniformBuffer, vk.DescriptorTypeUniformBufferDynamic:
+		return c.Limits.MinUniformBufferOffsetAlignment
+	case vk.DescriptorTypeStorageBuffer, vk.DescriptorTypeStorageBufferDynamic:
+		return c.Limits.MinStorageBufferOffsetAlignment
+	default:
+		return 1
+	}
+}
+
+// ValidateBufferOffsets checks the offsets of buffers bound to descriptors of the type
+// against BufferOffsetAlignment, e.g. the dynamic offsets of vk.CmdBindDescriptorSets.
+func (c *DeviceCapabilities) ValidateBufferOffsets(descriptorType vk.DescriptorType,
+	offsets ...vk.DeviceSize) error {
+
+	alignment := c.BufferOffsetAlignment(descriptorType)
+	for i, offset := range offsets {
+		if offset != alignUp(offset, alignment) {
+			return fmt.Errorf("offset %d of buffer %d isn't a multiple of %d, the alignment"+
+				" of descriptor type %d, the next valid offset is %d",
+				offset, i, alignment, descriptorType, alignUp(offset, alignment))
+		}
+	}
+	return nil
+}
+
+// Capabilities returns the capabilities of the chosen GPU.
+func (v *VulkanDeviceInfo) Capabilities() DeviceCapabilities {
+	return v.caps
+}
+
+func queryCapabilities(gpu vk.PhysicalDevice) DeviceCapabilities {
+	var caps DeviceCapabilities
+	vk.GetPhysicalDeviceProperties(gpu, &caps.Properties)
+	caps.Properties.Deref()
+	caps.Properties.Limits.Deref()
+	caps.Limits = caps.Properties.Limits
+	vk.GetPhysicalDeviceFeatures(gpu, &caps.SupportedFeatures)
+	caps.SupportedFeatures.Deref()
+	caps.Extensions = getDeviceExtensions(gpu)
+	return caps
+}
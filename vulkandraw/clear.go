@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// ClearRegion clears rect of the color attachment to color with vk.CmdClearAttachments,
+// unlike the load op clear it can be recorded at any point within the render pass,
+// e.g. to clear an inset after the scene is drawn. The rect must lie within renderArea,
+// the render area of the active render pass.
+func ClearRegion(cmd vk.CommandBuffer, renderArea vk.Rect2D, attachment uint32,
+	color []float32, rect vk.Rect2D) error {
+
+	if !rectContains(renderArea, rect) {
+		return fmt.Errorf("ClearRegion: rect %dx%d at (%d, %d) is outside of the render area %dx%d at (%d, %d)",
+			rect.Extent.Width, rect.Extent.Height, rect.Offset.X, rect.Offset.Y,
+			renderArea.Extent.Width, renderArea.Extent.Height, renderArea.Offset.X, renderArea.Offset.Y)
+	}
+	if rect.Extent.Width == 0 || rect.Extent.Height == 0 {
+		return fmt.Errorf("ClearRegion: rect is empty")
+	}
+	clearAttachments := []vk.ClearAttachment{{
+		AspectMask:      vk.ImageAspectFlags(vk.ImageAspectColorBit),
+		ColorAttachment: attachment,
+		ClearValue:      vk.NewClearValue(color),
+	}}
+	clearRects := []vk.ClearRect{{
+		Rect:           rect,
+		BaseArrayLayer: 0,
+		LayerCount:     1,
+	}}
+	vk.CmdClearAttachments(cmd, 1, clearAttachments, 1, clearRects)
+	return nil
+}
+
+// rectContains reports whether inner lies within outer.
+func rectContains(outer, inner vk.Rect2D) bool {
+	if inner.Offset.X < outer.Offset.X || inner.Offset.Y < outer.Offset.Y {
+		return false
+	}
+	innerRight := int64(inner.Offset.X) + int64(inner.Extent.Width)
+	innerBottom := int64(inner.Offset.Y) + int64(inner.Extent.Height)
+	outerRight := int64(outer.Offset.X) + int64(outer.Extent.Width)
+	outerBottom := int64(outer.Offset.Y) + int64(outer.Extent.Height)
+	return innerRight <= outerRight && innerBottom <= outerBottom
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// UploadPool is the transient command pool for one time uploads, e.g. of textures
+// and staging buffers, kept apart from the pool of the render command buffers.
+func (r *VulkanRenderInfo) UploadPool() vk.CommandPool {
+	return r.uploadPool
+}
+
+// ResetUploadPool recycles the memory of the upload command buffers at once,
+// no upload may be pending.
+func (r *VulkanRenderInfo) ResetUploadPool() error {
+	err := vk.Error(vk.ResetCommandPool(r.device, r.uploadPool, 0))
+	if err != nil {
+		err = fmt.Errorf("vk.ResetCommandPool failed with %s", err)
+		return err
+	}
+	return nil
+}
+
+// beginSingleTimeCommands allocates a command buffer from the pool and begins it for
+// a one time submission, finish it with endSingleTimeCommands. The pool is usually
+// the upload pool of the renderer, see UploadPool.
+func beginSingleTimeCommands(device vk.Device, pool vk.CommandPool) (vk.CommandBuffer, error) {
+	cmdBuffers := make([]vk.CommandBuffer, 1)
+	cmdBufferAllocateInfo := vk.CommandBufferAllocateInfo{
+		SType:              vk.StructureTypeCommandBufferAllocateInfo,
+		CommandPool:        pool,
+		Level:              vk.CommandBufferLevelPrimary,
+		CommandBufferCount: 1,
+	}
+	err := vk.Error(vk.AllocateCommandBuffers(device, &cmdBufferAllocateInfo, cmdBuffers))
+	if err != nil {
+		err = fmt.Errorf("vk.AllocateCommandBuffers failed with %s", err)
+		return nil, err
+	}
+	cmdBufferBeginInfo := vk.CommandBufferBeginInfo{
+		SType: vk.StructureTypeCommandBufferBeginInfo,
+		Flags: vk.CommandBufferUsageFlags(vk.CommandBufferUsageOneTimeSubmitBit),
+	}
+	err = vk.Error(vk.BeginCommandBuffer(cmdBuffers[0], &cmdBufferBeginInfo))
+	if err != nil {
+		vk.FreeCommandBuffers(device, pool, 1, cmdBuffers)
+		err = fmt.Errorf("vk.BeginCommandBuffer failed with %s", err)
+		return nil, err
+	}
+	return cmdBuffers[0], nil
+}
+
+// endSingleTimeCommands ends the command buffer, submits it to the queue and waits
+// for it to complete on a fence of the device pool. The command buffer is freed, unless
+// the submission may still be pending after an error, it's leaked with the fence then.
+func endSingleTimeCommands(v *VulkanDeviceInfo, pool vk.CommandPool,
+	queue vk.Queue, cmd vk.CommandBuffer) error {
+
+	cmdBuffers := []vk.CommandBuffer{cmd}
+	pending := false
+	defer func() {
+		if !pending {
+			vk.FreeCommandBuffers(v.device, pool, 1, cmdBuffers)
+		}
+	}()
+
+	err := vk.Error(vk.EndCommandBuffer(cmd))
+	if err != nil {
+		err = fmt.Errorf("vk.EndCommandBuffer failed with %s", err)
+		return err
+	}
+	fence, err := v.fences.Acquire()
+	if err != nil {
+		return err
+	}
+
+	submitInfo := []vk.SubmitInfo{{
+		SType:              vk.StructureTypeSubmitInfo,
+		CommandBufferCount: 1,
+		PCommandBuffers:    cmdBuffers,
+	}}
+	err = vk.Error(vk.QueueSubmit(queue, 1, submitInfo, fence))
+	if err != nil {
+		v.fences.Release(fence)
+		err = fmt.Errorf("vk.QueueSubmit failed with %s", err)
+		return err
+	}
+	err = vk.Error(vk.WaitForFences(v.device, 1, []vk.Fence{fence}, vk.True, vk.MaxUint64))
+	if err != nil {
+		err = fmt.Errorf("vk.WaitForFences failed with %s", err)
+		// the submission may still be pending, neither the fence nor
+		// the command buffer can go before the queue is idle
+		if vk.Error(vk.QueueWaitIdle(queue)) != nil {
+			pending = true
+			return err
+		}
+		v.fences.Release(fence)
+		return err
+	}
+	return v.fences.Release(fence)
+}
+
+// SubmitBatch holds command buffers that execute in order within a single vk.SubmitInfo,
+// e.g. a shadow pass followed by the main pass. Each wait semaphore comes with
+// the pipeline stages that wait on it.
+type SubmitBatch struct {
+	CommandBuffers   []vk.CommandBuffer
+	WaitSemaphores   []vk.Semaphore
+	WaitStages       []vk.PipelineStageFlags
+	SignalSemaphores []vk.Semaphore
+}
+
+func (b *SubmitBatch) submitInfo() (vk.SubmitInfo, error) {
+	if len(b.WaitStages) != len(b.WaitSemaphores) {
+		err := fmt.Errorf("%d wait stages for %d wait semaphores",
+			len(b.WaitStages), len(b.WaitSemaphores))
+		return vk.SubmitInfo{}, err
+	}
+	return vk.SubmitInfo{
+		SType:                vk.StructureTypeSubmitInfo,
+		WaitSemaphoreCount:   uint32(len(b.WaitSemaphores)),
+		PWaitSemaphores:      b.WaitSemaphores,
+		PWaitDstStageMask:    b.WaitStages,
+		CommandBufferCount:   uint32(len(b.CommandBuffers)),
+		PCommandBuffers:      b.CommandBuffers,
+		SignalSemaphoreCount: uint32(len(b.SignalSemaphores)),
+		PSignalSemaphores:    b.SignalSemaphores,
+	}, nil
+}
+
+func submitInfos(batches []SubmitBatch) ([]vk.SubmitInfo, error) {
+	infos := make([]vk.SubmitInfo, len(batches))
+	for i := range batches {
+		info, err := batches[i].submitInfo()
+		if err != nil {
+			err = fmt.Errorf("submit batch %d: %s", i, err)
+			return nil, err
+		}
+		infos[i] = info
+	}
+	return infos, nil
+}
+
+// SubmitBatches submits all batches to the queue with a single vk.QueueSubmit,
+// the fence, which may be vk.NullHandle, gets signaled once all of them complete.
+func SubmitBatches(queue vk.Queue, fence vk.Fence, batches ...SubmitBatch) error {
+	infos, err := submitInfos(batches)
+	if err != nil {
+		return err
+	}
+	err = vk.Error(vk.QueueSubmit(queue, uint32(len(infos)), infos, fence))
+	if err != nil {
+		err = fmt.Errorf("vk.QueueSubmit failed with %s", err)
+		return err
+	}
+	return nil
+}
+
+// AddPrePass submits the command buffers of a pass, one per frame in flight, ahead
+// of the swapchain ones in the same vk.QueueSubmit of VulkanDrawFrame, e.g. for
+// a shadow pass. The pass of a frame gets resubmitted once the frame completes.
+func (r *VulkanRenderInfo) AddPrePass(cmdBuffers []vk.CommandBuffer) error {
+	if len(cmdBuffers) != maxFramesInFlight {
+		err := fmt.Errorf("AddPrePass: %d command buffers for %d frames in flight",
+			len(cmdBuffers), maxFramesInFlight)
+		return err
+	}
+	r.prePasses = append(r.prePasses, cmdBuffers)
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"runtime/cgo"
+	"unsafe"
+)
+
+// debugUserData passes a Go value to the debug report callback as its pUserData.
+// The driver holds on to the pointer, and C must not keep Go pointers, so
+// the value goes through a cgo.Handle stored in C memory instead.
+type debugUserData struct {
+	handle cgo.Handle
+	ptr    *C.uintptr_t
+}
+
+func newDebugUserData(value interface{}) *debugUserData {
+	d := &debugUserData{
+		handle: cgo.NewHandle(value),
+		ptr:    (*C.uintptr_t)(C.malloc(C.size_t(unsafe.Sizeof(C.uintptr_t(0))))),
+	}
+	*d.ptr = C.uintptr_t(d.handle)
+	return d
+}
+
+// pointer returns the pUserData of vk.DebugReportCallbackCreateInfo, nil for no value.
+func (d *debugUserData) pointer() unsafe.Pointer {
+	if d == nil {
+		return nil
+	}
+	return unsafe.Pointer(d.ptr)
+}
+
+// Free releases the handle and its memory, once the callback got destroyed.
+func (d *debugUserData) Free() {
+	if d == nil {
+		return
+	}
+	d.handle.Delete()
+	C.free(unsafe.Pointer(d.ptr))
+}
+
+// DebugUserData returns the value given as VulkanDeviceOptions.DebugUserData
+// from the pUserData of the debug report callback, nil when none was given.
+func DebugUserData(pUserData unsafe.Pointer) interface{} {
+	if pUserData == nil {
+		return nil
+	}
+	return cgo.Handle(*(*C.uintptr_t)(pUserData)).Value()
+}
@@ -0,0 +1,145 @@
+package main
+
+/*
+#cgo LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdint.h>
+#include <stddef.h>
+#include <stdlib.h>
+
+// VK_EXT_debug_utils is missing from the bindings, so its entry points
+// are resolved by hand and called through these self-contained typedefs.
+
+#define VK_STRUCTURE_TYPE_DEBUG_UTILS_LABEL_EXT 1000128002
+
+typedef struct {
+	uint32_t    sType;
+	const void* pNext;
+	const char* pLabelName;
+	float       color[4];
+} debugUtilsLabel;
+
+typedef void* (*getInstanceProcAddrFn)(void* instance, const char* name);
+typedef void (*beginLabelFn)(void* handle, const debugUtilsLabel* label);
+typedef void (*endLabelFn)(void* handle);
+
+static void* instanceProcAddr(void* instance, const char* name) {
+	static getInstanceProcAddrFn getProcAddr = NULL;
+	if (getProcAddr == NULL) {
+		void* lib = dlopen("libvulkan.so", RTLD_NOW | RTLD_LOCAL);
+		if (lib == NULL) {
+			lib = dlopen("libvulkan.so.1", RTLD_NOW | RTLD_LOCAL);
+		}
+		if (lib == NULL) {
+			return NULL;
+		}
+		getProcAddr = (getInstanceProcAddrFn)dlsym(lib, "vkGetInstanceProcAddr");
+		if (getProcAddr == NULL) {
+			return NULL;
+		}
+	}
+	return getProcAddr(instance, name);
+}
+
+static void callBeginLabel(void* fn, void* handle, const char* name,
+	float r, float g, float b, float a) {
+
+	debugUtilsLabel label = {
+		VK_STRUCTURE_TYPE_DEBUG_UTILS_LABEL_EXT, NULL, name, {r, g, b, a},
+	};
+	((beginLabelFn)fn)(handle, &label);
+}
+
+static void callEndLabel(void* fn, void* handle) {
+	((endLabelFn)fn)(handle);
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// debugLabelFuncs holds the VK_EXT_debug_utils label entry points,
+// all nil when the extension is not enabled.
+type debugLabelFuncs struct {
+	cmdBegin   unsafe.Pointer
+	cmdEnd     unsafe.Pointer
+	queueBegin unsafe.Pointer
+	queueEnd   unsafe.Pointer
+}
+
+var debugLabels debugLabelFuncs
+
+// instanceProcAddr resolves an entry point missing from the bindings, nil when
+// the extension providing it is not enabled.
+func instanceProcAddr(instance vk.Instance, name string) unsafe.Pointer {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return C.instanceProcAddr(unsafe.Pointer(instance), cName)
+}
+
+// loadDebugLabels resolves the label functions, the instance must have been
+// created with VK_EXT_debug_utils enabled.
+func loadDebugLabels(instance vk.Instance) bool {
+	load := func(name string) unsafe.Pointer {
+		return instanceProcAddr(instance, name)
+	}
+	funcs := debugLabelFuncs{
+		cmdBegin:   load("vkCmdBeginDebugUtilsLabelEXT"),
+		cmdEnd:     load("vkCmdEndDebugUtilsLabelEXT"),
+		queueBegin: load("vkQueueBeginDebugUtilsLabelEXT"),
+		queueEnd:   load("vkQueueEndDebugUtilsLabelEXT"),
+	}
+	if funcs.cmdBegin == nil || funcs.cmdEnd == nil ||
+		funcs.queueBegin == nil || funcs.queueEnd == nil {
+		return false
+	}
+	debugLabels = funcs
+	return true
+}
+
+func unloadDebugLabels() {
+	debugLabels = debugLabelFuncs{}
+}
+
+// BeginLabel opens a labeled region in the command buffer, shown by capture tools
+// like RenderDoc or Nsight. It's a no-op without VK_EXT_debug_utils.
+func BeginLabel(cmd vk.CommandBuffer, name string, color [4]float32) {
+	if debugLabels.cmdBegin == nil {
+		return
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.callBeginLabel(debugLabels.cmdBegin, unsafe.Pointer(cmd), cName,
+		C.float(color[0]), C.float(color[1]), C.float(color[2]), C.float(color[3]))
+}
+
+// EndLabel closes the region opened by BeginLabel.
+func EndLabel(cmd vk.CommandBuffer) {
+	if debugLabels.cmdEnd == nil {
+		return
+	}
+	C.callEndLabel(debugLabels.cmdEnd, unsafe.Pointer(cmd))
+}
+
+// BeginQueueLabel opens a labeled region in the queue, it's a no-op without VK_EXT_debug_utils.
+func BeginQueueLabel(queue vk.Queue, name string, color [4]float32) {
+	if debugLabels.queueBegin == nil {
+		return
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.callBeginLabel(debugLabels.queueBegin, unsafe.Pointer(queue), cName,
+		C.float(color[0]), C.float(color[1]), C.float(color[2]), C.float(color[3]))
+}
+
+// EndQueueLabel closes the region opened by BeginQueueLabel.
+func EndQueueLabel(queue vk.Queue) {
+	if debugLabels.queueEnd == nil {
+		return
+	}
+	C.callEndLabel(debugLabels.queueEnd, unsafe.Pointer(queue))
+}
@@ -0,0 +1,130 @@
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+// VK_KHR_get_memory_requirements2 and VK_KHR_dedicated_allocation are missing from
+// the bindings, they're called through these self-contained typedefs. Image handles
+// are read through a pointer since they're pointers on 64-bit and uint64_t on 32-bit.
+
+#define VK_STRUCTURE_TYPE_MEMORY_DEDICATED_REQUIREMENTS 1000127000
+#define VK_STRUCTURE_TYPE_MEMORY_DEDICATED_ALLOCATE_INFO 1000127001
+#define VK_STRUCTURE_TYPE_IMAGE_MEMORY_REQUIREMENTS_INFO_2 1000146001
+#define VK_STRUCTURE_TYPE_MEMORY_REQUIREMENTS_2 1000146003
+
+typedef struct {
+	uint32_t    sType;
+	const void* pNext;
+	uint64_t    image;
+} imageMemoryRequirementsInfo2;
+
+typedef struct {
+	uint32_t sType;
+	void*    pNext;
+	uint32_t prefersDedicatedAllocation;
+	uint32_t requiresDedicatedAllocation;
+} memoryDedicatedRequirements;
+
+typedef struct {
+	uint64_t size;
+	uint64_t alignment;
+	uint32_t memoryTypeBits;
+} memoryRequirements;
+
+typedef struct {
+	uint32_t           sType;
+	void*              pNext;
+	memoryRequirements memoryRequirements;
+} memoryRequirements2;
+
+typedef struct {
+	uint32_t    sType;
+	const void* pNext;
+	uint64_t    image;
+	uint64_t    buffer;
+} memoryDedicatedAllocateInfo;
+
+typedef void (*getImageMemoryRequirements2Fn)(void* device,
+	const imageMemoryRequirementsInfo2* info, memoryRequirements2* reqs);
+
+static void callGetImageMemoryRequirements2(void* fn, void* device, const void* image,
+	uint64_t* size, uint32_t* prefers, uint32_t* requires) {
+
+	imageMemoryRequirementsInfo2 info = {
+		VK_STRUCTURE_TYPE_IMAGE_MEMORY_REQUIREMENTS_INFO_2, NULL, *(const uint64_t*)image,
+	};
+	memoryDedicatedRequirements dedicated = {
+		VK_STRUCTURE_TYPE_MEMORY_DEDICATED_REQUIREMENTS, NULL, 0, 0,
+	};
+	memoryRequirements2 reqs = {
+		VK_STRUCTURE_TYPE_MEMORY_REQUIREMENTS_2, &dedicated, {0, 0, 0},
+	};
+	((getImageMemoryRequirements2Fn)fn)(device, &info, &reqs);
+	*size = reqs.memoryRequirements.size;
+	*prefers = dedicated.prefersDedicatedAllocation;
+	*requires = dedicated.requiresDedicatedAllocation;
+}
+
+// the caller frees it once the memory is allocated
+static memoryDedicatedAllocateInfo* newDedicatedAllocateInfo(const void* image) {
+	memoryDedicatedAllocateInfo* info = calloc(1, sizeof(memoryDedicatedAllocateInfo));
+	if (info != NULL) {
+		info->sType = VK_STRUCTURE_TYPE_MEMORY_DEDICATED_ALLOCATE_INFO;
+		info->image = *(const uint64_t*)image;
+	}
+	return info;
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// dedicatedImageThreshold is the size from which render targets, e.g. offscreen, depth
+// and multisampled images, get their own allocation when VK_KHR_dedicated_allocation
+// is available, so the driver may place them better, e.g. for framebuffer compression.
+const dedicatedImageThreshold = 8 << 20 // 8 MiB
+
+// getImageMemoryRequirements2 is vkGetImageMemoryRequirements2KHR, nil unless both
+// VK_KHR_get_memory_requirements2 and VK_KHR_dedicated_allocation are enabled.
+var getImageMemoryRequirements2 unsafe.Pointer
+
+func loadDedicatedAllocation(instance vk.Instance) bool {
+	getImageMemoryRequirements2 = instanceProcAddr(instance, "vkGetImageMemoryRequirements2KHR")
+	return getImageMemoryRequirements2 != nil
+}
+
+func unloadDedicatedAllocation() {
+	getImageMemoryRequirements2 = nil
+}
+
+// dedicatedImage reports whether the image should get its own allocation: the driver
+// requires or prefers it, or it's at least threshold bytes large when threshold isn't 0.
+// It's always false without VK_KHR_dedicated_allocation.
+func dedicatedImage(device vk.Device, image vk.Image, threshold vk.DeviceSize) bool {
+	if getImageMemoryRequirements2 == nil {
+		return false
+	}
+	var size C.uint64_t
+	var prefers, requires C.uint32_t
+	C.callGetImageMemoryRequirements2(getImageMemoryRequirements2, unsafe.Pointer(device),
+		unsafe.Pointer(&image), &size, &prefers, &requires)
+	if requires == C.uint32_t(vk.True) || prefers == C.uint32_t(vk.True) {
+		return true
+	}
+	return threshold > 0 && vk.DeviceSize(size) >= threshold
+}
+
+// dedicatedAllocateInfo returns the vk.MemoryDedicatedAllocateInfo of the image to chain
+// to vk.MemoryAllocateInfo, it must be freed with freeDedicatedAllocateInfo.
+func dedicatedAllocateInfo(image vk.Image) unsafe.Pointer {
+	return unsafe.Pointer(C.newDedicatedAllocateInfo(unsafe.Pointer(&image)))
+}
+
+func freeDedicatedAllocateInfo(info unsafe.Pointer) {
+	C.free(info)
+}
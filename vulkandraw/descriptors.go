@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// DescriptorBinding describes a binding of a descriptor set layout. Stages are the shader
+// stages accessing it, e.g. vk.ShaderStageVertexBit|vk.ShaderStageFragmentBit for
+// a uniform buffer read by both, a stage that isn't listed reads undefined values.
+type DescriptorBinding struct {
+	Binding uint32
+	Type    vk.DescriptorType
+	Count   uint32 // number of descriptors of an array, 1 when 0
+	Stages  vk.ShaderStageFlagBits
+}
+
+// CreateDescriptorSetLayout creates a descriptor set layout with the bindings,
+// each must be accessed by at least one shader stage.
+func CreateDescriptorSetLayout(device vk.Device,
+	bindings []DescriptorBinding) (vk.DescriptorSetLayout, error) {
+
+	var layout vk.DescriptorSetLayout
+	layoutBindings := make([]vk.DescriptorSetLayoutBinding, len(bindings))
+	seen := make(map[uint32]bool, len(bindings))
+	for i, b := range bindings {
+		if b.Stages == 0 {
+			err := fmt.Errorf("CreateDescriptorSetLayout: binding %d is visible to no shader stage",
+				b.Binding)
+			return layout, err
+		}
+		if seen[b.Binding] {
+			err := fmt.Errorf("CreateDescriptorSetLayout: binding %d is described twice", b.Binding)
+			return layout, err
+		}
+		seen[b.Binding] = true
+		count := b.Count
+		if count == 0 {
+			count = 1
+		}
+		layoutBindings[i] = vk.DescriptorSetLayoutBinding{
+			Binding:         b.Binding,
+			DescriptorType:  b.Type,
+			DescriptorCount: count,
+			StageFlags:      vk.ShaderStageFlags(b.Stages),
+		}
+	}
+	descLayoutCreateInfo := vk.DescriptorSetLayoutCreateInfo{
+		SType:        vk.StructureTypeDescriptorSetLayoutCreateInfo,
+		BindingCount: uint32(len(layoutBindings)),
+		PBindings:    layoutBindings,
+	}
+	err := vk.Error(vk.CreateDescriptorSetLayout(device, &descLayoutCreateInfo, nil, &layout))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateDescriptorSetLayout failed with %s", err)
+		return layout, err
+	}
+	return layout, nil
+}
+
+// DescriptorPoolSizes sums the descriptors by type the sets need, setBindings holds
+// the bindings of each set layout, of which copies sets get allocated, e.g. one per
+// frame in flight. The types come in the order they first appear.
+func DescriptorPoolSizes(copies uint32,
+	setBindings ...[]DescriptorBinding) (sizes []vk.DescriptorPoolSize, maxSets uint32) {
+
+	index := make(map[vk.DescriptorType]int)
+	for _, bindings := range setBindings {
+		for _, b := range bindings {
+			count := b.Count
+			if count == 0 {
+				count = 1
+			}
+			i, ok := index[b.Type]
+			if !ok {
+				i = len(sizes)
+				index[b.Type] = i
+				sizes = append(sizes, vk.DescriptorPoolSize{Type: b.Type})
+			}
+			sizes[i].DescriptorCount += count * copies
+		}
+	}
+	return sizes, uint32(len(setBindings)) * copies
+}
+
+// CreateDescriptorPool creates a pool sized for copies of each set layout described
+// by setBindings, see DescriptorPoolSizes, so allocating them can't run out of pool memory.
+// With freeSets the sets may be freed one by one with vk.FreeDescriptorSets, otherwise
+// only the whole pool gets reset.
+func CreateDescriptorPool(device vk.Device, copies uint32, freeSets bool,
+	setBindings ...[]DescriptorBinding) (vk.DescriptorPool, error) {
+
+	var pool vk.DescriptorPool
+	poolSizes, maxSets := DescriptorPoolSizes(copies, setBindings...)
+	if maxSets == 0 {
+		err := fmt.Errorf("CreateDescriptorPool: no descriptor sets to allocate")
+		return pool, err
+	}
+	var flags vk.DescriptorPoolCreateFlags
+	if freeSets {
+		flags |= vk.DescriptorPoolCreateFlags(vk.DescriptorPoolCreateFreeDescriptorSetBit)
+	}
+	descPoolCreateInfo := vk.DescriptorPoolCreateInfo{
+		SType:         vk.StructureTypeDescriptorPoolCreateInfo,
+		Flags:         flags,
+		MaxSets:       maxSets,
+		PoolSizeCount: uint32(len(poolSizes)),
+		PPoolSizes:    poolSizes,
+	}
+	err := vk.Error(vk.CreateDescriptorPool(device, &descPoolCreateInfo, nil, &pool))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateDescriptorPool failed with %s", err)
+		return pool, err
+	}
+	return pool, nil
+}
+
+// CreatePipelineLayout creates a pipeline layout with the descriptor set layouts at sets
+// 0, 1, 2... in order of update frequency, e.g. per frame, per material and per object.
+// The layout can be shared by passing it to CreateGraphicsPipeline, it stays owned
+// by the caller.
+func (v *VulkanDeviceInfo) CreatePipelineLayout(setLayouts []vk.DescriptorSetLayout,
+	pushConstants []vk.PushConstantRange) (vk.PipelineLayout, error) {
+
+	var layout vk.PipelineLayout
+	if maxSets := v.caps.Limits.MaxBoundDescriptorSets; uint32(len(setLayouts)) > maxSets {
+		err := fmt.Errorf("CreatePipelineLayout: %d descriptor sets exceed maxBoundDescriptorSets %d",
+			len(setLayouts), maxSets)
+		return layout, err
+	}
+	pipelineLayoutCreateInfo := vk.PipelineLayoutCreateInfo{
+		SType:                  vk.StructureTypePipelineLayoutCreateInfo,
+		SetLayoutCount:         uint32(len(setLayouts)),
+		PSetLayouts:            setLayouts,
+		PushConstantRangeCount: uint32(len(pushConstants)),
+		PPushConstantRanges:    pushConstants,
+	}
+	err := vk.Error(vk.CreatePipelineLayout(v.device, &pipelineLayoutCreateInfo, nil, &layout))
+	if err != nil {
+		err = fmt.Errorf("vk.CreatePipelineLayout failed with %s", err)
+		return layout, err
+	}
+	return layout, nil
+}
+
+// CmdBindDescriptorSets binds the sets at consecutive set indices starting at firstSet,
+// sets below stay bound as long as the pipeline layouts are compatible up to them.
+// So the per frame set is bound once and only the per object set is rebound per draw.
+func CmdBindDescriptorSets(cmd vk.CommandBuffer, layout vk.PipelineLayout,
+	firstSet uint32, sets ...vk.DescriptorSet) {
+
+	if len(sets) == 0 {
+		return
+	}
+	vk.CmdBindDescriptorSets(cmd, vk.PipelineBindPointGraphics, layout,
+		firstSet, uint32(len(sets)), sets, 0, nil)
+}
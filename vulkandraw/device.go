@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// RateDevice scores a physical device, the device with the highest score wins.
+// A negative score marks the device as unsuitable. surfaceOK reports whether
+// the device supports VK_KHR_swapchain and can present to the target surface.
+type RateDevice func(props vk.PhysicalDeviceProperties,
+	features vk.PhysicalDeviceFeatures, surfaceOK bool) int
+
+// presentScore outweighs any other part of a score, so a GPU that can present
+// always wins over one that can only run in compute-only mode.
+const presentScore = 10000000
+
+// DefaultRateDevice prefers devices that can present to the surface, then discrete
+// GPUs and then larger max 2D image dimensions. The others remain suitable for
+// the compute-only mode, see Headless.
+func DefaultRateDevice(props vk.PhysicalDeviceProperties,
+	features vk.PhysicalDeviceFeatures, surfaceOK bool) int {
+
+	var score int
+	if surfaceOK {
+		score += presentScore
+	}
+	if props.DeviceType == vk.PhysicalDeviceTypeDiscreteGpu {
+		score += 1000
+	}
+	score += int(props.Limits.MaxImageDimension2D)
+	return score
+}
+
+// DevicePreference is the type of GPU RateDeviceFor favors.
+type DevicePreference int
+
+const (
+	// PreferDiscrete favors discrete GPUs for performance, like DefaultRateDevice.
+	PreferDiscrete DevicePreference = iota
+	// PreferIntegrated favors integrated GPUs to save power on battery.
+	PreferIntegrated
+	// PreferCPU favors software implementations, e.g. for reference rendering.
+	PreferCPU
+	// PreferAny doesn't favor any type.
+	PreferAny
+)
+
+func (p DevicePreference) String() string {
+	switch p {
+	case PreferDiscrete:
+		return "discrete"
+	case PreferIntegrated:
+		return "integrated"
+	case PreferCPU:
+		return "CPU"
+	case PreferAny:
+		return "any"
+	default:
+		return "unknown"
+	}
+}
+
+// RateDeviceFor returns a RateDevice that favors GPUs that can present, then GPUs of the
+// preferred type, then larger max 2D image dimensions. Other types remain suitable, so the
+// pick falls back to them when there's no GPU of the preferred type, and so do GPUs that
+// can't present, see Headless.
+func RateDeviceFor(pref DevicePreference) RateDevice {
+	var preferred vk.PhysicalDeviceType
+	switch pref {
+	case PreferDiscrete:
+		preferred = vk.PhysicalDeviceTypeDiscreteGpu
+	case PreferIntegrated:
+		preferred = vk.PhysicalDeviceTypeIntegratedGpu
+	case PreferCPU:
+		preferred = vk.PhysicalDeviceTypeCpu
+	default:
+		// no type gets the bonus
+		preferred = -1
+	}
+	return func(props vk.PhysicalDeviceProperties,
+		features vk.PhysicalDeviceFeatures, surfaceOK bool) int {
+
+		var score int
+		if surfaceOK {
+			score += presentScore
+		}
+		if props.DeviceType == preferred {
+			score += 1000000
+		}
+		score += int(props.Limits.MaxImageDimension2D)
+		return score
+	}
+}
+
+// PickPhysicalDevice rates all the GPUs available and returns the best one,
+// uses DefaultRateDevice if rate is nil.
+func PickPhysicalDevice(gpus []vk.PhysicalDevice, surface vk.Surface,
+	rate RateDevice) (vk.PhysicalDevice, error) {
+
+	if rate == nil {
+		rate = DefaultRateDevice
+	}
+	var chosen vk.PhysicalDevice
+	var chosenProps vk.PhysicalDeviceProperties
+	bestScore := -1
+	anySurfaceOK, chosenSurfaceOK := false, false
+	for i, gpu := range gpus {
+		var props vk.PhysicalDeviceProperties
+		vk.GetPhysicalDeviceProperties(gpu, &props)
+		props.Deref()
+		props.Limits.Deref()
+		var features vk.PhysicalDeviceFeatures
+		vk.GetPhysicalDeviceFeatures(gpu, &features)
+		features.Deref()
+
+		surfaceOK := hasExtension(getDeviceExtensions(gpu), "VK_KHR_swapchain") &&
+			canPresent(gpu, surface)
+		anySurfaceOK = anySurfaceOK || surfaceOK
+		score := rate(props, features, surfaceOK)
+		log.Printf("[INFO] GPU %d: %s scored %d", i, vk.ToString(props.DeviceName[:]), score)
+		if score > bestScore {
+			bestScore = score
+			chosen = gpu
+			chosenProps = props
+			chosenSurfaceOK = surfaceOK
+		}
+	}
+	if bestScore < 0 && !anySurfaceOK {
+		err := fmt.Errorf("PickPhysicalDevice: no GPU can present to this surface, %d checked", len(gpus))
+		return chosen, err
+	}
+	if bestScore < 0 {
+		err := fmt.Errorf("PickPhysicalDevice: no suitable GPU found among %d", len(gpus))
+		return chosen, err
+	}
+	log.Printf("[INFO] chose %s (%s)", vk.ToString(chosenProps.DeviceName[:]),
+		physicalDeviceType(chosenProps.DeviceType))
+	if surface != vk.NullHandle && !chosenSurfaceOK {
+		log.Println("[WARN] the GPU chosen can't present to this surface")
+	}
+	return chosen, nil
+}
+
+// canPresent checks whether any queue family of the GPU can present to the surface.
+func canPresent(gpu vk.PhysicalDevice, surface vk.Surface) bool {
+	if surface == vk.NullHandle {
+		// headless, see NewVulkanDeviceHeadless
+		return false
+	}
+	var queueCount uint32
+	vk.GetPhysicalDeviceQueueFamilyProperties(gpu, &queueCount, nil)
+	for i := uint32(0); i < queueCount; i++ {
+		var supported vk.Bool32
+		vk.GetPhysicalDeviceSurfaceSupport(gpu, i, surface, &supported)
+		if supported == vk.Bool32(vk.True) {
+			return true
+		}
+	}
+	return false
+}
+
+func physicalDeviceType(dev vk.PhysicalDeviceType) string {
+	switch dev {
+	case vk.PhysicalDeviceTypeIntegratedGpu:
+		return "Integrated GPU"
+	case vk.PhysicalDeviceTypeDiscreteGpu:
+		return "Discrete GPU"
+	case vk.PhysicalDeviceTypeVirtualGpu:
+		return "Virtual GPU"
+	case vk.PhysicalDeviceTypeCpu:
+		return "CPU"
+	case vk.PhysicalDeviceTypeOther:
+		return "Other"
+	default:
+		return "Unknown"
+	}
+}
+
+func hasExtension(extNames []string, name string) bool {
+	for _, ext := range extNames {
+		if ext == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureNullTerminated appends the terminator C expects, unless the string has one already.
+func ensureNullTerminated(s string) string {
+	if strings.HasSuffix(s, "\x00") {
+		return s
+	}
+	return s + "\x00"
+}
+
+// nullTerminated returns a copy of the extension or layer names with each one terminated,
+// for the PpEnabled*Names fields, so plain Go strings can be passed around.
+func nullTerminated(names []string) []string {
+	terminated := make([]string, len(names))
+	for i, name := range names {
+		terminated[i] = ensureNullTerminated(name)
+	}
+	return terminated
+}
+
+// Headless reports whether the device lacks VK_KHR_swapchain or can't present to the
+// surface, only compute and offscreen work is possible then.
+func (v *VulkanDeviceInfo) Headless() bool {
+	return v.headless
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+func testDeviceProps(deviceType vk.PhysicalDeviceType, maxImageDim uint32) vk.PhysicalDeviceProperties {
+	return vk.PhysicalDeviceProperties{
+		DeviceType: deviceType,
+		Limits: vk.PhysicalDeviceLimits{
+			MaxImageDimension2D: maxImageDim,
+		},
+	}
+}
+
+func TestRateDeviceNotPresenting(t *testing.T) {
+	var features vk.PhysicalDeviceFeatures
+	discrete := testDeviceProps(vk.PhysicalDeviceTypeDiscreteGpu, 16384)
+	raters := map[string]RateDevice{
+		"DefaultRateDevice": DefaultRateDevice,
+		"PreferDiscrete":    RateDeviceFor(PreferDiscrete),
+		"PreferIntegrated":  RateDeviceFor(PreferIntegrated),
+		"PreferCPU":         RateDeviceFor(PreferCPU),
+		"PreferAny":         RateDeviceFor(PreferAny),
+	}
+	for name, rate := range raters {
+		// a GPU without VK_KHR_swapchain or present support still runs compute-only
+		if score := rate(discrete, features, false); score < 0 {
+			t.Errorf("%s: a GPU that can't present scored %d, want it suitable", name, score)
+		}
+	}
+}
+
+func TestRateDevicePrefersPresenting(t *testing.T) {
+	var features vk.PhysicalDeviceFeatures
+	discrete := testDeviceProps(vk.PhysicalDeviceTypeDiscreteGpu, 32768)
+	cpu := testDeviceProps(vk.PhysicalDeviceTypeCpu, 4096)
+	raters := map[string]RateDevice{
+		"DefaultRateDevice": DefaultRateDevice,
+		"PreferDiscrete":    RateDeviceFor(PreferDiscrete),
+		"PreferAny":         RateDeviceFor(PreferAny),
+	}
+	for name, rate := range raters {
+		// the preferred type and larger images don't outweigh presenting
+		if notPresenting, presenting := rate(discrete, features, false), rate(cpu, features, true); notPresenting >= presenting {
+			t.Errorf("%s: GPU that can't present scored %d, the one that can %d", name, notPresenting, presenting)
+		}
+	}
+}
+
+func TestRateDeviceForPreference(t *testing.T) {
+	var features vk.PhysicalDeviceFeatures
+	integrated := testDeviceProps(vk.PhysicalDeviceTypeIntegratedGpu, 8192)
+	discrete := testDeviceProps(vk.PhysicalDeviceTypeDiscreteGpu, 16384)
+	for _, surfaceOK := range []bool{true, false} {
+		rate := RateDeviceFor(PreferIntegrated)
+		if i, d := rate(integrated, features, surfaceOK), rate(discrete, features, surfaceOK); i <= d {
+			t.Errorf("surfaceOK %v: integrated GPU scored %d, discrete one %d", surfaceOK, i, d)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+#include <string.h>
+
+// VK_KHR_get_physical_device_properties2 is missing from the bindings, the feature
+// structs are built in C memory so they can be chained through pNext.
+
+#define VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_FEATURES_2 1000059000
+
+// the VkBool32 members of every feature struct follow sType and pNext
+typedef struct {
+	uint32_t sType;
+	void*    pNext;
+	uint32_t features[];
+} featureStruct;
+
+// the 55 VkBool32 members of VkPhysicalDeviceFeatures
+typedef struct {
+	uint32_t sType;
+	void*    pNext;
+	uint32_t features[55];
+} physicalDeviceFeatures2;
+
+typedef void (*getFeatures2Fn)(void* gpu, physicalDeviceFeatures2* features);
+
+static featureStruct* newFeatureStruct(uint32_t sType, uint32_t count, featureStruct* next) {
+	featureStruct* s = calloc(1, sizeof(featureStruct) + count * sizeof(uint32_t));
+	if (s != NULL) {
+		s->sType = sType;
+		s->pNext = next;
+	}
+	return s;
+}
+
+static uint32_t getFeature(featureStruct* s, uint32_t i) {
+	return s->features[i];
+}
+
+static void setFeature(featureStruct* s, uint32_t i, uint32_t value) {
+	s->features[i] = value;
+}
+
+static void callGetFeatures2(void* fn, void* gpu, featureStruct* chain) {
+	physicalDeviceFeatures2 features2;
+	memset(&features2, 0, sizeof(features2));
+	features2.sType = VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_FEATURES_2;
+	features2.pNext = chain;
+	((getFeatures2Fn)fn)(gpu, &features2);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"log"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// FeatureStruct describes a Vulkan 1.1+ feature struct chained to vk.PhysicalDeviceFeatures2,
+// by its sType and the number of VkBool32 members following pNext. Extensions are the
+// device extensions providing it on Vulkan 1.0 devices, they get enabled along with it.
+type FeatureStruct struct {
+	Name       string
+	SType      uint32
+	Count      uint32
+	Extensions []string
+}
+
+var (
+	Features16BitStorage = FeatureStruct{
+		Name: "16BitStorage", SType: 1000083000, Count: 4,
+		Extensions: []string{"VK_KHR_16bit_storage", "VK_KHR_storage_buffer_storage_class"},
+	}
+	FeaturesMultiview = FeatureStruct{
+		Name: "Multiview", SType: 1000053001, Count: 3,
+		Extensions: []string{"VK_KHR_multiview"},
+	}
+	FeaturesDescriptorIndexing = FeatureStruct{
+		Name: "DescriptorIndexing", SType: 1000161001, Count: 20,
+		Extensions: []string{"VK_KHR_maintenance3", "VK_EXT_descriptor_indexing"},
+	}
+	FeaturesTimelineSemaphore = FeatureStruct{
+		Name: "TimelineSemaphore", SType: 1000207000, Count: 1,
+		Extensions: []string{"VK_KHR_timeline_semaphore"},
+	}
+)
+
+// DeviceFeature is a VkBool32 member of a feature struct, Index counts from the first
+// one after pNext in the order of the Vulkan headers.
+type DeviceFeature struct {
+	Struct FeatureStruct
+	Index  uint32
+}
+
+var (
+	FeatureStorageBuffer16BitAccess = DeviceFeature{Features16BitStorage, 0}
+	FeatureStoragePushConstant16    = DeviceFeature{Features16BitStorage, 2}
+	FeatureMultiview                = DeviceFeature{FeaturesMultiview, 0}
+
+	FeatureSampledImageArrayNonUniformIndexing = DeviceFeature{FeaturesDescriptorIndexing, 4}
+	FeatureDescriptorBindingPartiallyBound     = DeviceFeature{FeaturesDescriptorIndexing, 17}
+	FeatureVariableDescriptorCount             = DeviceFeature{FeaturesDescriptorIndexing, 18}
+	FeatureRuntimeDescriptorArray              = DeviceFeature{FeaturesDescriptorIndexing, 19}
+
+	FeatureTimelineSemaphore = DeviceFeature{FeaturesTimelineSemaphore, 0}
+)
+
+func (f DeviceFeature) String() string {
+	return fmt.Sprintf("%s[%d]", f.Struct.Name, f.Index)
+}
+
+// FeatureChain is a pNext chain of feature structs in C memory, it must be freed with Free.
+type FeatureChain struct {
+	structs []FeatureStruct
+	ptrs    []*C.featureStruct
+}
+
+// newFeatureChain chains one zeroed struct for each distinct sType of the features.
+func newFeatureChain(features []DeviceFeature) (*FeatureChain, error) {
+	c := &FeatureChain{}
+	for _, f := range features {
+		if c.find(f.Struct) < 0 {
+			c.structs = append(c.structs, f.Struct)
+		}
+	}
+	c.ptrs = make([]*C.featureStruct, len(c.structs))
+	var next *C.featureStruct
+	for i := len(c.structs) - 1; i >= 0; i-- {
+		s := c.structs[i]
+		c.ptrs[i] = C.newFeatureStruct(C.uint32_t(s.SType), C.uint32_t(s.Count), next)
+		if c.ptrs[i] == nil {
+			c.Free()
+			err := fmt.Errorf("newFeatureChain: out of host memory")
+			return nil, err
+		}
+		next = c.ptrs[i]
+	}
+	return c, nil
+}
+
+func (c *FeatureChain) find(s FeatureStruct) int {
+	for i := range c.structs {
+		if c.structs[i].SType == s.SType {
+			return i
+		}
+	}
+	return -1
+}
+
+// Has reports whether the feature is set in the chain.
+func (c *FeatureChain) Has(f DeviceFeature) bool {
+	if c == nil {
+		return false
+	}
+	i := c.find(f.Struct)
+	if i < 0 || f.Index >= f.Struct.Count {
+		return false
+	}
+	return C.getFeature(c.ptrs[i], C.uint32_t(f.Index)) == C.uint32_t(vk.True)
+}
+
+func (c *FeatureChain) set(f DeviceFeature) {
+	i := c.find(f.Struct)
+	C.setFeature(c.ptrs[i], C.uint32_t(f.Index), C.uint32_t(vk.True))
+}
+
+// head is what goes into the pNext of vk.DeviceCreateInfo, nil for an empty chain.
+func (c *FeatureChain) head() unsafe.Pointer {
+	if c == nil || len(c.ptrs) == 0 {
+		return nil
+	}
+	return unsafe.Pointer(c.ptrs[0])
+}
+
+// extensions lists the device extensions providing the structs of the chain.
+func (c *FeatureChain) extensions() []string {
+	if c == nil {
+		return nil
+	}
+	var names []string
+	for _, s := range c.structs {
+		for _, name := range s.Extensions {
+			if !hasExtension(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func (c *FeatureChain) Free() {
+	if c == nil {
+		return
+	}
+	for _, p := range c.ptrs {
+		C.free(unsafe.Pointer(p))
+	}
+	c.ptrs = nil
+	c.structs = nil
+}
+
+// SupportedFeatures2 queries which of the features the GPU supports with
+// vkGetPhysicalDeviceFeatures2KHR, in the order of features.
+func (v *VulkanDeviceInfo) SupportedFeatures2(features ...DeviceFeature) ([]bool, error) {
+	supported, err := v.queryFeatures2(features)
+	if err != nil {
+		return nil, err
+	}
+	defer supported.Free()
+	result := make([]bool, len(features))
+	for i, f := range features {
+		result[i] = supported.Has(f)
+	}
+	return result, nil
+}
+
+// HasFeature2 reports whether the feature got enabled on the device, see VulkanDeviceOptions.Features2.
+func (v *VulkanDeviceInfo) HasFeature2(f DeviceFeature) bool {
+	return v.features2.Has(f)
+}
+
+func (v *VulkanDeviceInfo) queryFeatures2(features []DeviceFeature) (*FeatureChain, error) {
+	if v.getFeatures2 == nil {
+		err := fmt.Errorf("vkGetPhysicalDeviceFeatures2KHR: VK_KHR_get_physical_device_properties2 is not available")
+		return nil, err
+	}
+	for _, f := range features {
+		if f.Index >= f.Struct.Count {
+			err := fmt.Errorf("feature %s is out of the %d members of its struct", f, f.Struct.Count)
+			return nil, err
+		}
+	}
+	supported, err := newFeatureChain(features)
+	if err != nil {
+		return nil, err
+	}
+	C.callGetFeatures2(v.getFeatures2, unsafe.Pointer(v.gpu), (*C.featureStruct)(supported.head()))
+	return supported, nil
+}
+
+// chainFeatures2 builds the chain of the requested features the GPU supports and whose
+// extensions it has, the others are skipped with a warning. It's nil when none are requested.
+func (v *VulkanDeviceInfo) chainFeatures2(features []DeviceFeature) (*FeatureChain, error) {
+	if len(features) == 0 {
+		return nil, nil
+	}
+	var available []DeviceFeature
+	for _, f := range features {
+		missing := ""
+		for _, name := range f.Struct.Extensions {
+			if !hasExtension(v.caps.Extensions, name) {
+				missing = name
+				break
+			}
+		}
+		if missing != "" {
+			log.Printf("[WARN] feature %s skipped, %s is missing", f, missing)
+			continue
+		}
+		available = append(available, f)
+	}
+	if len(available) == 0 {
+		return nil, nil
+	}
+	supported, err := v.queryFeatures2(available)
+	if err != nil {
+		return nil, err
+	}
+	defer supported.Free()
+	var enabled []DeviceFeature
+	for _, f := range available {
+		if !supported.Has(f) {
+			log.Printf("[WARN] feature %s skipped, it's not supported", f)
+			continue
+		}
+		enabled = append(enabled, f)
+	}
+	if len(enabled) == 0 {
+		return nil, nil
+	}
+	chain, err := newFeatureChain(enabled)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range enabled {
+		chain.set(f)
+	}
+	log.Println("[INFO] enabled features", enabled)
+	return chain, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// FencePool recycles the fences of one time submissions, e.g. uploads, instead of
+// creating and destroying one per submission. Acquire hands out an unsignaled fence
+// and Release resets it for the next one. It's safe for concurrent use.
+type FencePool struct {
+	device vk.Device
+
+	mux     sync.Mutex
+	free    []vk.Fence
+	created int // fences created so far, free or acquired
+}
+
+// NewFencePool creates an empty pool, fences get created on demand.
+func NewFencePool(device vk.Device) *FencePool {
+	return &FencePool{
+		device: device,
+	}
+}
+
+// Acquire returns a free unsignaled fence, creating one when none is left.
+func (p *FencePool) Acquire() (vk.Fence, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if n := len(p.free); n > 0 {
+		fence := p.free[n-1]
+		p.free = p.free[:n-1]
+		return fence, nil
+	}
+	fenceCreateInfo := vk.FenceCreateInfo{
+		SType: vk.StructureTypeFenceCreateInfo,
+	}
+	var fence vk.Fence
+	err := vk.Error(vk.CreateFence(p.device, &fenceCreateInfo, nil, &fence))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateFence failed with %s", err)
+		return vk.NullHandle, err
+	}
+	p.created++
+	return fence, nil
+}
+
+// Release resets the fence and returns it to the pool, the submission it was
+// signaled by must have completed or it must have never been submitted.
+func (p *FencePool) Release(fence vk.Fence) error {
+	err := vk.Error(vk.ResetFences(p.device, 1, []vk.Fence{fence}))
+	if err != nil {
+		p.Discard(fence)
+		err = fmt.Errorf("vk.ResetFences failed with %s", err)
+		return err
+	}
+	p.mux.Lock()
+	p.free = append(p.free, fence)
+	p.mux.Unlock()
+	return nil
+}
+
+// Discard destroys the fence instead of returning it to the pool, e.g. when resetting
+// it failed. The submission it was signaled by must have completed.
+func (p *FencePool) Discard(fence vk.Fence) {
+	vk.DestroyFence(p.device, fence, nil)
+	p.mux.Lock()
+	p.created--
+	p.mux.Unlock()
+}
+
+// Stats returns the number of fences created and not destroyed, and how many of them are free.
+func (p *FencePool) Stats() (created, free int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return p.created, len(p.free)
+}
+
+// Destroy destroys the free fences, all acquired ones must have been released.
+func (p *FencePool) Destroy() {
+	if p == nil {
+		return
+	}
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	for _, fence := range p.free {
+		vk.DestroyFence(p.device, fence, nil)
+	}
+	p.created -= len(p.free)
+	p.free = nil
+}
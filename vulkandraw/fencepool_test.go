@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+func checkFencePoolStats(t *testing.T, p *FencePool, wantCreated, wantFree int) {
+	t.Helper()
+	if created, free := p.Stats(); created != wantCreated || free != wantFree {
+		t.Errorf("pool has %d fences created and %d free, want %d and %d",
+			created, free, wantCreated, wantFree)
+	}
+}
+
+func TestFencePoolReuse(t *testing.T) {
+	v := NewVulkanDeviceForTest(t)
+	p := NewFencePool(v.device)
+	defer p.Destroy()
+
+	fence, err := p.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFencePoolStats(t, p, 1, 0)
+
+	// an empty submission signals the fence
+	if err := vk.Error(vk.QueueSubmit(v.graphicsQueue, 0, nil, fence)); err != nil {
+		t.Fatal("vk.QueueSubmit failed with", err)
+	}
+	if err := vk.Error(vk.WaitForFences(v.device, 1, []vk.Fence{fence}, vk.True, vk.MaxUint64)); err != nil {
+		t.Fatal("vk.WaitForFences failed with", err)
+	}
+	if err := p.Release(fence); err != nil {
+		t.Fatal(err)
+	}
+	checkFencePoolStats(t, p, 1, 1)
+
+	// the released fence comes back reset instead of a new one
+	reused, err := p.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reused != fence {
+		t.Errorf("Acquire returned a new fence, want the released one")
+	}
+	if ret := vk.GetFenceStatus(v.device, reused); ret != vk.NotReady {
+		t.Errorf("reused fence status is %s, want it unsignaled", resultString(ret))
+	}
+	checkFencePoolStats(t, p, 1, 0)
+
+	// with none free, a second fence gets created
+	other, err := p.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == reused {
+		t.Errorf("Acquire returned the fence already acquired")
+	}
+	checkFencePoolStats(t, p, 2, 0)
+	p.Release(reused)
+	p.Release(other)
+	checkFencePoolStats(t, p, 2, 2)
+}
+
+func TestFencePoolDiscard(t *testing.T) {
+	v := NewVulkanDeviceForTest(t)
+	p := NewFencePool(v.device)
+	defer p.Destroy()
+
+	fence, err := p.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Discard(fence)
+	// the fence is destroyed rather than kept for reuse
+	checkFencePoolStats(t, p, 0, 0)
+
+	next, err := p.Acquire()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFencePoolStats(t, p, 1, 0)
+	p.Release(next)
+	checkFencePoolStats(t, p, 1, 1)
+
+	p.Destroy()
+	checkFencePoolStats(t, p, 0, 0)
+}
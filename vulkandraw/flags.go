@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+type flagName struct {
+	bit  uint32
+	name string
+}
+
+var imageUsageNames = []flagName{
+	{uint32(vk.ImageUsageTransferSrcBit), "TRANSFER_SRC"},
+	{uint32(vk.ImageUsageTransferDstBit), "TRANSFER_DST"},
+	{uint32(vk.ImageUsageSampledBit), "SAMPLED"},
+	{uint32(vk.ImageUsageStorageBit), "STORAGE"},
+	{uint32(vk.ImageUsageColorAttachmentBit), "COLOR_ATTACHMENT"},
+	{uint32(vk.ImageUsageDepthStencilAttachmentBit), "DEPTH_STENCIL_ATTACHMENT"},
+	{uint32(vk.ImageUsageTransientAttachmentBit), "TRANSIENT_ATTACHMENT"},
+	{uint32(vk.ImageUsageInputAttachmentBit), "INPUT_ATTACHMENT"},
+}
+
+var surfaceTransformNames = []flagName{
+	{uint32(vk.SurfaceTransformIdentityBit), "IDENTITY"},
+	{uint32(vk.SurfaceTransformRotate90Bit), "ROTATE_90"},
+	{uint32(vk.SurfaceTransformRotate180Bit), "ROTATE_180"},
+	{uint32(vk.SurfaceTransformRotate270Bit), "ROTATE_270"},
+	{uint32(vk.SurfaceTransformHorizontalMirrorBit), "HORIZONTAL_MIRROR"},
+	{uint32(vk.SurfaceTransformHorizontalMirrorRotate90Bit), "HORIZONTAL_MIRROR_ROTATE_90"},
+	{uint32(vk.SurfaceTransformHorizontalMirrorRotate180Bit), "HORIZONTAL_MIRROR_ROTATE_180"},
+	{uint32(vk.SurfaceTransformHorizontalMirrorRotate270Bit), "HORIZONTAL_MIRROR_ROTATE_270"},
+	{uint32(vk.SurfaceTransformInheritBit), "INHERIT"},
+}
+
+// flagsString joins the names of the bits set in flags, unknown bits are kept as hex.
+func flagsString(flags uint32, names []flagName) string {
+	if flags == 0 {
+		return "NONE"
+	}
+	var set []string
+	for _, n := range names {
+		if flags&n.bit != 0 {
+			set = append(set, n.name)
+			flags &^= n.bit
+		}
+	}
+	if flags != 0 {
+		set = append(set, fmt.Sprintf("%#x", flags))
+	}
+	return strings.Join(set, ", ")
+}
+
+var compositeAlphaNames = []flagName{
+	{uint32(vk.CompositeAlphaOpaqueBit), "OPAQUE"},
+	{uint32(vk.CompositeAlphaPreMultipliedBit), "PRE_MULTIPLIED"},
+	{uint32(vk.CompositeAlphaPostMultipliedBit), "POST_MULTIPLIED"},
+	{uint32(vk.CompositeAlphaInheritBit), "INHERIT"},
+}
+
+// usageFlagsString decodes image usage flags, e.g. "TRANSFER_SRC, COLOR_ATTACHMENT".
+func usageFlagsString(flags vk.ImageUsageFlags) string {
+	return flagsString(uint32(flags), imageUsageNames)
+}
+
+// transformFlagsString decodes surface transform flags, e.g. "IDENTITY, ROTATE_90".
+func transformFlagsString(flags vk.SurfaceTransformFlags) string {
+	return flagsString(uint32(flags), surfaceTransformNames)
+}
+
+// compositeAlphaFlagsString decodes composite alpha flags, e.g. "OPAQUE, INHERIT".
+func compositeAlphaFlagsString(flags vk.CompositeAlphaFlags) string {
+	return flagsString(uint32(flags), compositeAlphaNames)
+}
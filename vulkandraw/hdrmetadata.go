@@ -0,0 +1,115 @@
+package main
+
+/*
+#include <stdint.h>
+
+// VK_EXT_hdr_metadata is missing from the bindings, vkSetHdrMetadataEXT
+// is resolved by hand and called through these self-contained typedefs.
+
+#define VK_STRUCTURE_TYPE_HDR_METADATA_EXT 1000105000
+
+typedef struct {
+	float x;
+	float y;
+} xyColor;
+
+typedef struct {
+	uint32_t    sType;
+	const void* pNext;
+	xyColor     displayPrimaryRed;
+	xyColor     displayPrimaryGreen;
+	xyColor     displayPrimaryBlue;
+	xyColor     whitePoint;
+	float       maxLuminance;
+	float       minLuminance;
+	float       maxContentLightLevel;
+	float       maxFrameAverageLightLevel;
+} hdrMetadata;
+
+// swapchains points to an array of VkSwapchainKHR handles of the native size
+typedef void (*setHdrMetadataFn)(void* device, uint32_t count,
+	const void* swapchains, const hdrMetadata* metadata);
+
+static void callSetHdrMetadata(void* fn, void* device, uint32_t count,
+	const void* swapchains, const float* m) {
+
+	hdrMetadata metadata = {
+		VK_STRUCTURE_TYPE_HDR_METADATA_EXT, NULL,
+		{m[0], m[1]}, {m[2], m[3]}, {m[4], m[5]}, {m[6], m[7]},
+		m[8], m[9], m[10], m[11],
+	};
+	hdrMetadata all[count];
+	for (uint32_t i = 0; i < count; i++) {
+		all[i] = metadata;
+	}
+	((setHdrMetadataFn)fn)(device, count, swapchains, all);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// HDRMetadata describes the mastering display the content was graded on and its light
+// levels, so the display can tone map it. Chromaticities are CIE 1931 xy coordinates
+// and luminances are in nits.
+type HDRMetadata struct {
+	DisplayPrimaryRed   [2]float32
+	DisplayPrimaryGreen [2]float32
+	DisplayPrimaryBlue  [2]float32
+	WhitePoint          [2]float32
+
+	MaxLuminance float32
+	MinLuminance float32
+	// MaxContentLightLevel and MaxFrameAverageLightLevel are the brightest pixel and
+	// the brightest frame average of the content, 0 when unknown.
+	MaxContentLightLevel      float32
+	MaxFrameAverageLightLevel float32
+}
+
+// DefaultHDRMetadata is HDR10 content mastered on a BT.2020 display with a D65 white point,
+// going from 0.001 to 1000 nits.
+func DefaultHDRMetadata() HDRMetadata {
+	return HDRMetadata{
+		DisplayPrimaryRed:   [2]float32{0.708, 0.292},
+		DisplayPrimaryGreen: [2]float32{0.170, 0.797},
+		DisplayPrimaryBlue:  [2]float32{0.131, 0.046},
+		WhitePoint:          [2]float32{0.3127, 0.3290},
+		MaxLuminance:        1000,
+		MinLuminance:        0.001,
+	}
+}
+
+// HasHDRMetadata reports whether VK_EXT_hdr_metadata is enabled, see SetHDRMetadata.
+func (v *VulkanDeviceInfo) HasHDRMetadata() bool {
+	return v.setHDRMetadata != nil
+}
+
+// SetHDRMetadata hands the metadata to the display for all swapchains of s, which must
+// have been created with an HDR color space, see HDRSurfaceFormats. It takes effect
+// with the next present and needs VK_EXT_hdr_metadata, see HasHDRMetadata.
+func (v *VulkanDeviceInfo) SetHDRMetadata(s *VulkanSwapchainInfo, metadata HDRMetadata) error {
+	if v.setHDRMetadata == nil {
+		return fmt.Errorf("SetHDRMetadata: VK_EXT_hdr_metadata is not available")
+	}
+	if !isHDRColorSpace(s.displayColorSpace) {
+		return fmt.Errorf("SetHDRMetadata: swapchain is in %s, not an HDR color space",
+			colorSpaceName(s.displayColorSpace))
+	}
+	if len(s.swapchains) == 0 {
+		return nil
+	}
+	m := []C.float{
+		C.float(metadata.DisplayPrimaryRed[0]), C.float(metadata.DisplayPrimaryRed[1]),
+		C.float(metadata.DisplayPrimaryGreen[0]), C.float(metadata.DisplayPrimaryGreen[1]),
+		C.float(metadata.DisplayPrimaryBlue[0]), C.float(metadata.DisplayPrimaryBlue[1]),
+		C.float(metadata.WhitePoint[0]), C.float(metadata.WhitePoint[1]),
+		C.float(metadata.MaxLuminance), C.float(metadata.MinLuminance),
+		C.float(metadata.MaxContentLightLevel), C.float(metadata.MaxFrameAverageLightLevel),
+	}
+	C.callSetHdrMetadata(v.setHDRMetadata, unsafe.Pointer(v.device), C.uint32_t(len(s.swapchains)),
+		unsafe.Pointer(&s.swapchains[0]), &m[0])
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import vk "github.com/vulkan-go/vulkan"
+
+// NewVulkanDeviceHeadless creates an instance and a device without a surface, e.g. for
+// compute and offscreen work or for tests, the window-dependent steps are skipped.
+// The device is always headless, so it can't create swapchains. GPUs are rated as if
+// they could present, opts.RateDevice gets surfaceOK true.
+func NewVulkanDeviceHeadless(appInfo vk.ApplicationInfo,
+	opts VulkanDeviceOptions) (VulkanDeviceInfo, error) {
+
+	v, err := createInstance(appInfo, nil)
+	if err != nil {
+		return v, err
+	}
+	if v.gpuDevices, err = getPhysicalDevices(v.instance); err != nil {
+		v.gpuDevices = nil
+		vk.DestroyInstance(v.instance, nil)
+		return v, err
+	}
+	rate := opts.RateDevice
+	if rate == nil {
+		rate = RateDeviceFor(opts.Preference)
+	}
+	ignoreSurface := func(props vk.PhysicalDeviceProperties,
+		features vk.PhysicalDeviceFeatures, surfaceOK bool) int {
+
+		return rate(props, features, true)
+	}
+	if v.gpu, err = PickPhysicalDevice(v.gpuDevices, vk.NullHandle, ignoreSurface); err != nil {
+		v.gpuDevices = nil
+		vk.DestroyInstance(v.instance, nil)
+		return v, err
+	}
+	if err = v.createDevice(opts, false); err != nil {
+		return v, err
+	}
+	return v, nil
+}
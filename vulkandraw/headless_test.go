@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+var testAppInfo = vk.ApplicationInfo{
+	SType:              vk.StructureTypeApplicationInfo,
+	ApiVersion:         vk.MakeVersion(1, 0, 0),
+	ApplicationVersion: vk.MakeVersion(1, 0, 0),
+	PApplicationName:   "VulkanDrawTest\x00",
+	PEngineName:        "golang\x00",
+}
+
+// NewVulkanDeviceForTest creates a headless device for package tests, so buffers, memory
+// types, shaders and formats can be exercised on a real GPU or a software one such as
+// lavapipe in CI. Any GPU type is accepted. The test is skipped when there's no Vulkan
+// loader or no device can be created, and the device is destroyed when the test ends.
+func NewVulkanDeviceForTest(t testing.TB) *VulkanDeviceInfo {
+	t.Helper()
+	if err := vk.Init(); err != nil {
+		t.Skip("no Vulkan loader:", err)
+	}
+	v, err := NewVulkanDeviceHeadless(testAppInfo, VulkanDeviceOptions{
+		Preference: PreferAny,
+	})
+	if err != nil {
+		t.Skip("no Vulkan device:", err)
+	}
+	t.Cleanup(func() {
+		vk.DeviceWaitIdle(v.device)
+		v.Destroy()
+	})
+	return &v
+}
+
+func TestCreateBufferHostVisible(t *testing.T) {
+	v := NewVulkanDeviceForTest(t)
+	props := vk.MemoryPropertyHostVisibleBit | vk.MemoryPropertyHostCoherentBit
+	buffer, mem, err := v.createBuffer(256, vk.BufferUsageVertexBufferBit, props)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vk.FreeMemory(v.device, mem, nil)
+	defer vk.DestroyBuffer(v.device, buffer, nil)
+
+	var memReq vk.MemoryRequirements
+	vk.GetBufferMemoryRequirements(v.device, buffer, &memReq)
+	memReq.Deref()
+	if memReq.Size < 256 {
+		t.Errorf("buffer needs %d bytes, want at least 256", memReq.Size)
+	}
+	memTypeIdx, ok := vk.FindMemoryTypeIndex(v.gpu, memReq.MemoryTypeBits, props)
+	if !ok {
+		t.Fatal("no memory type is host visible and coherent")
+	}
+	var memProps vk.PhysicalDeviceMemoryProperties
+	vk.GetPhysicalDeviceMemoryProperties(v.gpu, &memProps)
+	memProps.Deref()
+	memType := memProps.MemoryTypes[memTypeIdx]
+	memType.Deref()
+	if memType.PropertyFlags&vk.MemoryPropertyFlags(props) != vk.MemoryPropertyFlags(props) {
+		t.Errorf("memory type %d has properties %x, want %x", memTypeIdx, memType.PropertyFlags, props)
+	}
+
+	// host visible memory maps, and what's written reads back
+	var ptr unsafe.Pointer
+	if err := vk.Error(vk.MapMemory(v.device, mem, 0, 256, 0, &ptr)); err != nil {
+		t.Fatal("vk.MapMemory failed with", err)
+	}
+	data := []byte{1, 2, 3, 4}
+	vk.MemCopyByte(ptr, data)
+	got := (*[4]byte)(ptr)
+	if got[0] != 1 || got[3] != 4 {
+		t.Errorf("mapped memory reads %v, want %v", got[:], data)
+	}
+	vk.UnmapMemory(v.device, mem)
+}
@@ -64,6 +64,29 @@ func orPanicWith(err interface{}, notes ...string) {
 	}
 }
 
+func vkBool(b bool) vk.Bool32 {
+	if b {
+		return vk.Bool32(vk.True)
+	}
+	return vk.Bool32(vk.False)
+}
+
+func maxUint32(a, b uint32) uint32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// alignUp rounds offset up to a multiple of alignment, e.g. of the minimum offset alignment
+// of uniform buffers. An alignment of 0 leaves offset as is.
+func alignUp(offset, alignment vk.DeviceSize) vk.DeviceSize {
+	if alignment == 0 {
+		return offset
+	}
+	return (offset + alignment - 1) / alignment * alignment
+}
+
 func repackUint32(data []byte) []uint32 {
 	buf := make([]uint32, len(data)/4)
 	hdr := (*sliceHeader)(unsafe.Pointer(&buf))
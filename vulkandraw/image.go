@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// layoutAccess returns the access mask and pipeline stages that use an image in the layout,
+// it serves both sides of a barrier.
+func layoutAccess(layout vk.ImageLayout) (vk.AccessFlags, vk.PipelineStageFlags) {
+	switch layout {
+	case vk.ImageLayoutUndefined:
+		return 0, vk.PipelineStageFlags(vk.PipelineStageTopOfPipeBit)
+	case vk.ImageLayoutPreinitialized:
+		return vk.AccessFlags(vk.AccessHostWriteBit),
+			vk.PipelineStageFlags(vk.PipelineStageHostBit)
+	case vk.ImageLayoutTransferDstOptimal:
+		return vk.AccessFlags(vk.AccessTransferWriteBit),
+			vk.PipelineStageFlags(vk.PipelineStageTransferBit)
+	case vk.ImageLayoutTransferSrcOptimal:
+		return vk.AccessFlags(vk.AccessTransferReadBit),
+			vk.PipelineStageFlags(vk.PipelineStageTransferBit)
+	case vk.ImageLayoutShaderReadOnlyOptimal:
+		return vk.AccessFlags(vk.AccessShaderReadBit),
+			vk.PipelineStageFlags(vk.PipelineStageFragmentShaderBit)
+	case vk.ImageLayoutColorAttachmentOptimal:
+		return vk.AccessFlags(vk.AccessColorAttachmentReadBit | vk.AccessColorAttachmentWriteBit),
+			vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit)
+	case vk.ImageLayoutDepthStencilAttachmentOptimal:
+		return vk.AccessFlags(vk.AccessDepthStencilAttachmentReadBit | vk.AccessDepthStencilAttachmentWriteBit),
+			vk.PipelineStageFlags(vk.PipelineStageEarlyFragmentTestsBit | vk.PipelineStageLateFragmentTestsBit)
+	case vk.ImageLayoutPresentSrc:
+		// presentation is synchronized with semaphores
+		return 0, vk.PipelineStageFlags(vk.PipelineStageBottomOfPipeBit)
+	default:
+		return vk.AccessFlags(vk.AccessMemoryReadBit | vk.AccessMemoryWriteBit),
+			vk.PipelineStageFlags(vk.PipelineStageAllCommandsBit)
+	}
+}
+
+// transitionImageLayout records an image memory barrier that moves the subresource range
+// from oldLayout to newLayout.
+func transitionImageLayout(cmd vk.CommandBuffer, image vk.Image,
+	subresourceRange vk.ImageSubresourceRange, oldLayout, newLayout vk.ImageLayout) {
+
+	srcAccess, srcStages := layoutAccess(oldLayout)
+	dstAccess, dstStages := layoutAccess(newLayout)
+	barriers := []vk.ImageMemoryBarrier{{
+		SType:               vk.StructureTypeImageMemoryBarrier,
+		SrcAccessMask:       srcAccess,
+		DstAccessMask:       dstAccess,
+		OldLayout:           oldLayout,
+		NewLayout:           newLayout,
+		SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+		DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+		Image:               image,
+		SubresourceRange:    subresourceRange,
+	}}
+	vk.CmdPipelineBarrier(cmd, srcStages, dstStages, 0, 0, nil, 0, nil, 1, barriers)
+}
+
+// allocateImageMemory allocates memory with the properties for the image and binds it,
+// usually vk.MemoryPropertyDeviceLocalBit. It's the image counterpart of createBuffer.
+// The image gets a dedicated allocation when the driver requires or prefers it.
+func allocateImageMemory(device vk.Device, gpu vk.PhysicalDevice, image vk.Image,
+	props vk.MemoryPropertyFlagBits) (vk.DeviceMemory, error) {
+
+	return allocateImage(device, gpu, image, props, 0)
+}
+
+// allocateTargetMemory allocates device local memory for a render target and binds it,
+// large targets get a dedicated allocation, see dedicatedImageThreshold.
+func allocateTargetMemory(device vk.Device, gpu vk.PhysicalDevice,
+	image vk.Image) (vk.DeviceMemory, error) {
+
+	return allocateImage(device, gpu, image, vk.MemoryPropertyDeviceLocalBit, dedicatedImageThreshold)
+}
+
+// allocateImage allocates and binds the memory of the image, with a dedicated allocation
+// when VK_KHR_dedicated_allocation is available and dedicatedImage says so.
+func allocateImage(device vk.Device, gpu vk.PhysicalDevice, image vk.Image,
+	props vk.MemoryPropertyFlagBits, dedicatedThreshold vk.DeviceSize) (vk.DeviceMemory, error) {
+
+	var mem vk.DeviceMemory
+	var memReq vk.MemoryRequirements
+	vk.GetImageMemoryRequirements(device, image, &memReq)
+	memReq.Deref()
+	memTypeIdx, ok := vk.FindMemoryTypeIndex(gpu, memReq.MemoryTypeBits, props)
+	if !ok {
+		err := fmt.Errorf("vk.FindMemoryTypeIndex: no memory type with properties %x for image", props)
+		return mem, err
+	}
+	allocInfo := vk.MemoryAllocateInfo{
+		SType:           vk.StructureTypeMemoryAllocateInfo,
+		AllocationSize:  memReq.Size,
+		MemoryTypeIndex: memTypeIdx,
+	}
+	if dedicatedImage(device, image, dedicatedThreshold) {
+		allocInfo.PNext = dedicatedAllocateInfo(image)
+		defer freeDedicatedAllocateInfo(allocInfo.PNext)
+	}
+	err := vk.Error(vk.AllocateMemory(device, &allocInfo, nil, &mem))
+	if err != nil {
+		err = fmt.Errorf("vk.AllocateMemory failed with %s", err)
+		return vk.NullHandle, err
+	}
+	err = vk.Error(vk.BindImageMemory(device, image, mem, 0))
+	if err != nil {
+		vk.FreeMemory(device, mem, nil)
+		err = fmt.Errorf("vk.BindImageMemory failed with %s", err)
+		return vk.NullHandle, err
+	}
+	return mem, nil
+}
+
+// ImageFormatProperties returns the limits of images with the format, type, tiling, usage
+// and create flags, it fails with vk.ErrorFormatNotSupported when there's no such image.
+func ImageFormatProperties(gpu vk.PhysicalDevice, format vk.Format, imageType vk.ImageType,
+	tiling vk.ImageTiling, usage vk.ImageUsageFlags,
+	flags vk.ImageCreateFlags) (vk.ImageFormatProperties, error) {
+
+	var props vk.ImageFormatProperties
+	err := vk.Error(vk.GetPhysicalDeviceImageFormatProperties(gpu, format, imageType,
+		tiling, usage, flags, &props))
+	if err != nil {
+		err = fmt.Errorf("vk.GetPhysicalDeviceImageFormatProperties failed with %s", err)
+		return props, err
+	}
+	props.Deref()
+	props.MaxExtent.Deref()
+	return props, nil
+}
+
+// validateImageCreateInfo checks the extent, mip levels, array layers and samples
+// of the image against the limits of its format, so vk.CreateImage doesn't fail obscurely.
+func validateImageCreateInfo(gpu vk.PhysicalDevice, info *vk.ImageCreateInfo) error {
+	props, err := ImageFormatProperties(gpu, info.Format, info.ImageType,
+		info.Tiling, info.Usage, info.Flags)
+	if err != nil {
+		err = fmt.Errorf("image format %d: %s", info.Format, err)
+		return err
+	}
+	limit := props.MaxExtent
+	if info.Extent.Width > limit.Width || info.Extent.Height > limit.Height ||
+		info.Extent.Depth > limit.Depth {
+		return fmt.Errorf("image of %dx%dx%d exceeds the %dx%dx%d maximum of format %d",
+			info.Extent.Width, info.Extent.Height, info.Extent.Depth,
+			limit.Width, limit.Height, limit.Depth, info.Format)
+	}
+	if info.MipLevels > props.MaxMipLevels {
+		return fmt.Errorf("image with %d mip levels exceeds the %d maximum of format %d",
+			info.MipLevels, props.MaxMipLevels, info.Format)
+	}
+	if info.ArrayLayers > props.MaxArrayLayers {
+		return fmt.Errorf("image with %d array layers exceeds the %d maximum of format %d",
+			info.ArrayLayers, props.MaxArrayLayers, info.Format)
+	}
+	if vk.SampleCountFlags(info.Samples)&props.SampleCounts == 0 {
+		return fmt.Errorf("image format %d doesn't support %d samples", info.Format, info.Samples)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// drawIndirectCommandSize is sizeof(VkDrawIndirectCommand), four uint32 fields.
+const drawIndirectCommandSize = 16
+
+// drawIndirectData lays out the commands as the GPU reads them, each one taking stride bytes.
+func drawIndirectData(cmds []vk.DrawIndirectCommand, stride uint32) []byte {
+	data := make([]byte, len(cmds)*int(stride))
+	for i, cmd := range cmds {
+		entry := data[i*int(stride):]
+		binary.LittleEndian.PutUint32(entry[0:], cmd.VertexCount)
+		binary.LittleEndian.PutUint32(entry[4:], cmd.InstanceCount)
+		binary.LittleEndian.PutUint32(entry[8:], cmd.FirstVertex)
+		binary.LittleEndian.PutUint32(entry[12:], cmd.FirstInstance)
+	}
+	return data
+}
+
+// validateIndirectDraw checks the draw count and stride against the device features and limits.
+func (v *VulkanDeviceInfo) validateIndirectDraw(drawCount, stride uint32) error {
+	if drawCount == 0 {
+		return fmt.Errorf("no draw commands given")
+	}
+	if stride < drawIndirectCommandSize || stride%4 != 0 {
+		return fmt.Errorf("stride %d must be a multiple of 4 and at least %d",
+			stride, drawIndirectCommandSize)
+	}
+	if drawCount == 1 {
+		return nil
+	}
+	if v.enabledFeatures.MultiDrawIndirect != vk.Bool32(vk.True) {
+		return fmt.Errorf("%d draws need the multiDrawIndirect feature", drawCount)
+	}
+	if maxCount := v.caps.Limits.MaxDrawIndirectCount; drawCount > maxCount {
+		return fmt.Errorf("%d draws exceed maxDrawIndirectCount %d", drawCount, maxCount)
+	}
+	return nil
+}
+
+// CreateIndirectBuffer uploads the draw commands into an indirect buffer of b, the recorded
+// command buffers then draw with vk.CmdDrawIndirect instead of vk.CmdDraw. A zero stride
+// means the commands are tightly packed.
+func (v *VulkanDeviceInfo) CreateIndirectBuffer(b *VulkanBufferInfo,
+	cmds []vk.DrawIndirectCommand, stride uint32) error {
+
+	if stride == 0 {
+		stride = drawIndirectCommandSize
+	}
+	if err := v.validateIndirectDraw(uint32(len(cmds)), stride); err != nil {
+		err = fmt.Errorf("CreateIndirectBuffer: %s", err)
+		return err
+	}
+	data := drawIndirectData(cmds, stride)
+	buffer, mem, err := v.createBuffer(vk.DeviceSize(len(data)), vk.BufferUsageIndirectBufferBit,
+		vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+	if err != nil {
+		return err
+	}
+	var ptr unsafe.Pointer
+	err = vk.Error(vk.MapMemory(v.device, mem, 0, vk.DeviceSize(len(data)), 0, &ptr))
+	if err != nil {
+		vk.DestroyBuffer(v.device, buffer, nil)
+		vk.FreeMemory(v.device, mem, nil)
+		err = fmt.Errorf("vk.MapMemory failed with %s", err)
+		return err
+	}
+	vk.MemCopyByte(ptr, data)
+	vk.UnmapMemory(v.device, mem)
+
+	b.destroyIndirect()
+	b.indirectBuffer = buffer
+	b.indirectMem = mem
+	b.indirectCount = uint32(len(cmds))
+	b.indirectStride = stride
+	return nil
+}
+
+func (buf *VulkanBufferInfo) destroyIndirect() {
+	if buf.indirectBuffer == vk.NullHandle {
+		return
+	}
+	vk.DestroyBuffer(buf.device, buf.indirectBuffer, nil)
+	vk.FreeMemory(buf.device, buf.indirectMem, nil)
+	buf.indirectBuffer = vk.NullHandle
+	buf.indirectMem = vk.NullHandle
+}
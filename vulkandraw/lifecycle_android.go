@@ -0,0 +1,612 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	vk "github.com/vulkan-go/vulkan"
+	"github.com/xlab/android-go/android"
+	"github.com/xlab/android-go/app"
+)
+
+// VulkanApp ties the Vulkan objects of the demo to the NativeActivity lifecycle:
+// everything is built when the window becomes available, torn down when it's destroyed
+// and rebuilt when the window gets replaced. Frames are drawn only while the activity
+// is resumed.
+type VulkanApp struct {
+	v   VulkanDeviceInfo
+	s   VulkanSwapchainInfo
+	r   VulkanRenderInfo
+	b   VulkanBufferInfo
+	gfx VulkanGfxPipelineInfo
+	off VulkanOffscreenInfo
+	pst VulkanOffscreenInfo
+	pp  VulkanPostInfo
+	st  VulkanStatsInfo
+	ts  VulkanTimestampsInfo
+	ol  VulkanOutlineInfo
+	pt  VulkanParticlesInfo
+
+	depth        VulkanAttachmentImage // shared by the framebuffers, see EnableDepth
+	depthEnabled bool
+
+	pacer     *FramePacer
+	idlePacer *FramePacer
+	frames    int
+	dirty     bool // the scene changed since the last frame
+
+	window        *android.NativeWindow
+	swapchainOpts VulkanSwapchainOptions
+
+	active bool // the Vulkan objects exist
+	paused bool
+}
+
+func NewVulkanApp() *VulkanApp {
+	return &VulkanApp{
+		pacer:     NewFramePacer(targetFPS),
+		idlePacer: NewFramePacer(idleFPS),
+		// the outline needs the stencil of the depth attachment
+		depthEnabled: drawOutline && renderScale == 1 && !postProcess,
+	}
+}
+
+// HandleLifecycleEvent pauses drawing between onPause and onResume.
+func (va *VulkanApp) HandleLifecycleEvent(event app.LifecycleEvent) {
+	switch event.Kind {
+	case app.OnPause:
+		va.paused = true
+	case app.OnResume:
+		va.paused = false
+	}
+}
+
+// HandleWindowEvent creates, destroys or draws with the Vulkan objects following the window,
+// the activity gets notified when a redraw is done.
+func (va *VulkanApp) HandleWindowEvent(a app.NativeActivity, event app.NativeWindowEvent) error {
+	switch event.Kind {
+	case app.NativeWindowCreated:
+		if va.active {
+			// the window got replaced, e.g. after a configuration change
+			va.destroy()
+		}
+		return va.create(event.Window)
+	case app.NativeWindowDestroyed:
+		va.destroy()
+	case app.NativeWindowRedrawNeeded:
+		if va.active && !va.paused {
+			va.drawFrame()
+		}
+		a.NativeWindowRedrawDone()
+	}
+	return nil
+}
+
+func (va *VulkanApp) create(window *android.NativeWindow) error {
+	if err := vk.Init(); err != nil {
+		return err
+	}
+	va.window = window
+	if assetDir != "" {
+		LoadAsset = FileAssets(assetDir)
+	}
+	if err := ValidateAssets(requiredAssets()...); err != nil {
+		return err
+	}
+	var err error
+	deviceOpts := VulkanDeviceOptions{
+		RobustBufferAccess: robustBufferAccess,
+	}
+	if sparseBuffers {
+		deviceOpts.Queues = append(deviceOpts.Queues, QueueRequest{
+			Purpose:  QueueSparse,
+			Priority: 1.0,
+		})
+	}
+	va.v, err = NewVulkanDeviceAndroid(appInfo, window, deviceOpts)
+	if err != nil {
+		return err
+	}
+	va.swapchainOpts = VulkanSwapchainOptions{}
+	if renderScale != 1 {
+		va.swapchainOpts.ImageUsage = vk.ImageUsageTransferDstBit
+	}
+	if preferHDR {
+		va.swapchainOpts.SurfaceFormats = HDRSurfaceFormats
+	}
+	v := &va.v
+	if va.s, err = v.CreateSwapchain(va.swapchainOpts); err != nil {
+		return err
+	}
+	va.setHDRMetadata()
+	if err = va.createDepth(); err != nil {
+		return err
+	}
+	attachments := []vk.AttachmentDescription{
+		PresentAttachmentDescription(va.s.displayFormat),
+	}
+	va.r, err = CreateRenderer(v.device, v.queueFamilies[QueueGraphics], attachments, va.depth.format)
+	if err != nil {
+		return err
+	}
+	if err = va.s.CreateFramebuffers(&va.r, va.depth.view, attachments[1:]); err != nil {
+		return err
+	}
+	switch {
+	case drawQuad:
+		va.b, err = v.CreateQuadBuffers()
+	case sparseBuffers:
+		if va.b, err = v.CreateSparseBuffers(va.r.UploadPool()); err != nil {
+			log.Println("[INFO] skipping sparse buffers:", err)
+			va.b, err = v.CreateBuffers()
+		}
+	case separateStreams:
+		va.b, err = v.CreateBuffersSeparate()
+	default:
+		va.b, err = v.CreateBuffers()
+	}
+	if err != nil {
+		return err
+	}
+	if drawIndirect && !drawQuad {
+		err = v.CreateIndirectBuffer(&va.b, []vk.DrawIndirectCommand{{
+			VertexCount:   3,
+			InstanceCount: 1,
+		}}, 0)
+		if err != nil {
+			return err
+		}
+	}
+	renderSize := va.s.displaySize
+	if renderScale != 1 {
+		renderSize = ScaleExtent(va.s.displaySize, renderScale)
+		if va.off, err = v.CreateOffscreenTarget(va.s.displayFormat, renderSize); err != nil {
+			return err
+		}
+		va.r.UseOffscreen(&va.off)
+		log.Printf("[INFO] rendering at %dx%d", renderSize.Width, renderSize.Height)
+	}
+	if err = v.ValidateVertexLayout(va.b.VertexLayout()); err != nil {
+		return err
+	}
+	var shaders []ShaderStage
+	if tessellate {
+		if err := v.ValidateShaderStages(TessellationShaders); err != nil {
+			log.Println("[WARN]", err)
+		} else {
+			shaders = TessellationShaders
+		}
+	}
+	va.gfx, err = CreateGraphicsPipeline(v.device, renderSize, va.r.renderPass, vk.NullHandle,
+		len(attachments), DefaultDepthState(), DefaultMultisampleState(),
+		va.b.VertexLayout(), shaders)
+	if err != nil {
+		return err
+	}
+	if drawOutline {
+		va.ol, err = v.CreateOutlinePipelines(va.s.displaySize, va.r.renderPass, len(attachments),
+			va.depth.format, va.b.VertexLayout(), shaders)
+		if err != nil {
+			log.Println("[WARN] skipping the outline:", err)
+			va.ol = VulkanOutlineInfo{}
+		}
+	}
+	if drawParticles {
+		if err = va.createParticles(renderSize); err != nil {
+			log.Println("[WARN] skipping the particles:", err)
+		}
+	}
+	if postProcess {
+		if va.pst, err = v.CreateSampledTarget(va.s.displayFormat, renderSize); err != nil {
+			return err
+		}
+		va.pp, err = CreatePostPipeline(v.device, va.s.displaySize, va.r.renderPass, &va.pst)
+		if err != nil {
+			return err
+		}
+		va.r.UsePostProcess(&va.pp)
+	}
+	log.Println("[INFO] swapchain lengths:", va.s.swapchainLen)
+	if err = va.r.CreateCommandBuffers(va.s.ImageCount()); err != nil {
+		return err
+	}
+	if logPipelineStats {
+		if va.st, err = v.CreatePipelineStats(va.s.ImageCount()); err != nil {
+			log.Println("[WARN]", err)
+		} else {
+			va.r.UsePipelineStats(&va.st)
+		}
+	}
+	if logPassDuration {
+		if va.ts, err = v.CreateTimestamps(va.s.ImageCount()); err != nil {
+			log.Println("[WARN]", err)
+		} else {
+			va.r.UseTimestamps(&va.ts)
+		}
+	}
+
+	if va.ol.device != nil || va.pt.device != nil {
+		va.r.SetOverlay(va.drawOverlay)
+	}
+	if synchronousMode {
+		va.r.UseSynchronousMode()
+	}
+	if idleFPS > 0 {
+		va.r.UseStaticScene()
+	}
+	if err = VulkanInit(&va.v, &va.s, &va.r, &va.b, &va.gfx); err != nil {
+		return err
+	}
+	va.active = true
+	va.dirty = true
+	return nil
+}
+
+// setHDRMetadata describes the content to HDR displays, swapchains don't keep it
+// when they're recreated.
+func (va *VulkanApp) setHDRMetadata() {
+	v := &va.v
+	if !preferHDR || !v.HasHDRMetadata() || !isHDRColorSpace(va.s.displayColorSpace) {
+		return
+	}
+	if err := v.SetHDRMetadata(&va.s, DefaultHDRMetadata()); err != nil {
+		log.Println("[WARN]", err)
+	}
+}
+
+// createParticles creates the particles drawn in the overlay along with a pre-pass updating
+// them for each frame in flight. The pre-pass gets resubmitted as is, so the particles move
+// by the same step every frame.
+func (va *VulkanApp) createParticles(renderSize vk.Extent2D) error {
+	const step = 1.0 / 60 // seconds
+	v := &va.v
+	// the overlay draws in the final pass with a post pass
+	size := renderSize
+	if postProcess {
+		size = va.s.displaySize
+	}
+	pt, err := v.CreateParticles(particleCount, size, va.r.renderPass, va.r.colorAttachments)
+	if err != nil {
+		return err
+	}
+	cmdBuffers := make([]vk.CommandBuffer, maxFramesInFlight)
+	cmdBufferAllocateInfo := vk.CommandBufferAllocateInfo{
+		SType:              vk.StructureTypeCommandBufferAllocateInfo,
+		CommandPool:        va.r.cmdPool,
+		Level:              vk.CommandBufferLevelPrimary,
+		CommandBufferCount: maxFramesInFlight,
+	}
+	err = vk.Error(vk.AllocateCommandBuffers(v.device, &cmdBufferAllocateInfo, cmdBuffers))
+	if err != nil {
+		pt.Destroy()
+		err = fmt.Errorf("vk.AllocateCommandBuffers failed with %s", err)
+		return err
+	}
+	// freed along with the command pool
+	for _, cmd := range cmdBuffers {
+		cmdBufferBeginInfo := vk.CommandBufferBeginInfo{
+			SType: vk.StructureTypeCommandBufferBeginInfo,
+		}
+		ret := vk.BeginCommandBuffer(cmd, &cmdBufferBeginInfo)
+		check(ret, "vk.BeginCommandBuffer")
+		pt.CmdUpdate(cmd, step)
+		ret = vk.EndCommandBuffer(cmd)
+		check(ret, "vk.EndCommandBuffer")
+	}
+	if err := va.r.AddPrePass(cmdBuffers); err != nil {
+		pt.Destroy()
+		return err
+	}
+	va.pt = pt
+	return nil
+}
+
+// createDepth creates the depth image for the swapchain when depth is enabled,
+// otherwise it leaves va.depth empty so the render pass gets no depth attachment.
+func (va *VulkanApp) createDepth() error {
+	va.depth = VulkanAttachmentImage{}
+	if !va.depthEnabled {
+		return nil
+	}
+	findFormat := va.v.FindDepthFormat
+	if drawOutline {
+		findFormat = va.v.FindDepthStencilFormat
+	}
+	format, err := findFormat()
+	if err != nil {
+		return err
+	}
+	depth, err := va.s.CreateDepthImage(format)
+	if err != nil {
+		return err
+	}
+	va.depth = depth
+	return nil
+}
+
+// EnableDepth adds or removes the depth attachment while drawing. The render pass and
+// the framebuffers are coupled by their attachment count, so both get rebuilt together
+// once the device is idle, followed by the pipeline and the command buffers.
+// The offscreen and post targets have no depth attachment, so depth can't be enabled
+// along with renderScale or postProcess: the scene pipeline must match their render pass.
+func (va *VulkanApp) EnableDepth(enable bool) error {
+	if enable == va.depthEnabled {
+		return nil
+	}
+	if enable && (renderScale != 1 || postProcess) {
+		err := fmt.Errorf("EnableDepth: the offscreen targets have no depth attachment")
+		return err
+	}
+	if !enable && va.ol.device != nil {
+		err := fmt.Errorf("EnableDepth: the outline needs the stencil of the depth attachment")
+		return err
+	}
+	va.depthEnabled = enable
+	if !va.active {
+		// create picks it up
+		return nil
+	}
+	v := &va.v
+	vk.DeviceWaitIdle(v.device)
+	va.s.DestroyFramebuffers()
+	va.depth.Destroy(v.device)
+	if err := va.createDepth(); err != nil {
+		return err
+	}
+	attachments := []vk.AttachmentDescription{
+		PresentAttachmentDescription(va.s.displayFormat),
+	}
+	if err := va.r.RecreateRenderPass(attachments, va.depth.format); err != nil {
+		return err
+	}
+	if err := va.s.CreateFramebuffers(&va.r, va.depth.view, attachments[1:]); err != nil {
+		return err
+	}
+	err := RecreatePipeline(&va.gfx, v.device, va.s.displaySize, va.r.renderPass)
+	if err != nil {
+		return err
+	}
+	if err := va.recreateOverlayPipelines(RecreatePipeline); err != nil {
+		return err
+	}
+	for i := range va.r.cmdBuffers {
+		va.r.recordFrame(i)
+	}
+	va.dirty = true
+	log.Println("[INFO] depth enabled:", enable)
+	return nil
+}
+
+// SetDrawRanges draws the ranges of the buffers instead of all of them, see
+// VulkanBufferInfo.SetDrawRanges. The command buffers get recorded again once the device
+// is idle. The ranges go with the buffers, so they're reset when the window gets replaced.
+func (va *VulkanApp) SetDrawRanges(ranges ...DrawRange) error {
+	if !va.active {
+		return fmt.Errorf("SetDrawRanges: there are no buffers to draw from")
+	}
+	if err := va.b.SetDrawRanges(ranges...); err != nil {
+		return err
+	}
+	vk.DeviceWaitIdle(va.v.device)
+	for i := range va.r.cmdBuffers {
+		va.r.recordFrame(i)
+	}
+	va.dirty = true
+	return nil
+}
+
+// ReloadShaders swaps the vertex and fragment shaders of the scene pipeline, see ReloadShaders.
+// The command buffers get recorded again with the new pipeline.
+func (va *VulkanApp) ReloadShaders(vertName, fragName string) error {
+	if !va.active {
+		return fmt.Errorf("ReloadShaders: there is no pipeline to reload")
+	}
+	err := ReloadShaders(&va.gfx, va.v.device, va.s.displaySize, va.r.renderPass, vertName, fragName)
+	if err != nil {
+		return err
+	}
+	for i := range va.r.cmdBuffers {
+		va.r.recordFrame(i)
+	}
+	va.dirty = true
+	return nil
+}
+
+// requiredAssets lists the shaders the features turned on in main_android.go load.
+func requiredAssets() []string {
+	assets := ShaderAssets(TriangleShaders)
+	if tessellate {
+		assets = append(assets, ShaderAssets(TessellationShaders)...)
+	}
+	if postProcess {
+		assets = append(assets, FullscreenShader, PostFragmentShader)
+	}
+	if drawOutline {
+		assets = append(assets, ShaderAssets(OutlineShaders)...)
+	}
+	if drawParticles {
+		assets = append(assets, ParticleComputeShader)
+		assets = append(assets, ShaderAssets(ParticleShaders)...)
+	}
+	return assets
+}
+
+// MarkDirty tells that the scene changed and must be drawn again, until then frames
+// are paced at idleFPS.
+func (va *VulkanApp) MarkDirty() {
+	va.dirty = true
+	va.r.Invalidate()
+}
+
+func (va *VulkanApp) destroy() {
+	if !va.active {
+		return
+	}
+	va.active = false
+	vk.DeviceWaitIdle(va.v.device)
+	va.off.Destroy()
+	va.off = VulkanOffscreenInfo{}
+	va.pp.Destroy()
+	va.pp = VulkanPostInfo{}
+	va.pst.Destroy()
+	va.pst = VulkanOffscreenInfo{}
+	va.st.Destroy()
+	va.st = VulkanStatsInfo{}
+	va.ts.Destroy()
+	va.ts = VulkanTimestampsInfo{}
+	va.ol.Destroy()
+	va.ol = VulkanOutlineInfo{}
+	va.pt.Destroy()
+	va.pt = VulkanParticlesInfo{}
+	va.depth.Destroy(va.v.device)
+	va.depth = VulkanAttachmentImage{}
+	DestroyInOrder(&va.v, &va.s, &va.r, &va.b, &va.gfx)
+}
+
+// resize rebuilds the swapchain and the objects depending on its extent once the surface
+// changed. The offscreen targets are sized after the swapchain too, so with them
+// or when the number of images changes everything gets created again.
+func (va *VulkanApp) resize() error {
+	s, err := va.v.RecreateSwapchainDeferred(&va.s, va.swapchainOpts, &va.r)
+	if err != nil {
+		return err
+	}
+	return va.replaceSwapchain(s)
+}
+
+// recreateSurface follows the sequence of RecreateSurface after the surface was lost.
+func (va *VulkanApp) recreateSurface() error {
+	v := &va.v
+	vk.DeviceWaitIdle(v.device)
+	va.s.Destroy()
+	if err := v.RecreateSurface(va.window); err != nil {
+		return err
+	}
+	s, err := v.CreateSwapchain(va.swapchainOpts)
+	if err != nil {
+		return err
+	}
+	return va.replaceSwapchain(s)
+}
+
+// replaceSwapchain rebuilds what depends on the swapchain for the new one.
+func (va *VulkanApp) replaceSwapchain(s VulkanSwapchainInfo) error {
+	v := &va.v
+	va.s = s
+	va.setHDRMetadata()
+	if renderScale != 1 || postProcess || va.s.ImageCount() != uint32(len(va.r.cmdBuffers)) {
+		va.destroy()
+		return va.create(va.window)
+	}
+	if va.depthEnabled {
+		// the depth image follows the extent, the frames using the old one
+		// on the retired swapchain may still be in flight
+		depth := va.depth
+		va.r.retire(func() {
+			depth.Destroy(v.device)
+		})
+		if err := va.createDepth(); err != nil {
+			return err
+		}
+	}
+	if err := va.s.CreateFramebuffers(&va.r, va.depth.view, nil); err != nil {
+		return err
+	}
+	err := RecreatePipelineDeferred(&va.gfx, v.device, va.s.displaySize, va.r.renderPass, &va.r)
+	if err != nil {
+		return err
+	}
+	err = va.recreateOverlayPipelines(func(gfx *VulkanGfxPipelineInfo, device vk.Device,
+		displaySize vk.Extent2D, renderPass vk.RenderPass) error {
+		return RecreatePipelineDeferred(gfx, device, displaySize, renderPass, &va.r)
+	})
+	if err != nil {
+		return err
+	}
+	// the command buffers may still be pending, each one gets recorded
+	// again once the frame that last used it completed
+	va.r.Invalidate()
+	va.dirty = true
+	log.Printf("[INFO] swapchain of %dx%d", va.s.displaySize.Width, va.s.displaySize.Height)
+	return nil
+}
+
+// drawOverlay draws the outline and the particles on top of the scene.
+func (va *VulkanApp) drawOverlay(frame OverlayFrame) {
+	if va.ol.device != nil {
+		va.ol.CmdDraw(frame.Cmd, &va.b)
+	}
+	if va.pt.device != nil {
+		va.pt.CmdDraw(frame.Cmd)
+	}
+}
+
+// recreateOverlayPipelines rebuilds the pipelines drawOverlay uses with recreate, e.g.
+// RecreatePipeline, after the swapchain or the render pass changed.
+func (va *VulkanApp) recreateOverlayPipelines(recreate func(gfx *VulkanGfxPipelineInfo,
+	device vk.Device, displaySize vk.Extent2D, renderPass vk.RenderPass) error) error {
+
+	var pipelines []*VulkanGfxPipelineInfo
+	if va.ol.device != nil {
+		pipelines = append(pipelines, &va.ol.shape, &va.ol.outline)
+	}
+	if va.pt.device != nil {
+		pipelines = append(pipelines, &va.pt.graphics)
+	}
+	for _, gfx := range pipelines {
+		if err := recreate(gfx, va.v.device, va.s.displaySize, va.r.renderPass); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (va *VulkanApp) drawFrame() {
+	// suboptimal alone doesn't fail the frame, it's reported for every frame
+	// of a rotated surface as the swapchain isn't pre-rotated
+	switch result, err := VulkanDrawFrame(&va.v, &va.s, &va.r); result {
+	case DrawNeedsRecreate:
+		if err := va.resize(); err != nil {
+			log.Println("[WARN]", err)
+		}
+		return
+	case DrawSkipped:
+		log.Println("[WARN] frame skipped:", err)
+	case DrawSurfaceLost:
+		log.Println("[WARN]", err)
+		if err := va.recreateSurface(); err != nil {
+			log.Println("[WARN] cannot recreate the surface:", err)
+			va.destroy()
+		}
+		return
+	case DrawFatal:
+		// nothing gets drawn until the window is created again
+		log.Println("[WARN] frame failed:", err)
+		va.destroy()
+		return
+	}
+	if idleFPS > 0 && !va.dirty {
+		// nothing changed, so there's no hurry
+		va.idlePacer.Wait()
+	} else {
+		va.pacer.Wait()
+	}
+	va.dirty = false
+	va.frames++
+	if logPipelineStats && va.frames%60 == 0 {
+		stats, ok, err := va.r.ReadPipelineStats()
+		if err != nil {
+			log.Println("[WARN]", err)
+		} else if ok {
+			log.Printf("[INFO] pipeline stats: %+v", stats)
+		}
+	}
+	if logPassDuration && va.frames%60 == 0 {
+		d, ok, err := va.r.ReadPassDuration()
+		if err != nil {
+			log.Println("[WARN]", err)
+		} else if ok {
+			log.Printf("[INFO] render passes took %s on the GPU", d)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	vk "github.com/vulkan-go/vulkan"
+	"github.com/xlab/android-go/android"
+	"github.com/xlab/android-go/app"
+	"github.com/xlab/catcher"
+)
+
+func init() {
+	app.SetLogTag("VulkanDraw")
+}
+
+var appInfo = vk.ApplicationInfo{
+	SType:              vk.StructureTypeApplicationInfo,
+	ApiVersion:         vk.MakeVersion(1, 0, 0),
+	ApplicationVersion: vk.MakeVersion(1, 0, 0),
+	PApplicationName:   "VulkanDraw\x00",
+	PEngineName:        "golang\x00",
+}
+
+// renderScale is the ratio between the render resolution and the display one,
+// values above 1 supersample the scene which gets downscaled with a linear filter.
+const renderScale = 1.0
+
+// postProcess renders the scene into a texture which gets sampled by a fullscreen
+// pass drawn to the swapchain, the post shaders must be built with make shaders.
+const postProcess = false
+
+// preferHDR requests a 10-bit or float swapchain with an HDR color space,
+// falling back to 8-bit sRGB when the display doesn't support one.
+const preferHDR = false
+
+// logPipelineStats logs the pipeline statistics of the scene every 60 frames,
+// the GPU must support the pipelineStatisticsQuery feature.
+const logPipelineStats = false
+
+// logPassDuration logs the GPU time of the render passes every 60 frames, measured
+// with timestamp queries the graphics queue must support.
+const logPassDuration = false
+
+// drawQuad draws a quad of two indexed triangles instead of the triangle,
+// the vertices carry uvs at location 1 for the shaders to sample a texture with.
+const drawQuad = false
+
+// drawIndirect draws the triangle with vk.CmdDrawIndirect reading the draw
+// parameters from a buffer.
+const drawIndirect = false
+
+// tessellate subdivides the triangle with tessellation shaders, the GPU must support
+// the tessellationShader feature and the shaders must be built with make shaders.
+const tessellate = false
+
+// drawOutline draws a solid outline around the triangle with the stencil buffer, it turns
+// depth on with a depth-stencil format, so it can't go along with renderScale or postProcess.
+// The outline shaders must be built with make shaders.
+const drawOutline = false
+
+// drawParticles draws particles moved by a compute shader on top of the scene, the graphics
+// queue family must support compute and the particle shaders must be built with make shaders.
+const drawParticles = false
+
+// particleCount is the number of particles drawParticles draws.
+const particleCount = 4096
+
+// separateStreams keeps positions, normals and uvs in separate vertex buffers
+// instead of a single interleaved one.
+const separateStreams = false
+
+// sparseBuffers creates the triangle vertex buffer as a sparse buffer bound on a sparse
+// binding queue, the GPU must support the sparseBinding feature.
+const sparseBuffers = false
+
+// robustBufferAccess makes out of bounds buffer reads in shaders return zeros,
+// it's slower and only meant for debugging.
+const robustBufferAccess = false
+
+// synchronousMode completes every frame on the GPU before the next one starts,
+// it's slow and only meant for debugging.
+const synchronousMode = false
+
+// assetDir is where the assets are read from before bindata, e.g. shaders rebuilt and
+// pushed with adb for VulkanApp.ReloadShaders to pick up. Empty means bindata only.
+const assetDir = ""
+
+// targetFPS caps the frame rate to save battery, 0 means uncapped.
+const targetFPS = 0
+
+// idleFPS caps the frame rate while the scene is unchanged, the recorded command buffers
+// get resubmitted as is until VulkanApp.MarkDirty is called. 0 always draws at targetFPS.
+const idleFPS = 0
+
+func main() {
+	nativeWindowEvents := make(chan app.NativeWindowEvent)
+	inputQueueEvents := make(chan app.InputQueueEvent, 1)
+	inputQueueChan := make(chan *android.InputQueue, 1)
+
+	app.Main(func(a app.NativeActivity) {
+		// disable this to get the stack
+		defer catcher.Catch(
+			catcher.RecvLog(true),
+			catcher.RecvDie(-1),
+		)
+		va := NewVulkanApp()
+
+		a.HandleNativeWindowEvents(nativeWindowEvents)
+		a.HandleInputQueueEvents(inputQueueEvents)
+		// just skip input events (so app won't be dead on touch input)
+		go app.HandleInputQueues(inputQueueChan, func() {
+			a.InputQueueHandled()
+		}, app.SkipInputEvents)
+		a.InitDone()
+
+		for {
+			select {
+			case event := <-a.LifecycleEvents():
+				va.HandleLifecycleEvent(event)
+			case event := <-inputQueueEvents:
+				switch event.Kind {
+				case app.QueueCreated:
+					inputQueueChan <- event.Queue
+				case app.QueueDestroyed:
+					inputQueueChan <- nil
+				}
+			case event := <-nativeWindowEvents:
+				err := va.HandleWindowEvent(a, event)
+				orPanic(err)
+			}
+		}
+	})
+}
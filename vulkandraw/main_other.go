@@ -0,0 +1,12 @@
+//go:build !android
+// +build !android
+
+package main
+
+import "log"
+
+// main only exists so the package builds on desktop, where the headless device and
+// the tests run, e.g. on lavapipe. The demo itself is built for Android, see Makefile.
+func main() {
+	log.Fatalln("vulkandraw runs on Android only, build it with make")
+}
@@ -0,0 +1,96 @@
+package main
+
+import "math"
+
+// Mat4 is a 4x4 matrix stored in column-major order, as GLSL expects it
+// in uniform buffers and push constants: element (row, col) is at col*4+row.
+type Mat4 [16]float32
+
+// Vec3 is a point or a direction in 3D.
+type Vec3 [3]float32
+
+// Identity returns the identity matrix.
+func Identity() Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Perspective returns a right-handed projection for a camera looking down -Z, fovy is
+// the vertical field of view in radians. It targets the Vulkan clip space: Y points down
+// and depth goes from 0 at near to 1 at far, unlike OpenGL's [-1, 1].
+func Perspective(fovy, aspect, near, far float32) Mat4 {
+	f := float32(1 / math.Tan(float64(fovy)/2))
+	return Mat4{
+		f / aspect, 0, 0, 0,
+		0, -f, 0, 0, // Y flip
+		0, 0, far / (near - far), -1,
+		0, 0, near * far / (near - far), 0,
+	}
+}
+
+// Ortho returns a right-handed orthographic projection of the box, with the same
+// Vulkan clip space conventions as Perspective.
+func Ortho(left, right, bottom, top, near, far float32) Mat4 {
+	return Mat4{
+		2 / (right - left), 0, 0, 0,
+		0, -2 / (top - bottom), 0, 0, // Y flip
+		0, 0, -1 / (far - near), 0,
+		-(right + left) / (right - left), (top + bottom) / (top - bottom), -near / (far - near), 1,
+	}
+}
+
+// LookAt returns the view matrix of a right-handed camera at eye looking at center.
+func LookAt(eye, center, up Vec3) Mat4 {
+	f := normalize(sub(center, eye))
+	s := normalize(cross(f, up))
+	u := cross(s, f)
+	return Mat4{
+		s[0], u[0], -f[0], 0,
+		s[1], u[1], -f[1], 0,
+		s[2], u[2], -f[2], 0,
+		-dot(s, eye), -dot(u, eye), dot(f, eye), 1,
+	}
+}
+
+// Mul returns m*n, so n gets applied first, e.g. proj.Mul(view).Mul(model).
+func (m Mat4) Mul(n Mat4) Mat4 {
+	var out Mat4
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			var sum float32
+			for k := 0; k < 4; k++ {
+				sum += m[k*4+row] * n[col*4+k]
+			}
+			out[col*4+row] = sum
+		}
+	}
+	return out
+}
+
+func sub(a, b Vec3) Vec3 {
+	return Vec3{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func dot(a, b Vec3) float32 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross(a, b Vec3) Vec3 {
+	return Vec3{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func normalize(a Vec3) Vec3 {
+	l := float32(math.Sqrt(float64(dot(a, a))))
+	if l == 0 {
+		return a
+	}
+	return Vec3{a[0] / l, a[1] / l, a[2] / l}
+}
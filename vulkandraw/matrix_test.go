@@ -0,0 +1,135 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+const matrixEpsilon = 1e-6
+
+func approxEqual(a, b float32) bool {
+	return math.Abs(float64(a-b)) <= matrixEpsilon*math.Max(1, math.Abs(float64(b)))
+}
+
+func checkMat4(t *testing.T, name string, got, want Mat4) {
+	t.Helper()
+	for i := range want {
+		if !approxEqual(got[i], want[i]) {
+			t.Errorf("%s: element (%d, %d) is %v, want %v\ngot  %v\nwant %v",
+				name, i%4, i/4, got[i], want[i], got, want)
+			return
+		}
+	}
+}
+
+// transform applies m to the point p and divides by w, giving normalized device coordinates.
+func transform(m Mat4, p Vec3) Vec3 {
+	var out [4]float32
+	for row := 0; row < 4; row++ {
+		out[row] = m[row]*p[0] + m[4+row]*p[1] + m[8+row]*p[2] + m[12+row]
+	}
+	return Vec3{out[0] / out[3], out[1] / out[3], out[2] / out[3]}
+}
+
+func checkVec3(t *testing.T, name string, got, want Vec3) {
+	t.Helper()
+	for i := range want {
+		if !approxEqual(got[i], want[i]) {
+			t.Errorf("%s is %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func TestPerspective(t *testing.T) {
+	m := Perspective(math.Pi/2, 2, 1, 10)
+	// column-major, f = 1/tan(fovy/2) = 1
+	checkMat4(t, "Perspective", m, Mat4{
+		0.5, 0, 0, 0,
+		0, -1, 0, 0,
+		0, 0, -10.0 / 9, -1,
+		0, 0, -10.0 / 9, 0,
+	})
+	// depth is 0 at near and 1 at far, unlike OpenGL's -1 and 1
+	checkVec3(t, "near top right", transform(m, Vec3{2, 1, -1}), Vec3{1, -1, 0})
+	checkVec3(t, "far bottom left", transform(m, Vec3{-20, -10, -10}), Vec3{-1, 1, 1})
+}
+
+func TestOrtho(t *testing.T) {
+	m := Ortho(-2, 2, -1, 1, 0.5, 4.5)
+	checkMat4(t, "Ortho", m, Mat4{
+		0.5, 0, 0, 0,
+		0, -1, 0, 0,
+		0, 0, -0.25, 0,
+		0, 0, -0.125, 1,
+	})
+	// Y points down in the Vulkan clip space, so top maps to -1
+	checkVec3(t, "near top right", transform(m, Vec3{2, 1, -0.5}), Vec3{1, -1, 0})
+	checkVec3(t, "far bottom left", transform(m, Vec3{-2, -1, -4.5}), Vec3{-1, 1, 1})
+
+	// an off-center box is shifted into the clip space as well
+	m = Ortho(0, 4, 0, 2, 0, 1)
+	checkMat4(t, "off-center Ortho", m, Mat4{
+		0.5, 0, 0, 0,
+		0, -1, 0, 0,
+		0, 0, -1, 0,
+		-1, 1, 0, 1,
+	})
+}
+
+func TestLookAt(t *testing.T) {
+	// down -Z from +Z is a translation only
+	m := LookAt(Vec3{0, 0, 5}, Vec3{0, 0, 0}, Vec3{0, 1, 0})
+	checkMat4(t, "LookAt from +Z", m, Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, -5, 1,
+	})
+
+	// from +X the camera's right is -Z
+	m = LookAt(Vec3{3, 0, 0}, Vec3{0, 0, 0}, Vec3{0, 1, 0})
+	checkMat4(t, "LookAt from +X", m, Mat4{
+		0, 0, 1, 0,
+		0, 1, 0, 0,
+		-1, 0, 0, 0,
+		0, 0, -3, 1,
+	})
+	checkVec3(t, "eye", transform(m, Vec3{3, 0, 0}), Vec3{0, 0, 0})
+	checkVec3(t, "center", transform(m, Vec3{0, 0, 0}), Vec3{0, 0, -3})
+	checkVec3(t, "right of the camera", transform(m, Vec3{3, 0, -1}), Vec3{1, 0, 0})
+}
+
+func TestMul(t *testing.T) {
+	translate := Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		1, 2, 3, 1,
+	}
+	scale := Mat4{
+		2, 0, 0, 0,
+		0, 3, 0, 0,
+		0, 0, 4, 0,
+		0, 0, 0, 1,
+	}
+	checkMat4(t, "Identity().Mul(translate)", Identity().Mul(translate), translate)
+	// the right hand side gets applied first
+	checkMat4(t, "translate.Mul(scale)", translate.Mul(scale), Mat4{
+		2, 0, 0, 0,
+		0, 3, 0, 0,
+		0, 0, 4, 0,
+		1, 2, 3, 1,
+	})
+	checkMat4(t, "scale.Mul(translate)", scale.Mul(translate), Mat4{
+		2, 0, 0, 0,
+		0, 3, 0, 0,
+		0, 0, 4, 0,
+		2, 6, 12, 1,
+	})
+
+	// a point in front of the camera ends up in the middle of the depth range
+	view := LookAt(Vec3{0, 0, 5}, Vec3{0, 0, 0}, Vec3{0, 1, 0})
+	proj := Ortho(-1, 1, -1, 1, 1, 9)
+	checkVec3(t, "origin", transform(proj.Mul(view), Vec3{0, 0, 0}), Vec3{0, 0, 0.5})
+}
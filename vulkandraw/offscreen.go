@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// VulkanOffscreenInfo is a color target the scene gets rendered into,
+// its contents are then blitted onto the acquired swapchain image.
+type VulkanOffscreenInfo struct {
+	device vk.Device
+
+	image       vk.Image
+	mem         vk.DeviceMemory
+	view        vk.ImageView
+	renderPass  vk.RenderPass
+	framebuffer vk.Framebuffer
+
+	format      vk.Format
+	extent      vk.Extent2D
+	finalLayout vk.ImageLayout
+}
+
+// ScaleExtent scales the extent, e.g. by 2 for supersampling.
+func ScaleExtent(extent vk.Extent2D, scale float32) vk.Extent2D {
+	return vk.Extent2D{
+		Width:  maxUint32(uint32(float32(extent.Width)*scale), 1),
+		Height: maxUint32(uint32(float32(extent.Height)*scale), 1),
+	}
+}
+
+// CreateOffscreenTarget creates a color image of the given extent along with a render pass
+// and a framebuffer for it. The render pass is compatible with the one from CreateRenderer
+// for the same format, so the same pipeline works for both. The image ends up in
+// vk.ImageLayoutTransferSrcOptimal, ready to be blitted.
+func (v *VulkanDeviceInfo) CreateOffscreenTarget(format vk.Format,
+	extent vk.Extent2D) (VulkanOffscreenInfo, error) {
+
+	const requiredFeatures = vk.FormatFeatureBlitSrcBit | vk.FormatFeatureBlitDstBit |
+		vk.FormatFeatureColorAttachmentBit | vk.FormatFeatureSampledImageFilterLinearBit
+	o, err := v.createOffscreenTarget(format, extent, vk.ImageUsageTransferSrcBit,
+		requiredFeatures, vk.ImageLayoutTransferSrcOptimal)
+	if err != nil {
+		err = fmt.Errorf("createOffscreenTarget: %s", err)
+		return o, err
+	}
+	return o, nil
+}
+
+// CreateSampledTarget is like CreateOffscreenTarget, but the image ends up in
+// vk.ImageLayoutShaderReadOnlyOptimal, ready to be sampled by a following pass.
+func (v *VulkanDeviceInfo) CreateSampledTarget(format vk.Format,
+	extent vk.Extent2D) (VulkanOffscreenInfo, error) {
+
+	const requiredFeatures = vk.FormatFeatureColorAttachmentBit |
+		vk.FormatFeatureSampledImageBit | vk.FormatFeatureSampledImageFilterLinearBit
+	o, err := v.createOffscreenTarget(format, extent, vk.ImageUsageSampledBit,
+		requiredFeatures, vk.ImageLayoutShaderReadOnlyOptimal)
+	if err != nil {
+		err = fmt.Errorf("CreateSampledTarget: %s", err)
+		return o, err
+	}
+	return o, nil
+}
+
+func (v *VulkanDeviceInfo) createOffscreenTarget(format vk.Format, extent vk.Extent2D,
+	usage vk.ImageUsageFlagBits, requiredFeatures vk.FormatFeatureFlagBits,
+	finalLayout vk.ImageLayout) (VulkanOffscreenInfo, error) {
+
+	o := VulkanOffscreenInfo{
+		device:      v.device,
+		format:      format,
+		extent:      extent,
+		finalLayout: finalLayout,
+	}
+
+	// Phase 1: vk.GetPhysicalDeviceFormatProperties
+	//			check that the format supports the features required
+
+	var formatProps vk.FormatProperties
+	vk.GetPhysicalDeviceFormatProperties(v.gpu, format, &formatProps)
+	formatProps.Deref()
+	if formatProps.OptimalTilingFeatures&vk.FormatFeatureFlags(requiredFeatures) !=
+		vk.FormatFeatureFlags(requiredFeatures) {
+		err := fmt.Errorf("format %d lacks features %x", format, requiredFeatures)
+		return o, err
+	}
+
+	// Phase 2: vk.CreateImage
+	//			vk.AllocateMemory
+	//			vk.CreateImageView
+
+	imageCreateInfo := vk.ImageCreateInfo{
+		SType:     vk.StructureTypeImageCreateInfo,
+		ImageType: vk.ImageType2d,
+		Format:    format,
+		Extent: vk.Extent3D{
+			Width:  extent.Width,
+			Height: extent.Height,
+			Depth:  1,
+		},
+		MipLevels:     1,
+		ArrayLayers:   1,
+		Samples:       vk.SampleCount1Bit,
+		Tiling:        vk.ImageTilingOptimal,
+		Usage:         vk.ImageUsageFlags(vk.ImageUsageColorAttachmentBit | usage),
+		SharingMode:   vk.SharingModeExclusive,
+		InitialLayout: vk.ImageLayoutUndefined,
+	}
+	if err := validateImageCreateInfo(v.gpu, &imageCreateInfo); err != nil {
+		err = fmt.Errorf("createOffscreenTarget: %s", err)
+		return o, err
+	}
+	err := vk.Error(vk.CreateImage(v.device, &imageCreateInfo, nil, &o.image))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateImage failed with %s", err)
+		return o, err
+	}
+	o.mem, err = allocateTargetMemory(v.device, v.gpu, o.image)
+	if err != nil {
+		o.Destroy()
+		return o, err
+	}
+	viewCreateInfo := vk.ImageViewCreateInfo{
+		SType:    vk.StructureTypeImageViewCreateInfo,
+		Image:    o.image,
+		ViewType: vk.ImageViewType2d,
+		Format:   format,
+		Components: vk.ComponentMapping{
+			R: vk.ComponentSwizzleR,
+			G: vk.ComponentSwizzleG,
+			B: vk.ComponentSwizzleB,
+			A: vk.ComponentSwizzleA,
+		},
+		SubresourceRange: vk.ImageSubresourceRange{
+			AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+			LevelCount: 1,
+			LayerCount: 1,
+		},
+	}
+	err = vk.Error(vk.CreateImageView(v.device, &viewCreateInfo, nil, &o.view))
+	if err != nil {
+		o.Destroy()
+		err = fmt.Errorf("vk.CreateImageView failed with %s", err)
+		return o, err
+	}
+
+	// Phase 3: vk.CreateRenderPass
+	//			vk.CreateFramebuffer
+
+	attachmentDescriptions := []vk.AttachmentDescription{{
+		Format:         format,
+		Samples:        vk.SampleCount1Bit,
+		LoadOp:         vk.AttachmentLoadOpClear,
+		StoreOp:        vk.AttachmentStoreOpStore,
+		StencilLoadOp:  vk.AttachmentLoadOpDontCare,
+		StencilStoreOp: vk.AttachmentStoreOpDontCare,
+		InitialLayout:  vk.ImageLayoutUndefined,
+		FinalLayout:    finalLayout,
+	}}
+	colorAttachments := []vk.AttachmentReference{{
+		Attachment: 0,
+		Layout:     vk.ImageLayoutColorAttachmentOptimal,
+	}}
+	subpassDescriptions := []vk.SubpassDescription{{
+		PipelineBindPoint:    vk.PipelineBindPointGraphics,
+		ColorAttachmentCount: 1,
+		PColorAttachments:    colorAttachments,
+	}}
+	// the previous frame may still be reading from the image, and the next pass
+	// must wait for the rendering to complete before reading from it
+	consumerAccess, consumerStages := layoutAccess(finalLayout)
+	dependencies := []vk.SubpassDependency{{
+		SrcSubpass:    vk.SubpassExternal,
+		DstSubpass:    0,
+		SrcStageMask:  consumerStages,
+		DstStageMask:  vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit),
+		SrcAccessMask: consumerAccess,
+		DstAccessMask: vk.AccessFlags(vk.AccessColorAttachmentWriteBit),
+	}, {
+		SrcSubpass:    0,
+		DstSubpass:    vk.SubpassExternal,
+		SrcStageMask:  vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit),
+		DstStageMask:  consumerStages,
+		SrcAccessMask: vk.AccessFlags(vk.AccessColorAttachmentWriteBit),
+		DstAccessMask: consumerAccess,
+	}}
+	renderPassCreateInfo := vk.RenderPassCreateInfo{
+		SType:           vk.StructureTypeRenderPassCreateInfo,
+		AttachmentCount: 1,
+		PAttachments:    attachmentDescriptions,
+		SubpassCount:    1,
+		PSubpasses:      subpassDescriptions,
+		DependencyCount: uint32(len(dependencies)),
+		PDependencies:   dependencies,
+	}
+	err = vk.Error(vk.CreateRenderPass(v.device, &renderPassCreateInfo, nil, &o.renderPass))
+	if err != nil {
+		o.Destroy()
+		err = fmt.Errorf("vk.CreateRenderPass failed with %s", err)
+		return o, err
+	}
+	fbCreateInfo := vk.FramebufferCreateInfo{
+		SType:           vk.StructureTypeFramebufferCreateInfo,
+		RenderPass:      o.renderPass,
+		Layers:          1,
+		AttachmentCount: 1,
+		PAttachments:    []vk.ImageView{o.view},
+		Width:           extent.Width,
+		Height:          extent.Height,
+	}
+	err = vk.Error(vk.CreateFramebuffer(v.device, &fbCreateInfo, nil, &o.framebuffer))
+	if err != nil {
+		o.Destroy()
+		err = fmt.Errorf("vk.CreateFramebuffer failed with %s", err)
+		return o, err
+	}
+	return o, nil
+}
+
+// cmdBlitToSwapchain downsamples (or upscales) the offscreen image onto the swapchain image
+// with a linear filter, leaving the latter ready for presentation.
+func (o *VulkanOffscreenInfo) cmdBlitToSwapchain(cmd vk.CommandBuffer,
+	dstImage vk.Image, dstExtent vk.Extent2D) {
+
+	colorRange := vk.ImageSubresourceRange{
+		AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+		LevelCount: 1,
+		LayerCount: 1,
+	}
+	colorLayers := vk.ImageSubresourceLayers{
+		AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+		LayerCount: 1,
+	}
+	transitionImageLayout(cmd, dstImage, colorRange,
+		vk.ImageLayoutUndefined, vk.ImageLayoutTransferDstOptimal)
+	regions := []vk.ImageBlit{{
+		SrcSubresource: colorLayers,
+		SrcOffsets: [2]vk.Offset3D{{}, {
+			X: int32(o.extent.Width),
+			Y: int32(o.extent.Height),
+			Z: 1,
+		}},
+		DstSubresource: colorLayers,
+		DstOffsets: [2]vk.Offset3D{{}, {
+			X: int32(dstExtent.Width),
+			Y: int32(dstExtent.Height),
+			Z: 1,
+		}},
+	}}
+	vk.CmdBlitImage(cmd, o.image, vk.ImageLayoutTransferSrcOptimal,
+		dstImage, vk.ImageLayoutTransferDstOptimal, 1, regions, vk.FilterLinear)
+	transitionImageLayout(cmd, dstImage, colorRange,
+		vk.ImageLayoutTransferDstOptimal, vk.ImageLayoutPresentSrc)
+}
+
+func (o *VulkanOffscreenInfo) Destroy() {
+	if o == nil || o.device == nil {
+		return
+	}
+	vk.DestroyFramebuffer(o.device, o.framebuffer, nil)
+	vk.DestroyRenderPass(o.device, o.renderPass, nil)
+	vk.DestroyImageView(o.device, o.view, nil)
+	vk.DestroyImage(o.device, o.image, nil)
+	vk.FreeMemory(o.device, o.mem, nil)
+}
+
+// UseOffscreen makes the command buffers render into the offscreen target and blit
+// the result onto the swapchain images, nil switches back to direct rendering.
+// Swapchain must be created with vk.ImageUsageTransferDstBit.
+func (r *VulkanRenderInfo) UseOffscreen(o *VulkanOffscreenInfo) {
+	r.offscreen = o
+}
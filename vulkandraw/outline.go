@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// OutlineShaders draw the scaled up copy of the shape in a solid color, both come from
+// push constants, see VulkanOutlineInfo. The shaders must be built with make shaders.
+var OutlineShaders = []ShaderStage{
+	{Stage: vk.ShaderStageVertexBit, Asset: "shaders/outline-vert.spv"},
+	{Stage: vk.ShaderStageFragmentBit, Asset: "shaders/outline-frag.spv"},
+}
+
+// outlinePushConstantsSize is the size of the Outline block of the outline shaders,
+// a vec4 color followed by a float scale.
+const outlinePushConstantsSize = 5 * 4 // 4 = sizeof(float32)
+
+// hasStencil reports whether the depth format has a stencil aspect.
+func hasStencil(format vk.Format) bool {
+	switch format {
+	case vk.FormatS8Uint, vk.FormatD16UnormS8Uint,
+		vk.FormatD24UnormS8Uint, vk.FormatD32SfloatS8Uint:
+		return true
+	default:
+		return false
+	}
+}
+
+// StencilWriteState is the depth state with ref written into the stencil buffer
+// wherever the shape gets drawn, whatever the outcome of the depth test.
+func StencilWriteState(depth DepthState, ref uint32) DepthState {
+	depth.StencilTestEnable = true
+	depth.Stencil = vk.StencilOpState{
+		FailOp:      vk.StencilOpReplace,
+		PassOp:      vk.StencilOpReplace,
+		DepthFailOp: vk.StencilOpReplace,
+		CompareOp:   vk.CompareOpAlways,
+		CompareMask: 0xff,
+		WriteMask:   0xff,
+		Reference:   ref,
+	}
+	return depth
+}
+
+// StencilTestState draws only where the stencil buffer doesn't hold ref and leaves
+// it untouched. Depth isn't tested, so the outline shows around hidden shapes too.
+func StencilTestState(ref uint32) DepthState {
+	return DepthState{
+		CompareOp:         vk.CompareOpAlways,
+		StencilTestEnable: true,
+		Stencil: vk.StencilOpState{
+			FailOp:      vk.StencilOpKeep,
+			PassOp:      vk.StencilOpKeep,
+			DepthFailOp: vk.StencilOpKeep,
+			CompareOp:   vk.CompareOpNotEqual,
+			CompareMask: 0xff,
+			WriteMask:   0,
+			Reference:   ref,
+		},
+	}
+}
+
+// VulkanOutlineInfo draws a shape with a solid outline around it. The shape writes
+// the stencil buffer first, then a copy scaled up by Scale gets drawn in Color
+// only where the stencil isn't set, i.e. outside the shape.
+type VulkanOutlineInfo struct {
+	device vk.Device
+
+	// layout is shared by both pipelines, it holds the push constants of OutlineShaders
+	layout  vk.PipelineLayout
+	shape   VulkanGfxPipelineInfo
+	outline VulkanGfxPipelineInfo
+
+	Color [4]float32
+	Scale float32
+}
+
+// CreateOutlinePipelines creates the stencil writing pipeline of the shape, with the shaders
+// or TriangleShaders when empty, and the stencil testing one of the outline.
+// The render pass subpass must have a depth-stencil attachment of depthFormat,
+// see FindDepthStencilFormat.
+func (v *VulkanDeviceInfo) CreateOutlinePipelines(displaySize vk.Extent2D,
+	renderPass vk.RenderPass, colorAttachments int, depthFormat vk.Format,
+	vertex VertexLayout, shaders []ShaderStage) (VulkanOutlineInfo, error) {
+
+	o := VulkanOutlineInfo{
+		device: v.device,
+		Color:  [4]float32{1, 1, 1, 1},
+		Scale:  1.1,
+	}
+	if !hasStencil(depthFormat) {
+		err := fmt.Errorf("CreateOutlinePipelines: format %d has no stencil, a depth-stencil attachment is needed",
+			depthFormat)
+		return o, err
+	}
+	const ref = 1
+
+	// Phase 1: vk.CreatePipelineLayout
+	//			shared by both pipelines
+
+	pushConstants := []vk.PushConstantRange{{
+		StageFlags: vk.ShaderStageFlags(vk.ShaderStageVertexBit | vk.ShaderStageFragmentBit),
+		Size:       outlinePushConstantsSize,
+	}}
+	var err error
+	o.layout, err = v.CreatePipelineLayout(nil, pushConstants)
+	if err != nil {
+		return o, err
+	}
+
+	// Phase 2: vk.CreateGraphicsPipelines
+	//			the shape writing the stencil and the outline testing it
+
+	o.shape, err = CreateGraphicsPipeline(v.device, displaySize, renderPass, o.layout,
+		colorAttachments, StencilWriteState(DefaultDepthState(), ref),
+		DefaultMultisampleState(), vertex, shaders)
+	if err != nil {
+		o.Destroy()
+		return o, err
+	}
+	o.outline, err = CreateGraphicsPipeline(v.device, displaySize, renderPass, o.layout,
+		colorAttachments, StencilTestState(ref), DefaultMultisampleState(),
+		vertex, OutlineShaders)
+	if err != nil {
+		o.Destroy()
+		return o, err
+	}
+	return o, nil
+}
+
+// RecreatePipelines rebuilds both pipelines for a new extent or render pass, see RecreatePipeline.
+func (o *VulkanOutlineInfo) RecreatePipelines(displaySize vk.Extent2D, renderPass vk.RenderPass) error {
+	if err := RecreatePipeline(&o.shape, o.device, displaySize, renderPass); err != nil {
+		return err
+	}
+	return RecreatePipeline(&o.outline, o.device, displaySize, renderPass)
+}
+
+// CmdDraw draws the shape from the buffers and then its outline, it must be called within
+// a render pass whose depth-stencil attachment got cleared.
+func (o *VulkanOutlineInfo) CmdDraw(cmd vk.CommandBuffer, b *VulkanBufferInfo) {
+	offsets := make([]vk.DeviceSize, len(b.vertexBuffers))
+	vk.CmdBindVertexBuffers(cmd, 0, uint32(len(b.vertexBuffers)), b.vertexBuffers, offsets)
+	vk.CmdBindPipeline(cmd, vk.PipelineBindPointGraphics, o.shape.pipeline)
+	b.cmdDraw(cmd)
+
+	pushConstants := [5]float32{o.Color[0], o.Color[1], o.Color[2], o.Color[3], o.Scale}
+	vk.CmdBindPipeline(cmd, vk.PipelineBindPointGraphics, o.outline.pipeline)
+	vk.CmdPushConstants(cmd, o.layout,
+		vk.ShaderStageFlags(vk.ShaderStageVertexBit|vk.ShaderStageFragmentBit),
+		0, outlinePushConstantsSize, unsafe.Pointer(&pushConstants[0]))
+	b.cmdDraw(cmd)
+}
+
+func (o *VulkanOutlineInfo) Destroy() {
+	if o == nil || o.device == nil {
+		return
+	}
+	// either pipeline may be missing when creating them failed
+	if o.shape.device != nil {
+		o.shape.Destroy()
+	}
+	if o.outline.device != nil {
+		o.outline.Destroy()
+	}
+	vk.DestroyPipelineLayout(o.device, o.layout, nil)
+}
@@ -0,0 +1,29 @@
+package main
+
+import vk "github.com/vulkan-go/vulkan"
+
+// OverlayFrame carries the state an overlay needs to record its draw commands.
+type OverlayFrame struct {
+	// Cmd is the command buffer being recorded, the render pass is active.
+	Cmd vk.CommandBuffer
+	// Extent is the size of the render area, use it for the projection
+	// and to clip the scissor rects.
+	Extent vk.Extent2D
+	// ImageIndex is the swapchain image index this command buffer draws into,
+	// handy to pick per-frame vertex buffers uploaded from CPU.
+	ImageIndex int
+	// RenderPass the overlay pipeline must be compatible with.
+	RenderPass vk.RenderPass
+}
+
+// OverlayFunc records additional draw commands on top of the scene, e.g. an
+// immediate-mode UI like dear imgui. It's invoked right before vk.CmdEndRenderPass,
+// so it must bind its own pipeline, vertex buffers and set the scissor if the
+// pipeline has it as a dynamic state.
+type OverlayFunc func(frame OverlayFrame)
+
+// SetOverlay registers an overlay hook, nil disables it. When an overlay is set,
+// the command buffer of each acquired image gets re-recorded every frame.
+func (r *VulkanRenderInfo) SetOverlay(fn OverlayFunc) {
+	r.overlay = fn
+}
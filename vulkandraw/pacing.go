@@ -0,0 +1,38 @@
+package main
+
+import "time"
+
+// FramePacer caps the frame rate, it sleeps off what remains of the frame
+// interval after the frame has been rendered.
+type FramePacer struct {
+	interval time.Duration
+	deadline time.Time
+}
+
+// NewFramePacer makes a pacer for the target frame rate, 0 means uncapped.
+func NewFramePacer(targetFPS int) *FramePacer {
+	p := &FramePacer{}
+	if targetFPS > 0 {
+		p.interval = time.Second / time.Duration(targetFPS)
+	}
+	return p
+}
+
+// Wait blocks until the current frame interval ends. Deadlines advance by the
+// interval, so the time spent rendering counts towards it and the rate doesn't
+// drift, but a frame that took too long doesn't make the next ones rush.
+func (p *FramePacer) Wait() {
+	if p.interval == 0 {
+		return
+	}
+	now := time.Now()
+	if p.deadline.IsZero() {
+		p.deadline = now
+	}
+	p.deadline = p.deadline.Add(p.interval)
+	if p.deadline.Before(now) {
+		p.deadline = now
+		return
+	}
+	time.Sleep(p.deadline.Sub(now))
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// ParticleComputeShader moves the particles of the storage buffer by their velocity
+// and bounces them off the edges of the viewport. The shaders must be built with make shaders.
+const ParticleComputeShader = "shaders/particles-comp.spv"
+
+// ParticleShaders draw each particle as a point colored by its speed, see ParticleLayout.
+var ParticleShaders = []ShaderStage{
+	{Stage: vk.ShaderStageVertexBit, Asset: "shaders/particles-vert.spv"},
+	{Stage: vk.ShaderStageFragmentBit, Asset: "shaders/particles-frag.spv"},
+}
+
+const (
+	// particleSize is the size of a particle, a vec2 position followed by a vec2 velocity.
+	particleSize = 4 * 4 // 4 = sizeof(float32)
+	// particleStepSize is the size of the Step block of the compute shader,
+	// a float time step followed by a uint particle count.
+	particleStepSize = 2 * 4
+	// particleGroupSize is the local_size_x of the compute shader.
+	particleGroupSize = 64
+)
+
+// ParticleLayout reads the particles straight from the storage buffer, the position
+// at location 0 and the velocity at location 1, and draws them as points.
+func ParticleLayout() VertexLayout {
+	return VertexLayout{
+		Bindings: []vk.VertexInputBindingDescription{{
+			Binding:   0,
+			Stride:    particleSize,
+			InputRate: vk.VertexInputRateVertex,
+		}},
+		Attributes: []vk.VertexInputAttributeDescription{{
+			Binding:  0,
+			Location: 0,
+			Format:   vk.FormatR32g32Sfloat,
+		}, {
+			Binding:  0,
+			Location: 1,
+			Format:   vk.FormatR32g32Sfloat,
+			Offset:   2 * 4,
+		}},
+		Points: true,
+	}
+}
+
+// VulkanParticlesInfo is a particle system living in a single buffer: a compute pipeline
+// moves the particles each frame and a graphics pipeline draws the same buffer as a vertex
+// buffer, so the particles never leave the GPU.
+type VulkanParticlesInfo struct {
+	device vk.Device
+	count  uint32
+
+	// buffer is both a storage buffer of the compute shader and a vertex buffer
+	buffer vk.Buffer
+	mem    vk.DeviceMemory
+
+	descLayout vk.DescriptorSetLayout
+	descPool   vk.DescriptorPool
+	descSet    vk.DescriptorSet
+
+	computeLayout vk.PipelineLayout
+	compute       vk.Pipeline
+	graphics      VulkanGfxPipelineInfo
+}
+
+// CreateParticles creates count particles scattered over the viewport with random velocities,
+// the compute pipeline updating them and the pipeline drawing them in the render pass.
+// The updates get recorded into the command buffers of the graphics queue, so its family
+// must support compute too.
+func (v *VulkanDeviceInfo) CreateParticles(count uint32, displaySize vk.Extent2D,
+	renderPass vk.RenderPass, colorAttachments int) (VulkanParticlesInfo, error) {
+
+	p := VulkanParticlesInfo{
+		device: v.device,
+		count:  count,
+	}
+	if count == 0 {
+		err := fmt.Errorf("CreateParticles: no particles to create")
+		return p, err
+	}
+	family, ok := v.QueueFamily(QueueGraphics)
+	if !ok {
+		err := fmt.Errorf("CreateParticles: the device has no graphics queue")
+		return p, err
+	}
+	queueProps := getQueueFamilyProperties(v.gpu)
+	if queueProps[family].QueueFlags&vk.QueueFlags(vk.QueueComputeBit) == 0 {
+		err := fmt.Errorf("CreateParticles: graphics queue family %d doesn't support compute", family)
+		return p, err
+	}
+
+	// Phase 1: vk.CreateBuffer
+	//			vk.MapMemory
+	//			the initial particles are written by the host once
+
+	size := vk.DeviceSize(count) * particleSize
+	var err error
+	p.buffer, p.mem, err = v.createBuffer(size,
+		vk.BufferUsageStorageBufferBit|vk.BufferUsageVertexBufferBit,
+		vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+	if err != nil {
+		return p, err
+	}
+	particles := make([]float32, 0, count*4)
+	for i := uint32(0); i < count; i++ {
+		angle := rand.Float64() * 2 * math.Pi
+		speed := 0.1 + 0.4*rand.Float64()
+		particles = append(particles,
+			2*rand.Float32()-1, 2*rand.Float32()-1,
+			float32(speed*math.Cos(angle)), float32(speed*math.Sin(angle)))
+	}
+	var data unsafe.Pointer
+	err = vk.Error(vk.MapMemory(v.device, p.mem, 0, size, 0, &data))
+	if err != nil {
+		p.Destroy()
+		err = fmt.Errorf("vk.MapMemory failed with %s", err)
+		return p, err
+	}
+	vk.MemCopyFloat32(data, particles)
+	vk.UnmapMemory(v.device, p.mem)
+
+	// Phase 2: vk.CreateDescriptorSetLayout
+	//			vk.AllocateDescriptorSets
+	//			vk.UpdateDescriptorSets
+
+	bindings := []DescriptorBinding{{
+		Binding: 0,
+		Type:    vk.DescriptorTypeStorageBuffer,
+		Stages:  vk.ShaderStageComputeBit,
+	}}
+	p.descLayout, err = CreateDescriptorSetLayout(v.device, bindings)
+	if err != nil {
+		p.Destroy()
+		return p, err
+	}
+	p.descPool, err = CreateDescriptorPool(v.device, 1, false, bindings)
+	if err != nil {
+		p.Destroy()
+		return p, err
+	}
+	descSetAllocInfo := vk.DescriptorSetAllocateInfo{
+		SType:              vk.StructureTypeDescriptorSetAllocateInfo,
+		DescriptorPool:     p.descPool,
+		DescriptorSetCount: 1,
+		PSetLayouts:        []vk.DescriptorSetLayout{p.descLayout},
+	}
+	err = vk.Error(vk.AllocateDescriptorSets(v.device, &descSetAllocInfo, &p.descSet))
+	if err != nil {
+		p.Destroy()
+		err = fmt.Errorf("vk.AllocateDescriptorSets failed with %s", err)
+		return p, err
+	}
+	writes := []vk.WriteDescriptorSet{{
+		SType:           vk.StructureTypeWriteDescriptorSet,
+		DstSet:          p.descSet,
+		DstBinding:      0,
+		DescriptorCount: 1,
+		DescriptorType:  vk.DescriptorTypeStorageBuffer,
+		PBufferInfo: []vk.DescriptorBufferInfo{{
+			Buffer: p.buffer,
+			Range:  size,
+		}},
+	}}
+	vk.UpdateDescriptorSets(v.device, uint32(len(writes)), writes, 0, nil)
+
+	// Phase 3: vk.CreatePipelineLayout
+	//			vk.CreateComputePipelines
+
+	pushConstants := []vk.PushConstantRange{{
+		StageFlags: vk.ShaderStageFlags(vk.ShaderStageComputeBit),
+		Size:       particleStepSize,
+	}}
+	p.computeLayout, err = v.CreatePipelineLayout([]vk.DescriptorSetLayout{p.descLayout}, pushConstants)
+	if err != nil {
+		p.Destroy()
+		return p, err
+	}
+	computeShader, err := LoadShader(v.device, ParticleComputeShader)
+	if err != nil { // err has enough info
+		p.Destroy()
+		return p, err
+	}
+	defer vk.DestroyShaderModule(v.device, computeShader, nil)
+	pipelineCreateInfos := []vk.ComputePipelineCreateInfo{{
+		SType: vk.StructureTypeComputePipelineCreateInfo,
+		Stage: vk.PipelineShaderStageCreateInfo{
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  vk.ShaderStageComputeBit,
+			Module: computeShader,
+			PName:  "main\x00",
+		},
+		Layout: p.computeLayout,
+	}}
+	pipelines := make([]vk.Pipeline, 1)
+	err = vk.Error(vk.CreateComputePipelines(v.device,
+		vk.NullHandle, 1, pipelineCreateInfos, nil, pipelines))
+	if err != nil {
+		p.Destroy()
+		err = fmt.Errorf("vk.CreateComputePipelines failed with %s", err)
+		return p, err
+	}
+	p.compute = pipelines[0]
+
+	// Phase 4: vk.CreateGraphicsPipelines
+	//			the points drawn from the same buffer
+
+	p.graphics, err = CreateGraphicsPipeline(v.device, displaySize, renderPass, vk.NullHandle,
+		colorAttachments, DefaultDepthState(), DefaultMultisampleState(),
+		ParticleLayout(), ParticleShaders)
+	if err != nil {
+		p.Destroy()
+		return p, err
+	}
+	return p, nil
+}
+
+// RecreatePipeline rebuilds the graphics pipeline for a new extent or render pass,
+// the compute pipeline doesn't depend on either.
+func (p *VulkanParticlesInfo) RecreatePipeline(displaySize vk.Extent2D, renderPass vk.RenderPass) error {
+	return RecreatePipeline(&p.graphics, p.device, displaySize, renderPass)
+}
+
+// CmdUpdate moves the particles by dt seconds, it must be called outside of a render pass
+// and before CmdDraw. The barriers order the compute shader after the vertex reads of the
+// previous frame and the vertex reads of this frame after the compute shader writes.
+func (p *VulkanParticlesInfo) CmdUpdate(cmd vk.CommandBuffer, dt float32) {
+	readBarriers := []vk.BufferMemoryBarrier{{
+		SType:               vk.StructureTypeBufferMemoryBarrier,
+		SrcAccessMask:       vk.AccessFlags(vk.AccessVertexAttributeReadBit),
+		DstAccessMask:       vk.AccessFlags(vk.AccessShaderWriteBit),
+		SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+		DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+		Buffer:              p.buffer,
+		Size:                vk.DeviceSize(p.count) * particleSize,
+	}}
+	vk.CmdPipelineBarrier(cmd, vk.PipelineStageFlags(vk.PipelineStageVertexInputBit),
+		vk.PipelineStageFlags(vk.PipelineStageComputeShaderBit), 0, 0, nil, 1, readBarriers, 0, nil)
+
+	step := struct {
+		dt    float32
+		count uint32
+	}{dt, p.count}
+	vk.CmdBindPipeline(cmd, vk.PipelineBindPointCompute, p.compute)
+	vk.CmdBindDescriptorSets(cmd, vk.PipelineBindPointCompute, p.computeLayout,
+		0, 1, []vk.DescriptorSet{p.descSet}, 0, nil)
+	vk.CmdPushConstants(cmd, p.computeLayout, vk.ShaderStageFlags(vk.ShaderStageComputeBit),
+		0, particleStepSize, unsafe.Pointer(&step))
+	vk.CmdDispatch(cmd, (p.count+particleGroupSize-1)/particleGroupSize, 1, 1)
+
+	writeBarriers := []vk.BufferMemoryBarrier{{
+		SType:               vk.StructureTypeBufferMemoryBarrier,
+		SrcAccessMask:       vk.AccessFlags(vk.AccessShaderWriteBit),
+		DstAccessMask:       vk.AccessFlags(vk.AccessVertexAttributeReadBit),
+		SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+		DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+		Buffer:              p.buffer,
+		Size:                vk.DeviceSize(p.count) * particleSize,
+	}}
+	vk.CmdPipelineBarrier(cmd, vk.PipelineStageFlags(vk.PipelineStageComputeShaderBit),
+		vk.PipelineStageFlags(vk.PipelineStageVertexInputBit), 0, 0, nil, 1, writeBarriers, 0, nil)
+}
+
+// CmdDraw draws the particles as points, it must be called within the render pass.
+func (p *VulkanParticlesInfo) CmdDraw(cmd vk.CommandBuffer) {
+	vk.CmdBindPipeline(cmd, vk.PipelineBindPointGraphics, p.graphics.pipeline)
+	vk.CmdBindVertexBuffers(cmd, 0, 1, []vk.Buffer{p.buffer}, []vk.DeviceSize{0})
+	vk.CmdDraw(cmd, p.count, 1, 0, 0)
+}
+
+func (p *VulkanParticlesInfo) Destroy() {
+	if p == nil || p.device == nil {
+		return
+	}
+	// the graphics pipeline is missing when creating it failed
+	if p.graphics.device != nil {
+		p.graphics.Destroy()
+	}
+	vk.DestroyPipeline(p.device, p.compute, nil)
+	vk.DestroyPipelineLayout(p.device, p.computeLayout, nil)
+	// descriptor sets are freed along with the pool
+	vk.DestroyDescriptorPool(p.device, p.descPool, nil)
+	vk.DestroyDescriptorSetLayout(p.device, p.descLayout, nil)
+	vk.DestroyBuffer(p.device, p.buffer, nil)
+	vk.FreeMemory(p.device, p.mem, nil)
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// VulkanPostInfo is the second pass of a two-pass setup: the scene is rendered into
+// a sampled target first, then a fullscreen triangle samples it onto the swapchain image.
+type VulkanPostInfo struct {
+	device vk.Device
+	target *VulkanOffscreenInfo
+
+	sampler    vk.Sampler
+	descLayout vk.DescriptorSetLayout
+	descPool   vk.DescriptorPool
+	descSet    vk.DescriptorSet
+
+	layout   vk.PipelineLayout
+	cache    vk.PipelineCache
+	pipeline vk.Pipeline
+}
+
+// CreatePostPipeline creates the pipeline of the second pass, target must come from
+// CreateSampledTarget and renderPass is the one drawing to the swapchain images.
+func CreatePostPipeline(device vk.Device, displaySize vk.Extent2D,
+	renderPass vk.RenderPass, target *VulkanOffscreenInfo) (VulkanPostInfo, error) {
+
+	post := VulkanPostInfo{
+		device: device,
+		target: target,
+	}
+
+	// Phase 1: vk.CreateSampler
+	//			vk.CreateDescriptorSetLayout
+	//			vk.CreatePipelineLayout
+
+	samplerCreateInfo := vk.SamplerCreateInfo{
+		SType:         vk.StructureTypeSamplerCreateInfo,
+		MagFilter:     vk.FilterLinear,
+		MinFilter:     vk.FilterLinear,
+		MipmapMode:    vk.SamplerMipmapModeNearest,
+		AddressModeU:  vk.SamplerAddressModeClampToEdge,
+		AddressModeV:  vk.SamplerAddressModeClampToEdge,
+		AddressModeW:  vk.SamplerAddressModeClampToEdge,
+		MaxAnisotropy: 1,
+		CompareOp:     vk.CompareOpNever,
+		BorderColor:   vk.BorderColorFloatOpaqueBlack,
+	}
+	err := vk.Error(vk.CreateSampler(device, &samplerCreateInfo, nil, &post.sampler))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateSampler failed with %s", err)
+		return post, err
+	}
+	bindings := []DescriptorBinding{{
+		Binding: 0,
+		Type:    vk.DescriptorTypeCombinedImageSampler,
+		Stages:  vk.ShaderStageFragmentBit,
+	}}
+	post.descLayout, err = CreateDescriptorSetLayout(device, bindings)
+	if err != nil {
+		post.Destroy()
+		return post, err
+	}
+	pipelineLayoutCreateInfo := vk.PipelineLayoutCreateInfo{
+		SType:          vk.StructureTypePipelineLayoutCreateInfo,
+		SetLayoutCount: 1,
+		PSetLayouts:    []vk.DescriptorSetLayout{post.descLayout},
+	}
+	err = vk.Error(vk.CreatePipelineLayout(device, &pipelineLayoutCreateInfo, nil, &post.layout))
+	if err != nil {
+		post.Destroy()
+		err = fmt.Errorf("vk.CreatePipelineLayout failed with %s", err)
+		return post, err
+	}
+
+	// Phase 2: vk.CreateDescriptorPool
+	//			vk.AllocateDescriptorSets
+	//			vk.UpdateDescriptorSets
+
+	post.descPool, err = CreateDescriptorPool(device, 1, false, bindings)
+	if err != nil {
+		post.Destroy()
+		return post, err
+	}
+	descSetAllocInfo := vk.DescriptorSetAllocateInfo{
+		SType:              vk.StructureTypeDescriptorSetAllocateInfo,
+		DescriptorPool:     post.descPool,
+		DescriptorSetCount: 1,
+		PSetLayouts:        []vk.DescriptorSetLayout{post.descLayout},
+	}
+	err = vk.Error(vk.AllocateDescriptorSets(device, &descSetAllocInfo, &post.descSet))
+	if err != nil {
+		post.Destroy()
+		err = fmt.Errorf("vk.AllocateDescriptorSets failed with %s", err)
+		return post, err
+	}
+	writes := []vk.WriteDescriptorSet{{
+		SType:           vk.StructureTypeWriteDescriptorSet,
+		DstSet:          post.descSet,
+		DstBinding:      0,
+		DescriptorCount: 1,
+		DescriptorType:  vk.DescriptorTypeCombinedImageSampler,
+		PImageInfo: []vk.DescriptorImageInfo{{
+			Sampler:     post.sampler,
+			ImageView:   target.view,
+			ImageLayout: vk.ImageLayoutShaderReadOnlyOptimal,
+		}},
+	}}
+	vk.UpdateDescriptorSets(device, uint32(len(writes)), writes, 0, nil)
+
+	// Phase 3: load shaders and specify shader stages
+
+	vertexShader, err := LoadShader(device, FullscreenShader)
+	if err != nil { // err has enough info
+		post.Destroy()
+		return post, err
+	}
+	defer vk.DestroyShaderModule(device, vertexShader, nil)
+
+	fragmentShader, err := LoadShader(device, PostFragmentShader)
+	if err != nil { // err has enough info
+		post.Destroy()
+		return post, err
+	}
+	defer vk.DestroyShaderModule(device, fragmentShader, nil)
+
+	shaderStages := []vk.PipelineShaderStageCreateInfo{
+		{
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  vk.ShaderStageVertexBit,
+			Module: vertexShader,
+			PName:  "main\x00",
+		},
+		{
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  vk.ShaderStageFragmentBit,
+			Module: fragmentShader,
+			PName:  "main\x00",
+		},
+	}
+
+	// Phase 4: specify fixed function state,
+	//			the fullscreen triangle is generated from the vertex index
+
+	viewports := []vk.Viewport{{
+		MinDepth: 0.0,
+		MaxDepth: 1.0,
+		Width:    float32(displaySize.Width),
+		Height:   float32(displaySize.Height),
+	}}
+	scissors := []vk.Rect2D{{
+		Extent: displaySize,
+	}}
+	viewportState := vk.PipelineViewportStateCreateInfo{
+		SType:         vk.StructureTypePipelineViewportStateCreateInfo,
+		ViewportCount: 1,
+		PViewports:    viewports,
+		ScissorCount:  1,
+		PScissors:     scissors,
+	}
+	multisampleState := vk.PipelineMultisampleStateCreateInfo{
+		SType:                vk.StructureTypePipelineMultisampleStateCreateInfo,
+		RasterizationSamples: vk.SampleCount1Bit,
+		SampleShadingEnable:  vk.False,
+		PSampleMask:          []vk.SampleMask{vk.SampleMask(vk.MaxUint32)},
+	}
+	attachmentStates := []vk.PipelineColorBlendAttachmentState{{
+		ColorWriteMask: vk.ColorComponentFlags(
+			vk.ColorComponentRBit | vk.ColorComponentGBit |
+				vk.ColorComponentBBit | vk.ColorComponentABit,
+		),
+		BlendEnable: vk.False,
+	}}
+	colorBlendState := vk.PipelineColorBlendStateCreateInfo{
+		SType:           vk.StructureTypePipelineColorBlendStateCreateInfo,
+		LogicOpEnable:   vk.False,
+		LogicOp:         vk.LogicOpCopy,
+		AttachmentCount: 1,
+		PAttachments:    attachmentStates,
+	}
+	rasterState := vk.PipelineRasterizationStateCreateInfo{
+		SType:                   vk.StructureTypePipelineRasterizationStateCreateInfo,
+		DepthClampEnable:        vk.False,
+		RasterizerDiscardEnable: vk.False,
+		PolygonMode:             vk.PolygonModeFill,
+		CullMode:                vk.CullModeFlags(vk.CullModeNone),
+		FrontFace:               vk.FrontFaceCounterClockwise,
+		DepthBiasEnable:         vk.False,
+		LineWidth:               1,
+	}
+	inputAssemblyState := vk.PipelineInputAssemblyStateCreateInfo{
+		SType:                  vk.StructureTypePipelineInputAssemblyStateCreateInfo,
+		Topology:               vk.PrimitiveTopologyTriangleList,
+		PrimitiveRestartEnable: vk.False,
+	}
+	// no vertex bindings nor attributes, see FullscreenShader
+	vertexInputState := vk.PipelineVertexInputStateCreateInfo{
+		SType: vk.StructureTypePipelineVertexInputStateCreateInfo,
+	}
+	dynamicState := vk.PipelineDynamicStateCreateInfo{
+		SType: vk.StructureTypePipelineDynamicStateCreateInfo,
+	}
+
+	// Phase 5: vk.CreatePipelineCache
+	//			vk.CreateGraphicsPipelines
+
+	pipelineCacheInfo := vk.PipelineCacheCreateInfo{
+		SType: vk.StructureTypePipelineCacheCreateInfo,
+	}
+	err = vk.Error(vk.CreatePipelineCache(device, &pipelineCacheInfo, nil, &post.cache))
+	if err != nil {
+		post.Destroy()
+		err = fmt.Errorf("vk.CreatePipelineCache failed with %s", err)
+		return post, err
+	}
+	pipelineCreateInfos := []vk.GraphicsPipelineCreateInfo{{
+		SType:               vk.StructureTypeGraphicsPipelineCreateInfo,
+		StageCount:          2, // vert + frag
+		PStages:             shaderStages,
+		PVertexInputState:   &vertexInputState,
+		PInputAssemblyState: &inputAssemblyState,
+		PViewportState:      &viewportState,
+		PRasterizationState: &rasterState,
+		PMultisampleState:   &multisampleState,
+		PColorBlendState:    &colorBlendState,
+		PDynamicState:       &dynamicState,
+		Layout:              post.layout,
+		RenderPass:          renderPass,
+	}}
+	pipelines := make([]vk.Pipeline, 1)
+	err = vk.Error(vk.CreateGraphicsPipelines(device,
+		post.cache, 1, pipelineCreateInfos, nil, pipelines))
+	if err != nil {
+		post.Destroy()
+		err = fmt.Errorf("vk.CreateGraphicsPipelines failed with %s", err)
+		return post, err
+	}
+	post.pipeline = pipelines[0]
+	return post, nil
+}
+
+// cmdDraw samples the target onto the framebuffer, must be called within the render pass.
+func (post *VulkanPostInfo) cmdDraw(cmd vk.CommandBuffer) {
+	vk.CmdBindDescriptorSets(cmd, vk.PipelineBindPointGraphics, post.layout,
+		0, 1, []vk.DescriptorSet{post.descSet}, 0, nil)
+	CmdDrawFullscreen(cmd, post.pipeline)
+}
+
+// FullscreenShader is the vertex shader of fullscreen passes, e.g. post-processing,
+// tone mapping or blits. It generates a triangle covering the viewport from gl_VertexIndex
+// and passes uvs at location 0, the pipeline must have an empty vertex input state.
+const FullscreenShader = "shaders/fullscreen-vert.spv"
+
+// PostFragmentShader samples the scene in the post pass, see CreatePostPipeline.
+const PostFragmentShader = "shaders/post-frag.spv"
+
+// CmdDrawFullscreen binds a pipeline using FullscreenShader and draws the fullscreen
+// triangle without any vertex buffer, it must be called within a render pass.
+func CmdDrawFullscreen(cmd vk.CommandBuffer, pipeline vk.Pipeline) {
+	vk.CmdBindPipeline(cmd, vk.PipelineBindPointGraphics, pipeline)
+	vk.CmdDraw(cmd, 3, 1, 0, 0)
+}
+
+func (post *VulkanPostInfo) Destroy() {
+	if post == nil || post.device == nil {
+		return
+	}
+	vk.DestroyPipeline(post.device, post.pipeline, nil)
+	vk.DestroyPipelineCache(post.device, post.cache, nil)
+	vk.DestroyPipelineLayout(post.device, post.layout, nil)
+	// descriptor sets are freed along with the pool
+	vk.DestroyDescriptorPool(post.device, post.descPool, nil)
+	vk.DestroyDescriptorSetLayout(post.device, post.descLayout, nil)
+	vk.DestroySampler(post.device, post.sampler, nil)
+}
+
+// UsePostProcess makes the command buffers render the scene into the post target
+// and then draw the post pass onto the swapchain images, nil switches back to
+// direct rendering. It takes precedence over UseOffscreen.
+func (r *VulkanRenderInfo) UsePostProcess(post *VulkanPostInfo) {
+	r.post = post
+}
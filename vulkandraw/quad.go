@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// QuadLayout is the layout of CreateQuadBuffers, positions at location 0
+// interleaved with uvs at location 1.
+func QuadLayout() VertexLayout {
+	layout, _ := InterleavedLayout(vk.FormatR32g32b32Sfloat, vk.FormatR32g32Sfloat)
+	return layout
+}
+
+// CreateQuadBuffers creates a fullscreen quad of 4 vertices with uvs for texturing,
+// drawn as two triangles from a 6 indices buffer. The triangles wind clockwise
+// on screen like the one of CreateBuffers, matching vk.FrontFaceClockwise of the
+// triangle pipeline, and the uvs have their origin at the top left like the images.
+func (v *VulkanDeviceInfo) CreateQuadBuffers() (VulkanBufferInfo, error) {
+	vertexData := []float32{
+		-1, -1, 0, 0, 0, // top left
+		1, -1, 0, 1, 0, // top right
+		1, 1, 0, 1, 1, // bottom right
+		-1, 1, 0, 0, 1, // bottom left
+	}
+	indices := []uint16{
+		0, 1, 2,
+		2, 3, 0,
+	}
+	buffer := VulkanBufferInfo{
+		device:      v.device,
+		layout:      QuadLayout(),
+		vertexCount: 4,
+	}
+
+	// Phase 1: vk.CreateBuffer
+	//			vk.MapMemory
+	//			create the vertex buffer and copy the vertices
+
+	size := vk.DeviceSize(4 * len(vertexData)) // 4 = sizeof(float32)
+	buf, mem, err := v.createBuffer(size, vk.BufferUsageVertexBufferBit,
+		vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+	if err != nil {
+		return buffer, err
+	}
+	buffer.vertexBuffers = []vk.Buffer{buf}
+	buffer.vertexMems = []vk.DeviceMemory{mem}
+	var data unsafe.Pointer
+	err = vk.Error(vk.MapMemory(v.device, mem, 0, size, 0, &data))
+	if err != nil {
+		buffer.Destroy()
+		err = fmt.Errorf("vk.MapMemory failed with %s", err)
+		return buffer, err
+	}
+	if n := vk.MemCopyFloat32(data, vertexData); n != len(vertexData) {
+		log.Println("[WARN] failed to copy vertex buffer data")
+	}
+	vk.UnmapMemory(v.device, mem)
+
+	// Phase 2: vk.CreateBuffer
+	//			vk.MapMemory
+	//			create the index buffer and copy the indices
+
+	indexData := make([]byte, 2*len(indices)) // 2 = sizeof(uint16)
+	for i, idx := range indices {
+		binary.LittleEndian.PutUint16(indexData[2*i:], idx)
+	}
+	size = vk.DeviceSize(len(indexData))
+	buffer.indexBuffer, buffer.indexMem, err = v.createBuffer(size, vk.BufferUsageIndexBufferBit,
+		vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+	if err != nil {
+		buffer.Destroy()
+		return buffer, err
+	}
+	err = vk.Error(vk.MapMemory(v.device, buffer.indexMem, 0, size, 0, &data))
+	if err != nil {
+		buffer.Destroy()
+		err = fmt.Errorf("vk.MapMemory failed with %s", err)
+		return buffer, err
+	}
+	vk.MemCopyByte(data, indexData)
+	vk.UnmapMemory(v.device, buffer.indexMem)
+	buffer.indexCount = uint32(len(indices))
+	buffer.indexType = vk.IndexTypeUint16
+	return buffer, nil
+}
+
+// cmdDraw draws the vertices, indexed when there's an index buffer and
+// with vk.CmdDrawIndirect when there's an indirect one. Each of the draw ranges
+// takes a command, see SetDrawRanges.
+func (buf *VulkanBufferInfo) cmdDraw(cmd vk.CommandBuffer) {
+	switch {
+	case buf.indexBuffer != vk.NullHandle:
+		vk.CmdBindIndexBuffer(cmd, buf.indexBuffer, 0, buf.indexType)
+		for _, rng := range buf.drawRanges() {
+			vk.CmdDrawIndexed(cmd, rng.IndexCount, rng.instances(),
+				rng.FirstIndex, rng.VertexOffset, rng.FirstInstance)
+		}
+	case buf.indirectBuffer != vk.NullHandle:
+		vk.CmdDrawIndirect(cmd, buf.indirectBuffer, 0, buf.indirectCount, buf.indirectStride)
+	default:
+		for _, rng := range buf.drawRanges() {
+			vk.CmdDraw(cmd, rng.VertexCount, rng.instances(), rng.FirstVertex, rng.FirstInstance)
+		}
+	}
+}
+
+func (buf *VulkanBufferInfo) destroyIndices() {
+	if buf.indexBuffer == vk.NullHandle {
+		return
+	}
+	vk.DestroyBuffer(buf.device, buf.indexBuffer, nil)
+	vk.FreeMemory(buf.device, buf.indexMem, nil)
+	buf.indexBuffer = vk.NullHandle
+	buf.indexMem = vk.NullHandle
+	buf.indexCount = 0
+	buf.ranges = nil
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// QueuePurpose identifies what a device queue is used for.
+type QueuePurpose int
+
+const (
+	QueueGraphics QueuePurpose = iota
+	QueuePresent
+	QueueTransfer
+	QueueCompute
+	QueueSparse
+)
+
+func (p QueuePurpose) String() string {
+	switch p {
+	case QueueGraphics:
+		return "graphics"
+	case QueuePresent:
+		return "present"
+	case QueueTransfer:
+		return "transfer"
+	case QueueCompute:
+		return "compute"
+	case QueueSparse:
+		return "sparse"
+	default:
+		return "unknown"
+	}
+}
+
+// QueueRequest asks for a device queue with the given purpose and priority,
+// the priority must be within [0, 1].
+type QueueRequest struct {
+	Purpose  QueuePurpose
+	Priority float32
+}
+
+// VulkanDeviceOptions configures NewVulkanDeviceAndroid.
+type VulkanDeviceOptions struct {
+	// RateDevice picks the GPU, RateDeviceFor(Preference) is used when nil.
+	RateDevice RateDevice
+	// Preference is the type of GPU to pick when RateDevice is nil, e.g. PreferIntegrated
+	// to save power. Discrete GPUs are preferred by default.
+	Preference DevicePreference
+	// Queues lists the queues to create. Graphics and present queues are always created,
+	// the present queue shares the graphics one when the family can present.
+	// Transfer and compute queues prefer dedicated families and share a queue of the
+	// family when it has no spare one. The sparse binding queue
+	// shares the graphics one when possible and is skipped when no family supports it.
+	Queues []QueueRequest
+	// RobustBufferAccess bounds checks buffer accesses of shaders, out of bounds reads
+	// return zeros instead of undefined values or device loss. It costs performance
+	// on most GPUs, so it's meant for diagnosing shader memory bugs only.
+	RobustBufferAccess bool
+	// Features2 lists the Vulkan 1.1+ features to enable, e.g. FeatureMultiview, they're
+	// chained to the device create info along with the extensions providing them.
+	// Those the GPU doesn't support are skipped, see HasFeature2.
+	Features2 []DeviceFeature
+	// DebugUserData is handed to the debug report callback, retrieve it from pUserData
+	// with DebugUserData. The callback counts into a *ValidationCounters given here
+	// instead of the global counters. Only used when enableDebug is set.
+	DebugUserData interface{}
+}
+
+// queueSlot locates a queue within the logical device.
+type queueSlot struct {
+	family uint32
+	index  uint32
+}
+
+// queuePlan maps each purpose to its queue and holds the priorities
+// of queues to be created in each family.
+type queuePlan struct {
+	slots      map[QueuePurpose]queueSlot
+	priorities map[uint32][]float32
+}
+
+func getQueueFamilyProperties(gpu vk.PhysicalDevice) []vk.QueueFamilyProperties {
+	var queueCount uint32
+	vk.GetPhysicalDeviceQueueFamilyProperties(gpu, &queueCount, nil)
+	queueProps := make([]vk.QueueFamilyProperties, queueCount)
+	vk.GetPhysicalDeviceQueueFamilyProperties(gpu, &queueCount, queueProps)
+	for i := range queueProps {
+		queueProps[i].Deref()
+	}
+	return queueProps
+}
+
+// planQueues picks the queue families and indices for the requests, present is false
+// for compute-only devices that lack VK_KHR_swapchain.
+func planQueues(gpu vk.PhysicalDevice, surface vk.Surface,
+	requests []QueueRequest, present bool) (*queuePlan, error) {
+
+	queueProps := getQueueFamilyProperties(gpu)
+	presentOK := make([]bool, len(queueProps))
+	for i := range queueProps {
+		if !present {
+			break
+		}
+		var supported vk.Bool32
+		vk.GetPhysicalDeviceSurfaceSupport(gpu, uint32(i), surface, &supported)
+		presentOK[i] = supported == vk.Bool32(vk.True)
+	}
+	hasFlags := func(i int, flags vk.QueueFlagBits) bool {
+		return queueProps[i].QueueFlags&vk.QueueFlags(flags) != 0
+	}
+	find := func(match func(i int) bool) int {
+		for i := range queueProps {
+			if match(i) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var hasGraphics bool
+	for _, req := range requests {
+		if req.Purpose == QueueGraphics {
+			hasGraphics = true
+		}
+	}
+	if !hasGraphics {
+		requests = append([]QueueRequest{{
+			Purpose:  QueueGraphics,
+			Priority: 1.0,
+		}}, requests...)
+	}
+	plan := &queuePlan{
+		slots:      make(map[QueuePurpose]queueSlot),
+		priorities: make(map[uint32][]float32),
+	}
+	gfxFamily := find(func(i int) bool {
+		return hasFlags(i, vk.QueueGraphicsBit) && presentOK[i]
+	})
+	if gfxFamily < 0 {
+		gfxFamily = find(func(i int) bool {
+			return hasFlags(i, vk.QueueGraphicsBit)
+		})
+	}
+	if gfxFamily < 0 {
+		err := fmt.Errorf("planQueues: no queue family with graphics support")
+		return nil, err
+	}
+	addQueue := func(purpose QueuePurpose, family int, priority float32) error {
+		if priority < 0 || priority > 1 {
+			return fmt.Errorf("planQueues: %s queue priority %v is out of [0, 1]", purpose, priority)
+		}
+		idx := uint32(len(plan.priorities[uint32(family)]))
+		if idx >= queueProps[family].QueueCount {
+			return fmt.Errorf("planQueues: queue family %d has %d queues, cannot create one for %s",
+				family, queueProps[family].QueueCount, purpose)
+		}
+		plan.priorities[uint32(family)] = append(plan.priorities[uint32(family)], priority)
+		plan.slots[purpose] = queueSlot{
+			family: uint32(family),
+			index:  idx,
+		}
+		return nil
+	}
+	// addOrShareQueue shares a queue already planned in the family when it has no spare
+	// one, e.g. the transfer queue uses the graphics one on GPUs with a single queue.
+	addOrShareQueue := func(purpose QueuePurpose, family int, priority float32) error {
+		if uint32(len(plan.priorities[uint32(family)])) < queueProps[family].QueueCount {
+			return addQueue(purpose, family, priority)
+		}
+		for p := QueueGraphics; p <= QueueSparse; p++ {
+			if slot, ok := plan.slots[p]; ok && slot.family == uint32(family) {
+				plan.slots[purpose] = slot
+				return nil
+			}
+		}
+		return addQueue(purpose, family, priority)
+	}
+
+	// graphics goes first, so the present queue is able to share it
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].Purpose < requests[j].Purpose
+	})
+	for _, req := range requests {
+		if _, ok := plan.slots[req.Purpose]; ok {
+			err := fmt.Errorf("planQueues: %s queue requested twice", req.Purpose)
+			return nil, err
+		}
+		var err error
+		switch req.Purpose {
+		case QueueGraphics:
+			err = addQueue(req.Purpose, gfxFamily, req.Priority)
+		case QueuePresent:
+			if !present {
+				err = fmt.Errorf("planQueues: present queue requested, but VK_KHR_swapchain is missing")
+				break
+			}
+			if presentOK[gfxFamily] {
+				plan.slots[QueuePresent] = plan.slots[QueueGraphics]
+				continue
+			}
+			family := find(func(i int) bool {
+				return presentOK[i]
+			})
+			if family < 0 {
+				err = fmt.Errorf("planQueues: no queue family can present to the surface")
+				break
+			}
+			err = addQueue(req.Purpose, family, req.Priority)
+		case QueueTransfer:
+			family := find(func(i int) bool {
+				return hasFlags(i, vk.QueueTransferBit) &&
+					!hasFlags(i, vk.QueueGraphicsBit) && !hasFlags(i, vk.QueueComputeBit)
+			})
+			if family < 0 {
+				// graphics and compute families support transfers implicitly
+				family = gfxFamily
+			}
+			err = addOrShareQueue(req.Purpose, family, req.Priority)
+		case QueueCompute:
+			family := find(func(i int) bool {
+				return hasFlags(i, vk.QueueComputeBit) && !hasFlags(i, vk.QueueGraphicsBit)
+			})
+			if family < 0 {
+				family = find(func(i int) bool {
+					return hasFlags(i, vk.QueueComputeBit)
+				})
+			}
+			if family < 0 {
+				err = fmt.Errorf("planQueues: no queue family with compute support")
+				break
+			}
+			err = addOrShareQueue(req.Purpose, family, req.Priority)
+		case QueueSparse:
+			if hasFlags(gfxFamily, vk.QueueSparseBindingBit) {
+				plan.slots[QueueSparse] = plan.slots[QueueGraphics]
+				continue
+			}
+			family := find(func(i int) bool {
+				return hasFlags(i, vk.QueueSparseBindingBit)
+			})
+			if family < 0 {
+				// optional, Queue tells whether there's one
+				continue
+			}
+			err = addQueue(req.Purpose, family, req.Priority)
+		default:
+			err = fmt.Errorf("planQueues: unknown queue purpose %d", req.Purpose)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := plan.slots[QueuePresent]; !ok && present {
+		// the swapchain needs a present queue anyway
+		if presentOK[gfxFamily] {
+			plan.slots[QueuePresent] = plan.slots[QueueGraphics]
+			return plan, nil
+		}
+		family := find(func(i int) bool {
+			return presentOK[i]
+		})
+		if family < 0 {
+			err := fmt.Errorf("planQueues: no queue family can present to the surface")
+			return nil, err
+		}
+		if err := addQueue(QueuePresent, family, 1.0); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// createInfos returns a vk.DeviceQueueCreateInfo for each family involved.
+func (p *queuePlan) createInfos() []vk.DeviceQueueCreateInfo {
+	families := make([]int, 0, len(p.priorities))
+	for family := range p.priorities {
+		families = append(families, int(family))
+	}
+	sort.Ints(families)
+	queueCreateInfos := make([]vk.DeviceQueueCreateInfo, 0, len(families))
+	for _, family := range families {
+		priorities := p.priorities[uint32(family)]
+		queueCreateInfos = append(queueCreateInfos, vk.DeviceQueueCreateInfo{
+			SType:            vk.StructureTypeDeviceQueueCreateInfo,
+			QueueFamilyIndex: uint32(family),
+			QueueCount:       uint32(len(priorities)),
+			PQueuePriorities: priorities,
+		})
+	}
+	return queueCreateInfos
+}
+
+// Queue returns the device queue created for the purpose, if any.
+func (v *VulkanDeviceInfo) Queue(purpose QueuePurpose) (vk.Queue, bool) {
+	queue, ok := v.queues[purpose]
+	return queue, ok
+}
+
+// QueueFamily returns the queue family index used for the purpose, if any.
+func (v *VulkanDeviceInfo) QueueFamily(purpose QueuePurpose) (uint32, bool) {
+	family, ok := v.queueFamilies[purpose]
+	return family, ok
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// DrawRange is a part of the buffers drawn by a single command, so meshes batched into
+// shared vertex and index buffers can be drawn one by one. The vertex fields apply to
+// non-indexed draws and the index ones to indexed draws, see vk.CmdDraw and vk.CmdDrawIndexed.
+type DrawRange struct {
+	VertexCount uint32
+	FirstVertex uint32
+
+	IndexCount uint32
+	FirstIndex uint32
+	// VertexOffset is added to the indices, so a mesh can keep indices starting at 0.
+	VertexOffset int32
+
+	// InstanceCount of 0 draws a single instance.
+	InstanceCount uint32
+	FirstInstance uint32
+}
+
+func (rng DrawRange) instances() uint32 {
+	return maxUint32(rng.InstanceCount, 1)
+}
+
+// SetDrawRanges makes the recorded command buffers draw the ranges of the buffers instead
+// of all the vertices or indices, nil draws everything again. Ranges beyond the vertex
+// or index count are rejected. The command buffers must be recorded again, and draws
+// from an indirect buffer take no ranges.
+func (buf *VulkanBufferInfo) SetDrawRanges(ranges ...DrawRange) error {
+	indexed := buf.indexBuffer != vk.NullHandle
+	for i, rng := range ranges {
+		switch {
+		case indexed && uint64(rng.FirstIndex)+uint64(rng.IndexCount) > uint64(buf.indexCount):
+			return fmt.Errorf("SetDrawRanges: range %d of indices %d+%d exceeds the %d indices",
+				i, rng.FirstIndex, rng.IndexCount, buf.indexCount)
+		case !indexed && uint64(rng.FirstVertex)+uint64(rng.VertexCount) > uint64(buf.vertexCount):
+			return fmt.Errorf("SetDrawRanges: range %d of vertices %d+%d exceeds the %d vertices",
+				i, rng.FirstVertex, rng.VertexCount, buf.vertexCount)
+		}
+	}
+	buf.ranges = ranges
+	return nil
+}
+
+// drawRanges returns the ranges to draw, everything when none were set.
+func (buf *VulkanBufferInfo) drawRanges() []DrawRange {
+	if len(buf.ranges) > 0 {
+		return buf.ranges
+	}
+	return []DrawRange{{
+		VertexCount: buf.vertexCount,
+		IndexCount:  buf.indexCount,
+	}}
+}
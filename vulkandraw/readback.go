@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// ReadRegion copies the w x h pixels at (x, y) of a swapchain image into host memory,
+// e.g. for an eyedropper, rows are tightly packed in the swapchain format. The swapchain
+// must be created with vk.ImageUsageTransferSrcBit and the image must have been presented
+// already, it's read in place without going through the presentation engine.
+// Pending work of the graphics queue gets waited for, so don't call it every frame.
+func ReadRegion(v *VulkanDeviceInfo, s *VulkanSwapchainInfo, cmdPool vk.CommandPool,
+	imageIndex uint32, x, y int32, w, h uint32) ([]byte, error) {
+
+	if s.imageUsage&vk.ImageUsageFlags(vk.ImageUsageTransferSrcBit) == 0 {
+		err := fmt.Errorf("ReadRegion: swapchain images lack vk.ImageUsageTransferSrcBit")
+		return nil, err
+	}
+	if int(imageIndex) >= len(s.displayImages) {
+		err := fmt.Errorf("ReadRegion: no image %d among %d", imageIndex, len(s.displayImages))
+		return nil, err
+	}
+	area := vk.Rect2D{Extent: s.displaySize}
+	rect := vk.Rect2D{
+		Offset: vk.Offset2D{X: x, Y: y},
+		Extent: vk.Extent2D{Width: w, Height: h},
+	}
+	if w == 0 || h == 0 || !rectContains(area, rect) {
+		err := fmt.Errorf("ReadRegion: %dx%d at (%d, %d) is outside of the %dx%d image",
+			w, h, x, y, s.displaySize.Width, s.displaySize.Height)
+		return nil, err
+	}
+	pixelSize, ok := formatSize(s.displayFormat)
+	if !ok {
+		err := fmt.Errorf("ReadRegion: unknown pixel size of format %d", s.displayFormat)
+		return nil, err
+	}
+	size := vk.DeviceSize(w * h * pixelSize)
+
+	// Phase 1: create a host visible buffer for the region
+
+	buffer, mem, err := v.createBuffer(size, vk.BufferUsageTransferDstBit,
+		vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+	if err != nil {
+		return nil, err
+	}
+	defer vk.FreeMemory(v.device, mem, nil)
+	defer vk.DestroyBuffer(v.device, buffer, nil)
+
+	// Phase 2: vk.CmdCopyImageToBuffer
+	//			copy the region, the image goes back to vk.ImageLayoutPresentSrc
+
+	// the frame rendering into the image must be done
+	vk.QueueWaitIdle(v.graphicsQueue)
+	cmd, err := beginSingleTimeCommands(v.device, cmdPool)
+	if err != nil {
+		return nil, err
+	}
+	image := s.displayImages[imageIndex]
+	subresourceRange := vk.ImageSubresourceRange{
+		AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+		LevelCount: 1,
+		LayerCount: 1,
+	}
+	transitionImageLayout(cmd, image, subresourceRange,
+		vk.ImageLayoutPresentSrc, vk.ImageLayoutTransferSrcOptimal)
+	regions := []vk.BufferImageCopy{{
+		ImageSubresource: vk.ImageSubresourceLayers{
+			AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+			LayerCount: 1,
+		},
+		ImageOffset: vk.Offset3D{X: x, Y: y},
+		ImageExtent: vk.Extent3D{Width: w, Height: h, Depth: 1},
+	}}
+	vk.CmdCopyImageToBuffer(cmd, image, vk.ImageLayoutTransferSrcOptimal, buffer, 1, regions)
+	transitionImageLayout(cmd, image, subresourceRange,
+		vk.ImageLayoutTransferSrcOptimal, vk.ImageLayoutPresentSrc)
+	bufferBarriers := []vk.BufferMemoryBarrier{{
+		SType:               vk.StructureTypeBufferMemoryBarrier,
+		SrcAccessMask:       vk.AccessFlags(vk.AccessTransferWriteBit),
+		DstAccessMask:       vk.AccessFlags(vk.AccessHostReadBit),
+		SrcQueueFamilyIndex: vk.QueueFamilyIgnored,
+		DstQueueFamilyIndex: vk.QueueFamilyIgnored,
+		Buffer:              buffer,
+		Size:                size,
+	}}
+	vk.CmdPipelineBarrier(cmd, vk.PipelineStageFlags(vk.PipelineStageTransferBit),
+		vk.PipelineStageFlags(vk.PipelineStageHostBit), 0, 0, nil, 1, bufferBarriers, 0, nil)
+	if err := endSingleTimeCommands(v, cmdPool, v.graphicsQueue, cmd); err != nil {
+		return nil, err
+	}
+
+	// Phase 3: vk.MapMemory
+	//			copy the pixels out
+
+	var ptr unsafe.Pointer
+	err = vk.Error(vk.MapMemory(v.device, mem, 0, size, 0, &ptr))
+	if err != nil {
+		err = fmt.Errorf("vk.MapMemory failed with %s", err)
+		return nil, err
+	}
+	var mapped []byte
+	hdr := (*sliceHeader)(unsafe.Pointer(&mapped))
+	hdr.Data = uintptr(ptr)
+	hdr.Len = int(size)
+	hdr.Cap = int(size)
+	data := make([]byte, size)
+	copy(data, mapped)
+	vk.UnmapMemory(v.device, mem)
+	return data, nil
+}
+
+// CaptureFrame reads the swapchain image of the last frame into an image, e.g. for a screenshot.
+// B8G8R8A8 images get their blue and red channels swapped and 10-bit ones are reduced
+// to 8 bits per channel, so the colors are right whatever the swapchain format, sRGB ones
+// stay encoded. Like ReadRegion it needs vk.ImageUsageTransferSrcBit and waits for the GPU.
+func CaptureFrame(v *VulkanDeviceInfo, s *VulkanSwapchainInfo,
+	r *VulkanRenderInfo) (*image.RGBA, error) {
+
+	if r.lastImageIdx < 0 {
+		err := fmt.Errorf("CaptureFrame: no frame was drawn yet")
+		return nil, err
+	}
+	extent := s.displaySize
+	data, err := ReadRegion(v, s, r.UploadPool(), uint32(r.lastImageIdx),
+		0, 0, extent.Width, extent.Height)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, int(extent.Width), int(extent.Height)))
+	if err := convertToRGBA(img, data, s.displayFormat); err != nil {
+		err = fmt.Errorf("CaptureFrame: %s", err)
+		return nil, err
+	}
+	return img, nil
+}
+
+// convertToRGBA fills img with the tightly packed rows of pixels of the format,
+// the rows of img may be padded, see its Stride.
+func convertToRGBA(img *image.RGBA, data []byte, format vk.Format) error {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	pitch := 4 * w
+	if len(data) < pitch*h {
+		return fmt.Errorf("%d bytes are short of %dx%d pixels", len(data), w, h)
+	}
+	for y := 0; y < h; y++ {
+		src := data[y*pitch : (y+1)*pitch]
+		dst := img.Pix[y*img.Stride : y*img.Stride+pitch]
+		switch format {
+		case vk.FormatR8g8b8a8Unorm, vk.FormatR8g8b8a8Srgb:
+			copy(dst, src)
+		case vk.FormatB8g8r8a8Unorm, vk.FormatB8g8r8a8Srgb:
+			for i := 0; i < pitch; i += 4 {
+				dst[i+0] = src[i+2]
+				dst[i+1] = src[i+1]
+				dst[i+2] = src[i+0]
+				dst[i+3] = src[i+3]
+			}
+		case vk.FormatA2b10g10r10UnormPack32, vk.FormatA2r10g10b10UnormPack32:
+			// red takes the low bits with A2B10G10R10, blue with A2R10G10B10
+			lo, hi := 0, 2
+			if format == vk.FormatA2r10g10b10UnormPack32 {
+				lo, hi = 2, 0
+			}
+			for i := 0; i < pitch; i += 4 {
+				p := binary.LittleEndian.Uint32(src[i:])
+				dst[i+lo] = uint8(p >> 2)
+				dst[i+1] = uint8(p >> 12)
+				dst[i+hi] = uint8(p >> 22)
+				dst[i+3] = uint8(p>>30) * 0x55
+			}
+		default:
+			return fmt.Errorf("can't convert format %d to RGBA", format)
+		}
+	}
+	return nil
+}
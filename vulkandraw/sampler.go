@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// SamplerPreset is a common sampler configuration, see NewSampler.
+type SamplerPreset int
+
+const (
+	// LinearRepeat filters bilinearly between texels and trilinearly between mip levels,
+	// coordinates wrap around, e.g. for tiled textures.
+	LinearRepeat SamplerPreset = iota
+	// LinearClamp is LinearRepeat with coordinates clamped to the edges, e.g. for render targets.
+	LinearClamp
+	// NearestRepeat picks the nearest texel of the nearest mip level, e.g. for pixel art.
+	NearestRepeat
+	// NearestClamp is NearestRepeat with coordinates clamped to the edges.
+	NearestClamp
+	// LinearRepeatAniso is LinearRepeat with anisotropic filtering, textures viewed at
+	// grazing angles stay sharp. It falls back to LinearRepeat without the
+	// samplerAnisotropy feature.
+	LinearRepeatAniso
+	// LinearClampAniso is LinearClamp with anisotropic filtering, like LinearRepeatAniso.
+	LinearClampAniso
+)
+
+func (p SamplerPreset) String() string {
+	switch p {
+	case LinearRepeat:
+		return "linear repeat"
+	case LinearClamp:
+		return "linear clamp"
+	case NearestRepeat:
+		return "nearest repeat"
+	case NearestClamp:
+		return "nearest clamp"
+	case LinearRepeatAniso:
+		return "linear repeat anisotropic"
+	case LinearClampAniso:
+		return "linear clamp anisotropic"
+	default:
+		return "unknown"
+	}
+}
+
+// maxSamplerAnisotropy caps the anisotropy of the presets, more is rarely visible.
+const maxSamplerAnisotropy = 16
+
+// lodClampNone is VK_LOD_CLAMP_NONE, samplers cover all the mip levels of the images.
+const lodClampNone = 1000.0
+
+// samplerCreateInfo returns the create info of the preset, anisotropy is used by
+// the anisotropic presets and must be supported.
+func (p SamplerPreset) samplerCreateInfo(anisotropy float32) (vk.SamplerCreateInfo, error) {
+	info := vk.SamplerCreateInfo{
+		SType:         vk.StructureTypeSamplerCreateInfo,
+		MagFilter:     vk.FilterLinear,
+		MinFilter:     vk.FilterLinear,
+		MipmapMode:    vk.SamplerMipmapModeLinear,
+		AddressModeU:  vk.SamplerAddressModeRepeat,
+		AddressModeV:  vk.SamplerAddressModeRepeat,
+		AddressModeW:  vk.SamplerAddressModeRepeat,
+		MaxAnisotropy: 1,
+		CompareOp:     vk.CompareOpNever,
+		MinLod:        0,
+		MaxLod:        lodClampNone,
+		BorderColor:   vk.BorderColorFloatOpaqueBlack,
+	}
+	switch p {
+	case LinearRepeat:
+	case LinearClamp, LinearClampAniso:
+		info.AddressModeU = vk.SamplerAddressModeClampToEdge
+		info.AddressModeV = vk.SamplerAddressModeClampToEdge
+		info.AddressModeW = vk.SamplerAddressModeClampToEdge
+	case NearestRepeat:
+		info.MagFilter = vk.FilterNearest
+		info.MinFilter = vk.FilterNearest
+		info.MipmapMode = vk.SamplerMipmapModeNearest
+	case NearestClamp:
+		info.MagFilter = vk.FilterNearest
+		info.MinFilter = vk.FilterNearest
+		info.MipmapMode = vk.SamplerMipmapModeNearest
+		info.AddressModeU = vk.SamplerAddressModeClampToEdge
+		info.AddressModeV = vk.SamplerAddressModeClampToEdge
+		info.AddressModeW = vk.SamplerAddressModeClampToEdge
+	case LinearRepeatAniso:
+	default:
+		return info, fmt.Errorf("unknown sampler preset %d", p)
+	}
+	if (p == LinearRepeatAniso || p == LinearClampAniso) && anisotropy > 1 {
+		info.AnisotropyEnable = vk.Bool32(vk.True)
+		info.MaxAnisotropy = anisotropy
+	}
+	return info, nil
+}
+
+// NewSampler creates a sampler of the preset. The device keeps track of it and destroys it
+// along with itself, see Destroy. The anisotropic presets use up to 16x anisotropy, less
+// when the device limit is lower, or none without the samplerAnisotropy feature.
+func (v *VulkanDeviceInfo) NewSampler(preset SamplerPreset) (vk.Sampler, error) {
+	var sampler vk.Sampler
+	anisotropy := float32(1)
+	if preset == LinearRepeatAniso || preset == LinearClampAniso {
+		if v.enabledFeatures.SamplerAnisotropy != vk.Bool32(vk.True) {
+			log.Printf("[WARN] %s sampler without the samplerAnisotropy feature, no anisotropy", preset)
+		} else {
+			anisotropy = v.caps.Limits.MaxSamplerAnisotropy
+			if anisotropy > maxSamplerAnisotropy {
+				anisotropy = maxSamplerAnisotropy
+			}
+		}
+	}
+	samplerCreateInfo, err := preset.samplerCreateInfo(anisotropy)
+	if err != nil {
+		err = fmt.Errorf("NewSampler: %s", err)
+		return sampler, err
+	}
+	err = vk.Error(vk.CreateSampler(v.device, &samplerCreateInfo, nil, &sampler))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateSampler failed with %s", err)
+		return sampler, err
+	}
+	v.samplers = append(v.samplers, sampler)
+	return sampler, nil
+}
+
+func (v *VulkanDeviceInfo) destroySamplers() {
+	for _, sampler := range v.samplers {
+		vk.DestroySampler(v.device, sampler, nil)
+	}
+	v.samplers = nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// VulkanSecondaryInfo splits the scene pass of each frame into two secondary command buffers,
+// see UseSecondaryBuffers. The static one holds the scene and is recorded only when it's
+// outdated, the dynamic one is recorded again every frame.
+type VulkanSecondaryInfo struct {
+	static  []vk.CommandBuffer // per swapchain image
+	dynamic []vk.CommandBuffer // per swapchain image
+	record  OverlayFunc
+
+	// static command buffers recorded before the scene or the framebuffers last changed
+	staticOutdated []bool
+}
+
+// UseSecondaryBuffers records the scene once into a secondary command buffer per image and
+// the draws of dynamic into another one that gets recorded again every frame, e.g. for an
+// animated object in a mostly static scene. The frame command buffer only executes both
+// with vk.CmdExecuteCommands, so it's cheap to record again too. Invalidate records the
+// static ones again. With a post pass the overlay stays in the frame command buffer,
+// otherwise it's recorded into the dynamic one right after dynamic. Pipeline statistics
+// then need the inheritedQueries feature and count the draws of both. Call it after
+// CreateCommandBuffers and before VulkanInit.
+func (r *VulkanRenderInfo) UseSecondaryBuffers(dynamic OverlayFunc) error {
+	if len(r.cmdBuffers) == 0 {
+		err := fmt.Errorf("UseSecondaryBuffers: the command buffers must be created first")
+		return err
+	}
+	n := uint32(len(r.cmdBuffers))
+	cmdBuffers := make([]vk.CommandBuffer, 2*n)
+	cmdBufferAllocateInfo := vk.CommandBufferAllocateInfo{
+		SType:              vk.StructureTypeCommandBufferAllocateInfo,
+		CommandPool:        r.cmdPool,
+		Level:              vk.CommandBufferLevelSecondary,
+		CommandBufferCount: 2 * n,
+	}
+	err := vk.Error(vk.AllocateCommandBuffers(r.device, &cmdBufferAllocateInfo, cmdBuffers))
+	if err != nil {
+		err = fmt.Errorf("vk.AllocateCommandBuffers failed with %s", err)
+		return err
+	}
+	r.secondary = &VulkanSecondaryInfo{
+		static:         cmdBuffers[:n],
+		dynamic:        cmdBuffers[n:],
+		record:         dynamic,
+		staticOutdated: make([]bool, n),
+	}
+	r.secondary.invalidate()
+	return nil
+}
+
+func (sec *VulkanSecondaryInfo) invalidate() {
+	if sec == nil {
+		return
+	}
+	for i := range sec.staticOutdated {
+		sec.staticOutdated[i] = true
+	}
+}
+
+// cmdExecute records the secondary command buffers of image i that are due and executes them,
+// the render pass must have begun with vk.SubpassContentsSecondaryCommandBuffers.
+func (sec *VulkanSecondaryInfo) cmdExecute(cmd vk.CommandBuffer, i int, frame OverlayFrame,
+	framebuffer vk.Framebuffer, r *VulkanRenderInfo, b *VulkanBufferInfo, gfx *VulkanGfxPipelineInfo) {
+
+	// the framebuffer is known, which may help some implementations
+	inheritanceInfo := []vk.CommandBufferInheritanceInfo{{
+		SType:       vk.StructureTypeCommandBufferInheritanceInfo,
+		RenderPass:  frame.RenderPass,
+		Subpass:     0,
+		Framebuffer: framebuffer,
+	}}
+	if r.stats != nil && r.stats.inherited {
+		// the pipeline statistics query of the frame command buffer stays active
+		inheritanceInfo[0].PipelineStatistics = vk.QueryPipelineStatisticFlags(pipelineStatFlags)
+	}
+	if sec.staticOutdated[i] {
+		beginInfo := vk.CommandBufferBeginInfo{
+			SType:            vk.StructureTypeCommandBufferBeginInfo,
+			Flags:            vk.CommandBufferUsageFlags(vk.CommandBufferUsageRenderPassContinueBit),
+			PInheritanceInfo: inheritanceInfo,
+		}
+		ret := vk.BeginCommandBuffer(sec.static[i], &beginInfo)
+		check(ret, "vk.BeginCommandBuffer")
+		vk.CmdBindPipeline(sec.static[i], vk.PipelineBindPointGraphics, gfx.pipeline)
+		offsets := make([]vk.DeviceSize, len(b.vertexBuffers))
+		vk.CmdBindVertexBuffers(sec.static[i], 0, uint32(len(b.vertexBuffers)), b.vertexBuffers, offsets)
+		b.cmdDraw(sec.static[i])
+		ret = vk.EndCommandBuffer(sec.static[i])
+		check(ret, "vk.EndCommandBuffer")
+		sec.staticOutdated[i] = false
+	}
+
+	beginInfo := vk.CommandBufferBeginInfo{
+		SType: vk.StructureTypeCommandBufferBeginInfo,
+		Flags: vk.CommandBufferUsageFlags(vk.CommandBufferUsageRenderPassContinueBit |
+			vk.CommandBufferUsageOneTimeSubmitBit),
+		PInheritanceInfo: inheritanceInfo,
+	}
+	ret := vk.BeginCommandBuffer(sec.dynamic[i], &beginInfo)
+	check(ret, "vk.BeginCommandBuffer")
+	frame.Cmd = sec.dynamic[i]
+	if sec.record != nil {
+		sec.record(frame)
+	}
+	if r.overlay != nil && r.post == nil {
+		r.overlay(frame)
+	}
+	ret = vk.EndCommandBuffer(sec.dynamic[i])
+	check(ret, "vk.EndCommandBuffer")
+
+	vk.CmdExecuteCommands(cmd, 2, []vk.CommandBuffer{sec.static[i], sec.dynamic[i]})
+}
+
+func (sec *VulkanSecondaryInfo) free(device vk.Device, pool vk.CommandPool) {
+	if sec == nil {
+		return
+	}
+	vk.FreeCommandBuffers(device, pool, uint32(len(sec.static)), sec.static)
+	vk.FreeCommandBuffers(device, pool, uint32(len(sec.dynamic)), sec.dynamic)
+}
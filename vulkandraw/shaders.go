@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// LoadAsset reads the shaders and textures by name, from bindata unless it's replaced,
+// e.g. with FileAssets so ReloadShaders picks up shaders rebuilt without the app.
+var LoadAsset = Asset
+
+// FileAssets returns a LoadAsset reading the assets from the files under dir,
+// the ones missing there still come from bindata.
+func FileAssets(dir string) func(name string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if os.IsNotExist(err) {
+			return Asset(name)
+		}
+		return data, err
+	}
+}
+
+// ShaderStage names the SPIR-V asset and entry point of a pipeline stage. Stages may share
+// an asset, e.g. a combined module with vertMain and fragMain entry points, which then
+// gets loaded only once.
+type ShaderStage struct {
+	Stage      vk.ShaderStageFlagBits
+	Asset      string
+	EntryPoint string // "main" when empty
+	// PatchControlPoints is the patch size read by a tessellation control stage, 3 when zero.
+	PatchControlPoints uint32
+}
+
+// TriangleShaders are the default stages of CreateGraphicsPipeline.
+var TriangleShaders = []ShaderStage{
+	{Stage: vk.ShaderStageVertexBit, Asset: "shaders/tri-vert.spv"},
+	{Stage: vk.ShaderStageFragmentBit, Asset: "shaders/tri-frag.spv"},
+}
+
+// TessellationShaders subdivide the triangle, the device must have the tessellationShader
+// feature, see ValidateShaderStages. The shaders must be built with make shaders.
+var TessellationShaders = []ShaderStage{
+	{Stage: vk.ShaderStageVertexBit, Asset: "shaders/tri-vert.spv"},
+	{Stage: vk.ShaderStageTessellationControlBit, Asset: "shaders/tri-tesc.spv", PatchControlPoints: 3},
+	{Stage: vk.ShaderStageTessellationEvaluationBit, Asset: "shaders/tri-tese.spv"},
+	{Stage: vk.ShaderStageFragmentBit, Asset: "shaders/tri-frag.spv"},
+}
+
+// ShaderAssets returns the assets of the stages, for ValidateAssets.
+func ShaderAssets(stages []ShaderStage) []string {
+	names := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		names = append(names, stage.Asset)
+	}
+	return names
+}
+
+// ValidateAssets checks up front that all the assets exist, so an incomplete bindata
+// fails before any Vulkan object gets created rather than halfway through.
+// The error lists every missing asset.
+func ValidateAssets(names ...string) error {
+	var missing []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, err := LoadAsset(name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing assets: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// patchControlPoints returns the patch size of the tessellation control stage,
+// ok is false when the stages don't tessellate.
+func patchControlPoints(stages []ShaderStage) (n uint32, ok bool) {
+	for _, stage := range stages {
+		if stage.Stage != vk.ShaderStageTessellationControlBit {
+			continue
+		}
+		if stage.PatchControlPoints == 0 {
+			return 3, true
+		}
+		return stage.PatchControlPoints, true
+	}
+	return 0, false
+}
+
+// ValidateShaderStages checks that the device has the features the stages need,
+// and that tessellation control and evaluation stages come together.
+func (v *VulkanDeviceInfo) ValidateShaderStages(stages []ShaderStage) error {
+	var hasControl, hasEvaluation bool
+	for _, stage := range stages {
+		switch stage.Stage {
+		case vk.ShaderStageTessellationControlBit:
+			hasControl = true
+		case vk.ShaderStageTessellationEvaluationBit:
+			hasEvaluation = true
+		}
+	}
+	if hasControl != hasEvaluation {
+		return fmt.Errorf("tessellation needs both a control and an evaluation stage")
+	}
+	if !hasControl {
+		return nil
+	}
+	if v.enabledFeatures.TessellationShader != vk.Bool32(vk.True) {
+		return fmt.Errorf("tessellation stages need the tessellationShader feature")
+	}
+	n, _ := patchControlPoints(stages)
+	if maxPoints := v.caps.Limits.MaxTessellationPatchSize; n > maxPoints {
+		return fmt.Errorf("%d patch control points exceed maxTessellationPatchSize %d", n, maxPoints)
+	}
+	return nil
+}
+
+// loadShaderStages creates a module per distinct asset and the stage create infos referencing
+// them, the modules must be destroyed with destroyShaderModules once the pipeline is created.
+func loadShaderStages(device vk.Device,
+	stages []ShaderStage) ([]vk.PipelineShaderStageCreateInfo, map[string]vk.ShaderModule, error) {
+
+	modules := make(map[string]vk.ShaderModule)
+	createInfos := make([]vk.PipelineShaderStageCreateInfo, 0, len(stages))
+	for _, stage := range stages {
+		module, ok := modules[stage.Asset]
+		if !ok {
+			var err error
+			module, err = LoadShader(device, stage.Asset)
+			if err != nil { // err has enough info
+				destroyShaderModules(device, modules)
+				return nil, nil, err
+			}
+			modules[stage.Asset] = module
+		}
+		entryPoint := stage.EntryPoint
+		if len(entryPoint) == 0 {
+			entryPoint = "main"
+		}
+		createInfos = append(createInfos, vk.PipelineShaderStageCreateInfo{
+			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
+			Stage:  stage.Stage,
+			Module: module,
+			PName:  entryPoint + "\x00",
+		})
+	}
+	if len(createInfos) == 0 {
+		err := fmt.Errorf("loadShaderStages: no shader stages given")
+		return nil, nil, err
+	}
+	return createInfos, modules, nil
+}
+
+func destroyShaderModules(device vk.Device, modules map[string]vk.ShaderModule) {
+	for _, module := range modules {
+		vk.DestroyShaderModule(device, module, nil)
+	}
+}
@@ -0,0 +1,13 @@
+#version 450
+#extension GL_ARB_separate_shader_objects : enable
+#extension GL_ARB_shading_language_420pack : enable
+layout (location = 0) out vec2 texCoord;
+out gl_PerVertex {
+   vec4 gl_Position;
+};
+void main() {
+   // a single triangle covering the whole viewport, no vertex buffer needed:
+   // vertices 0, 1, 2 get uvs (0, 0), (2, 0), (0, 2) so [0, 1] spans the screen
+   texCoord = vec2((gl_VertexIndex << 1) & 2, gl_VertexIndex & 2);
+   gl_Position = vec4(texCoord * 2.0 - 1.0, 0.0, 1.0);
+}
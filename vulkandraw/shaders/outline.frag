@@ -0,0 +1,11 @@
+#version 450
+#extension GL_ARB_separate_shader_objects : enable
+#extension GL_ARB_shading_language_420pack : enable
+layout (push_constant) uniform Outline {
+   vec4 color;
+   float scale;
+} outline;
+layout (location = 0) out vec4 uFragColor;
+void main() {
+   uFragColor = outline.color;
+}
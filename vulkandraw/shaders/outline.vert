@@ -0,0 +1,12 @@
+#version 450
+#extension GL_ARB_separate_shader_objects : enable
+#extension GL_ARB_shading_language_420pack : enable
+layout (push_constant) uniform Outline {
+   vec4 color;
+   float scale;
+} outline;
+layout (location = 0) in vec4 pos;
+void main() {
+   // grow the shape around the origin, the stencil masks out the original
+   gl_Position = vec4(pos.xyz * outline.scale, 1.0);
+}
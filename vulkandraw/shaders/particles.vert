@@ -0,0 +1,16 @@
+#version 450
+#extension GL_ARB_separate_shader_objects : enable
+#extension GL_ARB_shading_language_420pack : enable
+layout (location = 0) in vec2 pos;
+layout (location = 1) in vec2 vel;
+layout (location = 0) out vec4 color;
+out gl_PerVertex {
+   vec4 gl_Position;
+   float gl_PointSize;
+};
+void main() {
+   // faster particles are brighter
+   color = vec4(mix(vec3(0.2, 0.4, 1.0), vec3(1.0), clamp(length(vel), 0.0, 1.0)), 1.0);
+   gl_PointSize = 2.0;
+   gl_Position = vec4(pos, 0.0, 1.0);
+}
@@ -0,0 +1,13 @@
+#version 450
+#extension GL_ARB_separate_shader_objects : enable
+#extension GL_ARB_shading_language_420pack : enable
+layout (binding = 0) uniform sampler2D scene;
+layout (location = 0) in vec2 texCoord;
+layout (location = 0) out vec4 uFragColor;
+void main() {
+   vec4 color = texture(scene, texCoord);
+   // darken the corners a bit
+   vec2 d = texCoord - 0.5;
+   float vignette = 1.0 - dot(d, d);
+   uFragColor = vec4(color.rgb * vignette, color.a);
+}
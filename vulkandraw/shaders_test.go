@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileAssets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "shaders"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	edited := []byte{0x03, 0x02, 0x23, 0x07}
+	if err := ioutil.WriteFile(filepath.Join(dir, "shaders", "tri-vert.spv"), edited, 0644); err != nil {
+		t.Fatal(err)
+	}
+	load := FileAssets(dir)
+
+	// the file wins over bindata
+	data, err := load("shaders/tri-vert.spv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, edited) {
+		t.Errorf("shaders/tri-vert.spv reads %x, want the file contents %x", data, edited)
+	}
+
+	// what's missing in dir comes from bindata
+	data, err = load("shaders/tri-frag.spv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Asset("shaders/tri-frag.spv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("shaders/tri-frag.spv doesn't match the bindata asset")
+	}
+
+	if _, err := load("shaders/missing.spv"); err == nil {
+		t.Errorf("an asset missing in both got loaded")
+	}
+}
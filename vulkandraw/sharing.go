@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// BufferSharing is how buffers are shared between queue families. The zero value is
+// vk.SharingModeExclusive: a buffer belongs to one family at a time and using it from
+// another one takes an ownership transfer. With vk.SharingModeConcurrent the families
+// listed may all use it, e.g. a transfer queue writing and the graphics queue reading,
+// at some cost in performance on some GPUs.
+type BufferSharing struct {
+	Mode          vk.SharingMode
+	QueueFamilies []uint32 // the families sharing concurrent buffers, at least two distinct
+}
+
+// SharingFor returns the sharing for buffers used by the queues of the purposes, concurrent
+// when they come from different families and exclusive otherwise, so it's a no-op on
+// GPUs with a single family. Purposes without a queue are skipped.
+func (v *VulkanDeviceInfo) SharingFor(purposes ...QueuePurpose) BufferSharing {
+	var families []uint32
+	for _, purpose := range purposes {
+		family, ok := v.queueFamilies[purpose]
+		if !ok {
+			continue
+		}
+		known := false
+		for _, f := range families {
+			known = known || f == family
+		}
+		if !known {
+			families = append(families, family)
+		}
+	}
+	if len(families) < 2 {
+		return BufferSharing{}
+	}
+	return BufferSharing{
+		Mode:          vk.SharingModeConcurrent,
+		QueueFamilies: families,
+	}
+}
+
+// SetBufferSharing sets the sharing of the buffers created from now on, see SharingFor.
+// Buffers created before keep theirs.
+func (v *VulkanDeviceInfo) SetBufferSharing(sharing BufferSharing) error {
+	if sharing.Mode != vk.SharingModeConcurrent {
+		v.bufferSharing = BufferSharing{}
+		return nil
+	}
+	if len(sharing.QueueFamilies) < 2 {
+		err := fmt.Errorf("SetBufferSharing: concurrent sharing needs at least two queue families, got %d",
+			len(sharing.QueueFamilies))
+		return err
+	}
+	familyCount := uint32(len(getQueueFamilyProperties(v.gpu)))
+	for i, family := range sharing.QueueFamilies {
+		if family >= familyCount {
+			err := fmt.Errorf("SetBufferSharing: queue family %d of %d doesn't exist", family, familyCount)
+			return err
+		}
+		for _, other := range sharing.QueueFamilies[:i] {
+			if other == family {
+				err := fmt.Errorf("SetBufferSharing: queue family %d is listed twice", family)
+				return err
+			}
+		}
+	}
+	v.bufferSharing = sharing
+	return nil
+}
+
+// apply sets the sharing mode and the queue families of the buffer create info.
+func (sharing BufferSharing) apply(info *vk.BufferCreateInfo) {
+	if sharing.Mode != vk.SharingModeConcurrent {
+		info.SharingMode = vk.SharingModeExclusive
+		info.QueueFamilyIndexCount = 0
+		info.PQueueFamilyIndices = nil
+		return
+	}
+	info.SharingMode = vk.SharingModeConcurrent
+	info.QueueFamilyIndexCount = uint32(len(sharing.QueueFamilies))
+	info.PQueueFamilyIndices = sharing.QueueFamilies
+}
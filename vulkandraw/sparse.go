@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// CreateSparseBuffers creates the triangle vertex buffer as a sparse buffer: it's created
+// without memory, device local blocks get bound to it with vk.QueueBindSparse on the sparse
+// binding queue and the vertices are uploaded through a staging buffer from cmdPool.
+// The GPU must support the sparseBinding feature and have a sparse binding queue,
+// see QueueSparse.
+func (v *VulkanDeviceInfo) CreateSparseBuffers(cmdPool vk.CommandPool) (VulkanBufferInfo, error) {
+	sparseQueue, ok := v.Queue(QueueSparse)
+	if !ok || v.enabledFeatures.SparseBinding != vk.Bool32(vk.True) {
+		err := fmt.Errorf("CreateSparseBuffers: sparse binding is not supported by GPU")
+		return VulkanBufferInfo{}, err
+	}
+	vertexData := []float32{
+		-1, -1, 0,
+		1, -1, 0,
+		0, 1, 0,
+	}
+	data := make([]byte, 4*len(vertexData))
+	for i, f := range vertexData {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(f))
+	}
+
+	// Phase 1: vk.CreateBuffer
+	//			no memory is bound to a sparse buffer at creation
+
+	bufferCreateInfo := vk.BufferCreateInfo{
+		SType: vk.StructureTypeBufferCreateInfo,
+		Flags: vk.BufferCreateFlags(vk.BufferCreateSparseBindingBit),
+		Size:  vk.DeviceSize(len(data)),
+		Usage: vk.BufferUsageFlags(vk.BufferUsageVertexBufferBit | vk.BufferUsageTransferDstBit),
+	}
+	v.bufferSharing.apply(&bufferCreateInfo)
+	buffer := VulkanBufferInfo{
+		device:        v.device,
+		vertexBuffers: make([]vk.Buffer, 1),
+		layout:        PositionLayout(),
+		vertexCount:   3,
+	}
+	err := vk.Error(vk.CreateBuffer(v.device, &bufferCreateInfo, nil, &buffer.vertexBuffers[0]))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateBuffer failed with %s", err)
+		return VulkanBufferInfo{}, err
+	}
+	fail := func(err error) (VulkanBufferInfo, error) {
+		buffer.Destroy()
+		return VulkanBufferInfo{}, err
+	}
+
+	// Phase 2: vk.AllocateMemory
+	//			a separate allocation for each sparse block,
+	//			the alignment is the size of a block
+
+	var memReq vk.MemoryRequirements
+	vk.GetBufferMemoryRequirements(v.device, buffer.DefaultVertexBuffer(), &memReq)
+	memReq.Deref()
+	memTypeIdx, ok := vk.FindMemoryTypeIndex(v.gpu, memReq.MemoryTypeBits,
+		vk.MemoryPropertyDeviceLocalBit)
+	if !ok {
+		err := fmt.Errorf("vk.FindMemoryTypeIndex: no device local memory for sparse buffer")
+		return fail(err)
+	}
+	blockSize := memReq.Alignment
+	blocks := (memReq.Size + blockSize - 1) / blockSize
+	binds := make([]vk.SparseMemoryBind, blocks)
+	for i := range binds {
+		allocInfo := vk.MemoryAllocateInfo{
+			SType:           vk.StructureTypeMemoryAllocateInfo,
+			AllocationSize:  blockSize,
+			MemoryTypeIndex: memTypeIdx,
+		}
+		var mem vk.DeviceMemory
+		err := vk.Error(vk.AllocateMemory(v.device, &allocInfo, nil, &mem))
+		if err != nil {
+			err = fmt.Errorf("vk.AllocateMemory failed with %s", err)
+			return fail(err)
+		}
+		buffer.vertexMems = append(buffer.vertexMems, mem)
+		binds[i] = vk.SparseMemoryBind{
+			ResourceOffset: vk.DeviceSize(i) * blockSize,
+			Size:           blockSize,
+			Memory:         mem,
+		}
+	}
+
+	// Phase 3: vk.QueueBindSparse
+	//			bind the blocks and wait until they are bound
+
+	bindInfos := []vk.BindSparseInfo{{
+		SType:           vk.StructureTypeBindSparseInfo,
+		BufferBindCount: 1,
+		PBufferBinds: []vk.SparseBufferMemoryBindInfo{{
+			Buffer:    buffer.DefaultVertexBuffer(),
+			BindCount: uint32(len(binds)),
+			PBinds:    binds,
+		}},
+	}}
+	fence, err := v.fences.Acquire()
+	if err != nil {
+		return fail(err)
+	}
+	err = vk.Error(vk.QueueBindSparse(sparseQueue, 1, bindInfos, fence))
+	if err != nil {
+		v.fences.Release(fence)
+		err = fmt.Errorf("vk.QueueBindSparse failed with %s", err)
+		return fail(err)
+	}
+	err = vk.Error(vk.WaitForFences(v.device, 1, []vk.Fence{fence}, vk.True, vk.MaxUint64))
+	if err != nil {
+		v.fences.Discard(fence)
+		err = fmt.Errorf("vk.WaitForFences failed with %s", err)
+		return fail(err)
+	}
+	if err := v.fences.Release(fence); err != nil {
+		return fail(err)
+	}
+	log.Printf("[INFO] sparse vertex buffer bound with %d blocks of %d bytes", blocks, blockSize)
+
+	// Phase 4: upload the vertices through a staging buffer
+
+	st, err := v.NewStagingBuffer(cmdPool, vk.DeviceSize(len(data)))
+	if err != nil {
+		return fail(err)
+	}
+	defer st.Destroy()
+	if err := st.Upload(data, buffer.DefaultVertexBuffer(), 0); err != nil {
+		return fail(err)
+	}
+	return buffer, nil
+}
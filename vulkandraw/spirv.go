@@ -0,0 +1,481 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// ShaderReflection is what ReflectShader finds in a SPIR-V module for an entry point,
+// so vertex input and descriptor set layouts can be derived from the shaders instead
+// of being kept in sync by hand.
+type ShaderReflection struct {
+	Stage      vk.ShaderStageFlagBits
+	EntryPoint string
+	// Inputs are the user defined inputs of the stage by location, built-ins are left out.
+	Inputs []ShaderInput
+	// Bindings are the descriptors the module declares by set and binding, Stages
+	// of each is the stage of the entry point.
+	Bindings []ShaderBinding
+}
+
+// ShaderInput is an input variable of a shader stage.
+type ShaderInput struct {
+	Name     string // empty when the module was stripped of debug names
+	Location uint32
+	Format   vk.Format
+}
+
+// ShaderBinding is a descriptor declared by a shader module.
+type ShaderBinding struct {
+	Name string // empty when the module was stripped of debug names
+	Set  uint32
+	DescriptorBinding
+}
+
+// the few SPIR-V opcodes, decorations, storage classes and execution models the reflection reads,
+// see the SPIR-V specification
+const (
+	spirvMagic = 0x07230203
+
+	spirvOpName             = 5
+	spirvOpEntryPoint       = 15
+	spirvOpTypeInt          = 21
+	spirvOpTypeFloat        = 22
+	spirvOpTypeVector       = 23
+	spirvOpTypeImage        = 25
+	spirvOpTypeSampler      = 26
+	spirvOpTypeSampledImage = 27
+	spirvOpTypeArray        = 28
+	spirvOpTypeRuntimeArray = 29
+	spirvOpTypeStruct       = 30
+	spirvOpTypePointer      = 32
+	spirvOpConstant         = 43
+	spirvOpVariable         = 59
+	spirvOpDecorate         = 71
+
+	spirvDecorationBlock         = 2
+	spirvDecorationBufferBlock   = 3
+	spirvDecorationBuiltIn       = 11
+	spirvDecorationLocation      = 30
+	spirvDecorationBinding       = 33
+	spirvDecorationDescriptorSet = 34
+
+	spirvStorageUniformConstant = 0
+	spirvStorageInput           = 1
+	spirvStorageUniform         = 2
+	spirvStorageStorageBuffer   = 12
+
+	spirvDimBuffer      = 5
+	spirvDimSubpassData = 6
+)
+
+// spirvStages maps the SPIR-V execution models to shader stages.
+var spirvStages = map[uint32]vk.ShaderStageFlagBits{
+	0: vk.ShaderStageVertexBit,
+	1: vk.ShaderStageTessellationControlBit,
+	2: vk.ShaderStageTessellationEvaluationBit,
+	3: vk.ShaderStageGeometryBit,
+	4: vk.ShaderStageFragmentBit,
+	5: vk.ShaderStageComputeBit,
+}
+
+// spirvInstruction is an opcode with its operands.
+type spirvInstruction struct {
+	op       uint32
+	operands []uint32
+}
+
+// spirvModule holds what the reflection needs from a module, by result id.
+type spirvModule struct {
+	names       map[uint32]string
+	decorations map[uint32]map[uint32]uint32 // the first literal of each decoration
+	types       map[uint32]spirvInstruction
+	constants   map[uint32]uint32
+	variables   []spirvInstruction
+	entryPoints []spirvInstruction
+}
+
+// spirvString decodes a null terminated literal string packed into the words.
+func spirvString(words []uint32) string {
+	var s []byte
+	for _, word := range words {
+		for i := 0; i < 4; i++ {
+			c := byte(word >> (8 * uint(i)))
+			if c == 0 {
+				return string(s)
+			}
+			s = append(s, c)
+		}
+	}
+	return string(s)
+}
+
+// spirvStringWords returns the number of words the literal string at the start of words takes.
+func spirvStringWords(words []uint32) int {
+	for i, word := range words {
+		if word>>24 == 0 {
+			return i + 1
+		}
+	}
+	return len(words)
+}
+
+func parseSpirv(code []byte) (*spirvModule, error) {
+	if len(code)%4 != 0 || len(code) < 20 {
+		return nil, fmt.Errorf("SPIR-V module of %d bytes is malformed", len(code))
+	}
+	if binary.LittleEndian.Uint32(code) != spirvMagic {
+		return nil, fmt.Errorf("SPIR-V module has a wrong magic number")
+	}
+	words := make([]uint32, len(code)/4)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(code[4*i:])
+	}
+	m := &spirvModule{
+		names:       make(map[uint32]string),
+		decorations: make(map[uint32]map[uint32]uint32),
+		types:       make(map[uint32]spirvInstruction),
+		constants:   make(map[uint32]uint32),
+	}
+	// the header takes 5 words
+	for i := 5; i < len(words); {
+		count := int(words[i] >> 16)
+		if count == 0 || i+count > len(words) {
+			return nil, fmt.Errorf("SPIR-V instruction at word %d is truncated", i)
+		}
+		inst := spirvInstruction{
+			op:       words[i] & 0xffff,
+			operands: words[i+1 : i+count],
+		}
+		i += count
+		ops := inst.operands
+		switch inst.op {
+		case spirvOpName:
+			if len(ops) >= 2 {
+				m.names[ops[0]] = spirvString(ops[1:])
+			}
+		case spirvOpEntryPoint:
+			if len(ops) >= 3 {
+				m.entryPoints = append(m.entryPoints, inst)
+			}
+		case spirvOpDecorate:
+			if len(ops) >= 2 {
+				if m.decorations[ops[0]] == nil {
+					m.decorations[ops[0]] = make(map[uint32]uint32)
+				}
+				var literal uint32
+				if len(ops) >= 3 {
+					literal = ops[2]
+				}
+				m.decorations[ops[0]][ops[1]] = literal
+			}
+		case spirvOpTypeInt, spirvOpTypeFloat, spirvOpTypeVector, spirvOpTypeImage,
+			spirvOpTypeSampler, spirvOpTypeSampledImage, spirvOpTypeArray,
+			spirvOpTypeRuntimeArray, spirvOpTypeStruct, spirvOpTypePointer:
+			if len(ops) >= 1 {
+				m.types[ops[0]] = inst
+			}
+		case spirvOpConstant:
+			if len(ops) >= 3 {
+				m.constants[ops[1]] = ops[2]
+			}
+		case spirvOpVariable:
+			if len(ops) >= 3 {
+				m.variables = append(m.variables, inst)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *spirvModule) decoration(id, decoration uint32) (uint32, bool) {
+	literal, ok := m.decorations[id][decoration]
+	return literal, ok
+}
+
+// isBlock reports whether the type is a struct or an array of structs, e.g. gl_PerVertex
+// or the gl_in[] array of tessellation and geometry stages.
+func (m *spirvModule) isBlock(typeID uint32) bool {
+	t := m.types[typeID]
+	if t.op == spirvOpTypeArray && len(t.operands) >= 2 {
+		t = m.types[t.operands[1]]
+	}
+	return t.op == spirvOpTypeStruct
+}
+
+// inputFormat returns the vertex format of a scalar or vector type of 32-bit components.
+func (m *spirvModule) inputFormat(typeID uint32) (vk.Format, error) {
+	components := uint32(1)
+	t := m.types[typeID]
+	if t.op == spirvOpTypeVector {
+		if len(t.operands) < 3 {
+			return vk.FormatUndefined, fmt.Errorf("vector type %d is truncated", typeID)
+		}
+		components = t.operands[2]
+		t = m.types[t.operands[1]]
+	}
+	formats := map[uint32][4]vk.Format{
+		spirvOpTypeFloat: {vk.FormatR32Sfloat, vk.FormatR32g32Sfloat,
+			vk.FormatR32g32b32Sfloat, vk.FormatR32g32b32a32Sfloat},
+		spirvOpTypeInt: {vk.FormatR32Uint, vk.FormatR32g32Uint,
+			vk.FormatR32g32b32Uint, vk.FormatR32g32b32a32Uint},
+	}
+	if t.op == spirvOpTypeInt && len(t.operands) >= 3 && t.operands[2] == 1 {
+		formats[spirvOpTypeInt] = [4]vk.Format{vk.FormatR32Sint, vk.FormatR32g32Sint,
+			vk.FormatR32g32b32Sint, vk.FormatR32g32b32a32Sint}
+	}
+	byComponents, ok := formats[t.op]
+	if !ok || len(t.operands) < 2 || t.operands[1] != 32 || components < 1 || components > 4 {
+		return vk.FormatUndefined, fmt.Errorf("input type %d isn't a scalar or vector of 32 bits", typeID)
+	}
+	return byComponents[components-1], nil
+}
+
+// descriptorType returns the descriptor type and count of a variable of the storage class
+// and type, it fails when the type isn't a descriptor or is truncated.
+func (m *spirvModule) descriptorType(storage, typeID uint32) (vk.DescriptorType, uint32, error) {
+	count := uint32(1)
+	t := m.types[typeID]
+	switch t.op {
+	case spirvOpTypeArray:
+		if len(t.operands) < 3 {
+			return 0, 0, fmt.Errorf("array type %d is truncated", typeID)
+		}
+		count = m.constants[t.operands[2]]
+		t = m.types[t.operands[1]]
+	case spirvOpTypeRuntimeArray:
+		if len(t.operands) < 2 {
+			return 0, 0, fmt.Errorf("runtime array type %d is truncated", typeID)
+		}
+		// the size comes from the layout, at least one descriptor is needed
+		t = m.types[t.operands[1]]
+	}
+	switch t.op {
+	case spirvOpTypeSampler:
+		return vk.DescriptorTypeSampler, count, nil
+	case spirvOpTypeSampledImage:
+		return vk.DescriptorTypeCombinedImageSampler, count, nil
+	case spirvOpTypeImage:
+		if len(t.operands) < 7 {
+			return 0, 0, fmt.Errorf("image type %d is truncated", t.operands[0])
+		}
+		dim, sampled := t.operands[2], t.operands[6]
+		switch {
+		case dim == spirvDimSubpassData:
+			return vk.DescriptorTypeInputAttachment, count, nil
+		case dim == spirvDimBuffer && sampled == 2:
+			return vk.DescriptorTypeStorageTexelBuffer, count, nil
+		case dim == spirvDimBuffer:
+			return vk.DescriptorTypeUniformTexelBuffer, count, nil
+		case sampled == 2:
+			return vk.DescriptorTypeStorageImage, count, nil
+		default:
+			return vk.DescriptorTypeSampledImage, count, nil
+		}
+	case spirvOpTypeStruct:
+		structID := t.operands[0]
+		switch {
+		case storage == spirvStorageStorageBuffer:
+			return vk.DescriptorTypeStorageBuffer, count, nil
+		case storage != spirvStorageUniform:
+			return 0, 0, fmt.Errorf("struct type %d isn't a block of a buffer", typeID)
+		}
+		if _, ok := m.decoration(structID, spirvDecorationBufferBlock); ok {
+			return vk.DescriptorTypeStorageBuffer, count, nil
+		}
+		return vk.DescriptorTypeUniformBuffer, count, nil
+	}
+	return 0, 0, fmt.Errorf("type %d isn't a descriptor", typeID)
+}
+
+// ReflectShader reflects the entry point of a SPIR-V module, the first one when entryPoint
+// is empty. Inputs must be scalars or vectors of 32 bits, matrices and 16 or 64 bits
+// components aren't supported. Runtime sized descriptor arrays get a count of 1.
+func ReflectShader(code []byte, entryPoint string) (ShaderReflection, error) {
+	var refl ShaderReflection
+	m, err := parseSpirv(code)
+	if err != nil {
+		err = fmt.Errorf("ReflectShader: %s", err)
+		return refl, err
+	}
+	var entry *spirvInstruction
+	for i := range m.entryPoints {
+		name := spirvString(m.entryPoints[i].operands[2:])
+		if len(entryPoint) == 0 || name == entryPoint {
+			entry = &m.entryPoints[i]
+			refl.EntryPoint = name
+			break
+		}
+	}
+	if entry == nil {
+		err := fmt.Errorf("ReflectShader: no entry point %q", entryPoint)
+		return refl, err
+	}
+	stage, ok := spirvStages[entry.operands[0]]
+	if !ok {
+		err := fmt.Errorf("ReflectShader: unsupported execution model %d", entry.operands[0])
+		return refl, err
+	}
+	refl.Stage = stage
+	interfaceIDs := make(map[uint32]bool)
+	for _, id := range entry.operands[2+spirvStringWords(entry.operands[2:]):] {
+		interfaceIDs[id] = true
+	}
+
+	for _, variable := range m.variables {
+		resultType, id, storage := variable.operands[0], variable.operands[1], variable.operands[2]
+		pointer := m.types[resultType]
+		if pointer.op != spirvOpTypePointer || len(pointer.operands) < 3 {
+			continue
+		}
+		typeID := pointer.operands[2]
+		switch storage {
+		case spirvStorageInput:
+			if !interfaceIDs[id] {
+				continue
+			}
+			if _, builtIn := m.decoration(id, spirvDecorationBuiltIn); builtIn {
+				continue
+			}
+			if m.isBlock(typeID) {
+				// blocks of built-ins such as gl_PerVertex
+				continue
+			}
+			location, ok := m.decoration(id, spirvDecorationLocation)
+			if !ok {
+				err := fmt.Errorf("ReflectShader: input %d has no location", id)
+				return refl, err
+			}
+			format, err := m.inputFormat(typeID)
+			if err != nil {
+				err = fmt.Errorf("ReflectShader: input at location %d: %s", location, err)
+				return refl, err
+			}
+			refl.Inputs = append(refl.Inputs, ShaderInput{
+				Name:     m.names[id],
+				Location: location,
+				Format:   format,
+			})
+		case spirvStorageUniformConstant, spirvStorageUniform, spirvStorageStorageBuffer:
+			binding, ok := m.decoration(id, spirvDecorationBinding)
+			if !ok {
+				continue
+			}
+			set, _ := m.decoration(id, spirvDecorationDescriptorSet)
+			descriptorType, count, err := m.descriptorType(storage, typeID)
+			if err != nil {
+				err = fmt.Errorf("ReflectShader: unsupported descriptor at set %d, binding %d: %s",
+					set, binding, err)
+				return refl, err
+			}
+			refl.Bindings = append(refl.Bindings, ShaderBinding{
+				Name: m.names[id],
+				Set:  set,
+				DescriptorBinding: DescriptorBinding{
+					Binding: binding,
+					Type:    descriptorType,
+					Count:   count,
+					Stages:  stage,
+				},
+			})
+		}
+	}
+	sort.Slice(refl.Inputs, func(i, j int) bool {
+		return refl.Inputs[i].Location < refl.Inputs[j].Location
+	})
+	sort.Slice(refl.Bindings, func(i, j int) bool {
+		a, b := refl.Bindings[i], refl.Bindings[j]
+		return a.Set < b.Set || a.Set == b.Set && a.Binding < b.Binding
+	})
+	return refl, nil
+}
+
+// ReflectShaderStages reflects the assets of the stages, e.g. TriangleShaders.
+func ReflectShaderStages(stages []ShaderStage) ([]ShaderReflection, error) {
+	refls := make([]ShaderReflection, 0, len(stages))
+	for _, stage := range stages {
+		data, err := LoadAsset(stage.Asset)
+		if err != nil {
+			err := fmt.Errorf("asset %s not found: %s", stage.Asset, err)
+			return nil, err
+		}
+		entryPoint := stage.EntryPoint
+		if len(entryPoint) == 0 {
+			entryPoint = "main"
+		}
+		refl, err := ReflectShader(data, entryPoint)
+		if err != nil {
+			err = fmt.Errorf("%s: %s", stage.Asset, err)
+			return nil, err
+		}
+		if refl.Stage != stage.Stage {
+			err := fmt.Errorf("%s: entry point %s is a %d stage, not %d",
+				stage.Asset, entryPoint, refl.Stage, stage.Stage)
+			return nil, err
+		}
+		refls = append(refls, refl)
+	}
+	return refls, nil
+}
+
+// VertexLayout interleaves the inputs of a vertex stage in a single binding, in the order
+// of their locations. It matches buffers packed the same way, like InterleavedLayout does
+// when the locations are 0, 1, 2...
+func (refl ShaderReflection) VertexLayout() (VertexLayout, error) {
+	var layout VertexLayout
+	if refl.Stage != vk.ShaderStageVertexBit {
+		err := fmt.Errorf("VertexLayout: stage %d has no vertex inputs", refl.Stage)
+		return layout, err
+	}
+	var offset uint32
+	for _, input := range refl.Inputs {
+		size, _ := formatSize(input.Format)
+		layout.Attributes = append(layout.Attributes, vk.VertexInputAttributeDescription{
+			Binding:  0,
+			Location: input.Location,
+			Format:   input.Format,
+			Offset:   offset,
+		})
+		offset += size
+	}
+	layout.Bindings = []vk.VertexInputBindingDescription{{
+		Binding:   0,
+		Stride:    offset,
+		InputRate: vk.VertexInputRateVertex,
+	}}
+	return layout, nil
+}
+
+// ReflectedSetBindings merges the bindings of the set declared by the stages into the bindings
+// of a descriptor set layout, see CreateDescriptorSetLayout. A binding declared by several
+// stages is visible to all of them, it must have the same type and count in each.
+func ReflectedSetBindings(set uint32, refls ...ShaderReflection) ([]DescriptorBinding, error) {
+	var bindings []DescriptorBinding
+	index := make(map[uint32]int)
+	for _, refl := range refls {
+		for _, b := range refl.Bindings {
+			if b.Set != set {
+				continue
+			}
+			i, ok := index[b.Binding]
+			if !ok {
+				index[b.Binding] = len(bindings)
+				bindings = append(bindings, b.DescriptorBinding)
+				continue
+			}
+			if bindings[i].Type != b.Type || bindings[i].Count != b.Count {
+				err := fmt.Errorf("ReflectedSetBindings: set %d, binding %d differs between stages",
+					set, b.Binding)
+				return nil, err
+			}
+			bindings[i].Stages |= b.Stages
+		}
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Binding < bindings[j].Binding
+	})
+	return bindings, nil
+}
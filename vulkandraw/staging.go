@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// StagingBuffer is a persistently mapped host visible buffer reused across uploads
+// to device local buffers, it grows when an upload doesn't fit.
+type StagingBuffer struct {
+	v       *VulkanDeviceInfo
+	cmdPool vk.CommandPool
+
+	buffer vk.Buffer
+	mem    vk.DeviceMemory
+	data   unsafe.Pointer
+	size   vk.DeviceSize
+
+	highWater vk.DeviceSize // the largest upload so far
+}
+
+// NewStagingBuffer creates a staging buffer of the given size,
+// uploads are recorded into command buffers allocated from cmdPool.
+func (v *VulkanDeviceInfo) NewStagingBuffer(cmdPool vk.CommandPool,
+	size vk.DeviceSize) (*StagingBuffer, error) {
+
+	st := &StagingBuffer{
+		v:       v,
+		cmdPool: cmdPool,
+	}
+	if err := st.allocate(size); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+func (st *StagingBuffer) allocate(size vk.DeviceSize) error {
+	buffer, mem, err := st.v.createBuffer(size, vk.BufferUsageTransferSrcBit,
+		vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+	if err != nil {
+		return err
+	}
+	var data unsafe.Pointer
+	err = vk.Error(vk.MapMemory(st.v.device, mem, 0, size, 0, &data))
+	if err != nil {
+		vk.DestroyBuffer(st.v.device, buffer, nil)
+		vk.FreeMemory(st.v.device, mem, nil)
+		err = fmt.Errorf("vk.MapMemory failed with %s", err)
+		return err
+	}
+	st.buffer = buffer
+	st.mem = mem
+	st.data = data
+	st.size = size
+	return nil
+}
+
+// Upload copies data into dstBuffer at dstOffset, it returns when the transfer completes
+// so the staging memory can be reused right away.
+func (st *StagingBuffer) Upload(data []byte, dstBuffer vk.Buffer, dstOffset vk.DeviceSize) error {
+	size := vk.DeviceSize(len(data))
+	if size == 0 {
+		return nil
+	}
+	if size > st.highWater {
+		st.highWater = size
+	}
+	if size > st.size {
+		newSize := st.size * 2
+		if newSize < size {
+			newSize = size
+		}
+		st.free()
+		if err := st.allocate(newSize); err != nil {
+			err = fmt.Errorf("StagingBuffer: cannot grow to %d bytes: %s", newSize, err)
+			return err
+		}
+	}
+	vk.MemCopyByte(st.data, data)
+
+	cmd, err := beginSingleTimeCommands(st.v.device, st.cmdPool)
+	if err != nil {
+		return err
+	}
+	regions := []vk.BufferCopy{{
+		SrcOffset: 0,
+		DstOffset: dstOffset,
+		Size:      size,
+	}}
+	vk.CmdCopyBuffer(cmd, st.buffer, dstBuffer, 1, regions)
+	return endSingleTimeCommands(st.v, st.cmdPool, st.v.graphicsQueue, cmd)
+}
+
+// HighWater returns the size of the largest upload so far, useful to size the buffer up front.
+func (st *StagingBuffer) HighWater() vk.DeviceSize {
+	return st.highWater
+}
+
+func (st *StagingBuffer) free() {
+	if st.buffer == vk.NullHandle {
+		return
+	}
+	vk.UnmapMemory(st.v.device, st.mem)
+	vk.DestroyBuffer(st.v.device, st.buffer, nil)
+	vk.FreeMemory(st.v.device, st.mem, nil)
+	st.buffer = vk.NullHandle
+	st.mem = vk.NullHandle
+	st.data = nil
+	st.size = 0
+}
+
+func (st *StagingBuffer) Destroy() {
+	if st == nil {
+		return
+	}
+	st.free()
+}
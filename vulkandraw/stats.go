@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// pipelineStatFlags are the statistics collected, results come in the order of the bits.
+const pipelineStatFlags = vk.QueryPipelineStatisticInputAssemblyVerticesBit |
+	vk.QueryPipelineStatisticInputAssemblyPrimitivesBit |
+	vk.QueryPipelineStatisticVertexShaderInvocationsBit |
+	vk.QueryPipelineStatisticClippingInvocationsBit |
+	vk.QueryPipelineStatisticClippingPrimitivesBit |
+	vk.QueryPipelineStatisticFragmentShaderInvocationsBit
+
+const pipelineStatCount = 6
+
+// PipelineStats are the counters of the scene draw within a frame.
+type PipelineStats struct {
+	// InputAssemblyVertices is the number of vertices fetched, including the ones
+	// of incomplete primitives.
+	InputAssemblyVertices uint64
+	// InputAssemblyPrimitives is the number of primitives assembled.
+	InputAssemblyPrimitives uint64
+	// VertexShaderInvocations may be lower than the vertex count when the
+	// results of previous invocations get reused.
+	VertexShaderInvocations uint64
+	// ClippingInvocations is the number of primitives reaching the clipping stage,
+	// ClippingPrimitives is the number of primitives that pass it and may be
+	// greater when clipping splits them.
+	ClippingInvocations uint64
+	ClippingPrimitives  uint64
+	// FragmentShaderInvocations grows with overdraw and may be lowered
+	// by early fragment tests, it's usually what to optimize for.
+	FragmentShaderInvocations uint64
+}
+
+// VulkanStatsInfo holds a pipeline statistics query for each command buffer.
+type VulkanStatsInfo struct {
+	device vk.Device
+	pool   vk.QueryPool
+
+	// inherited is true when the queries may stay active while secondary
+	// command buffers execute, see UseSecondaryBuffers
+	inherited bool
+}
+
+// CreatePipelineStats creates a query pool with n pipeline statistics queries,
+// the device must support the pipelineStatisticsQuery feature.
+func (v *VulkanDeviceInfo) CreatePipelineStats(n uint32) (VulkanStatsInfo, error) {
+	stats := VulkanStatsInfo{
+		device:    v.device,
+		inherited: v.enabledFeatures.InheritedQueries == vk.Bool32(vk.True),
+	}
+	if v.enabledFeatures.PipelineStatisticsQuery != vk.Bool32(vk.True) {
+		err := fmt.Errorf("CreatePipelineStats: pipelineStatisticsQuery feature is not supported")
+		return stats, err
+	}
+	queryPoolCreateInfo := vk.QueryPoolCreateInfo{
+		SType:              vk.StructureTypeQueryPoolCreateInfo,
+		QueryType:          vk.QueryTypePipelineStatistics,
+		QueryCount:         n,
+		PipelineStatistics: vk.QueryPipelineStatisticFlags(pipelineStatFlags),
+	}
+	err := vk.Error(vk.CreateQueryPool(v.device, &queryPoolCreateInfo, nil, &stats.pool))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateQueryPool failed with %s", err)
+		return stats, err
+	}
+	return stats, nil
+}
+
+// read returns the stats of the query, ok is false when the results are not yet available.
+func (stats *VulkanStatsInfo) read(query uint32) (PipelineStats, bool, error) {
+	var results [pipelineStatCount]uint64
+	const dataSize = pipelineStatCount * 8 // 8 = sizeof(uint64)
+	ret := vk.GetQueryPoolResults(stats.device, stats.pool, query, 1,
+		dataSize, unsafe.Pointer(&results[0]), dataSize, vk.QueryResultFlags(vk.QueryResult64Bit))
+	if ret == vk.NotReady {
+		return PipelineStats{}, false, nil
+	}
+	if err := vk.Error(ret); err != nil {
+		err = fmt.Errorf("vk.GetQueryPoolResults failed with %s", err)
+		return PipelineStats{}, false, err
+	}
+	return PipelineStats{
+		InputAssemblyVertices:     results[0],
+		InputAssemblyPrimitives:   results[1],
+		VertexShaderInvocations:   results[2],
+		ClippingInvocations:       results[3],
+		ClippingPrimitives:        results[4],
+		FragmentShaderInvocations: results[5],
+	}, true, nil
+}
+
+func (stats *VulkanStatsInfo) Destroy() {
+	if stats == nil || stats.device == nil {
+		return
+	}
+	vk.DestroyQueryPool(stats.device, stats.pool, nil)
+}
+
+// UsePipelineStats makes the command buffers collect pipeline statistics of the scene draw,
+// the pool needs a query per command buffer.
+func (r *VulkanRenderInfo) UsePipelineStats(stats *VulkanStatsInfo) {
+	r.stats = stats
+}
+
+// ReadPipelineStats returns the counters of the last frame submitted,
+// ok is false until the frame completes.
+func (r *VulkanRenderInfo) ReadPipelineStats() (stats PipelineStats, ok bool, err error) {
+	if r.stats == nil || r.lastImageIdx < 0 {
+		return stats, false, nil
+	}
+	if r.secondary != nil && !r.stats.inherited {
+		err = fmt.Errorf("ReadPipelineStats: inheritedQueries feature is not supported")
+		return stats, false, err
+	}
+	return r.stats.read(uint32(r.lastImageIdx))
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// SurfaceInfo caches what the GPU supports for a surface. The current extent changes
+// with the window, so the cache must be refreshed after a resize, RecreateSwapchain does.
+type SurfaceInfo struct {
+	gpu     vk.PhysicalDevice
+	surface vk.Surface
+
+	Capabilities vk.SurfaceCapabilities
+	Formats      []vk.SurfaceFormat
+	PresentModes []vk.PresentMode
+}
+
+func newSurfaceInfo(gpu vk.PhysicalDevice, surface vk.Surface) (*SurfaceInfo, error) {
+	si := &SurfaceInfo{
+		gpu:     gpu,
+		surface: surface,
+	}
+	if err := si.Refresh(); err != nil {
+		return nil, err
+	}
+	return si, nil
+}
+
+// Refresh queries the capabilities, formats and present modes of the surface again.
+func (si *SurfaceInfo) Refresh() error {
+	var caps vk.SurfaceCapabilities
+	err := vk.Error(vk.GetPhysicalDeviceSurfaceCapabilities(si.gpu, si.surface, &caps))
+	if err != nil {
+		err = fmt.Errorf("vk.GetPhysicalDeviceSurfaceCapabilities failed with %s", err)
+		return err
+	}
+	caps.Deref()
+	caps.CurrentExtent.Deref()
+	caps.MinImageExtent.Deref()
+	caps.MaxImageExtent.Deref()
+
+	var formatCount uint32
+	vk.GetPhysicalDeviceSurfaceFormats(si.gpu, si.surface, &formatCount, nil)
+	if formatCount == 0 {
+		err := fmt.Errorf("vk.GetPhysicalDeviceSurfaceFormats: surface reports no supported formats — surface may be invalid or lost")
+		return err
+	}
+	formats := make([]vk.SurfaceFormat, formatCount)
+	vk.GetPhysicalDeviceSurfaceFormats(si.gpu, si.surface, &formatCount, formats)
+	for i := range formats {
+		formats[i].Deref()
+		formats[i].Free()
+	}
+
+	var modeCount uint32
+	err = vk.Error(vk.GetPhysicalDeviceSurfacePresentModes(si.gpu, si.surface, &modeCount, nil))
+	if err != nil {
+		err = fmt.Errorf("vk.GetPhysicalDeviceSurfacePresentModes failed with %s", err)
+		return err
+	}
+	modes := make([]vk.PresentMode, modeCount)
+	vk.GetPhysicalDeviceSurfacePresentModes(si.gpu, si.surface, &modeCount, modes)
+
+	si.Capabilities = caps
+	si.Formats = formats
+	si.PresentModes = modes
+	log.Println("[INFO] got", formatCount, "physical device surface formats")
+	return nil
+}
+
+// SurfaceInfo returns the cached surface info of the device surface, querying it on first use.
+func (v *VulkanDeviceInfo) SurfaceInfo() (*SurfaceInfo, error) {
+	if v.surfaceInfo == nil {
+		si, err := newSurfaceInfo(v.gpu, v.surface)
+		if err != nil {
+			return nil, err
+		}
+		v.surfaceInfo = si
+	}
+	return v.surfaceInfo, nil
+}
+
+// SurfaceLostError reports that the surface went away under the swapchain, e.g. when
+// an Android app gets backgrounded. Unlike an out of date swapchain, recreating
+// the swapchain isn't enough, the surface must be recreated first, see RecreateSurface.
+type SurfaceLostError struct {
+	Call string // the call that failed with vk.ErrorSurfaceLost
+}
+
+func (e *SurfaceLostError) Error() string {
+	return fmt.Sprintf("%s failed: the surface was lost", e.Call)
+}
+
+// undefinedExtent is the width and height of the current extent of surfaces whose size
+// is picked by the swapchain.
+const undefinedExtent = 0xFFFFFFFF
+
+// chooseExtent returns the current extent of the surface, unless it's undefined and the
+// app picks the size, then desired gets clamped between the min and max image extents.
+func chooseExtent(caps vk.SurfaceCapabilities, desired vk.Extent2D) vk.Extent2D {
+	if caps.CurrentExtent.Width != undefinedExtent {
+		return caps.CurrentExtent
+	}
+	clamp := func(size, min, max uint32) uint32 {
+		if size < min {
+			return min
+		}
+		if size > max {
+			return max
+		}
+		return size
+	}
+	return vk.Extent2D{
+		Width:  clamp(desired.Width, caps.MinImageExtent.Width, caps.MaxImageExtent.Width),
+		Height: clamp(desired.Height, caps.MinImageExtent.Height, caps.MaxImageExtent.Height),
+	}
+}
+
+// chooseImageCount clamps the desired number of swapchain images to the range the surface
+// supports, a MaxImageCount of 0 means no limit. MinImageCount is used when desired is 0.
+func chooseImageCount(caps vk.SurfaceCapabilities, desired uint32) uint32 {
+	if desired < caps.MinImageCount {
+		desired = caps.MinImageCount
+	}
+	if caps.MaxImageCount > 0 && desired > caps.MaxImageCount {
+		desired = caps.MaxImageCount
+	}
+	return desired
+}
+
+// chooseLatencyPresentMode picks mailbox when supported, it replaces the queued image with
+// the newest one instead of blocking, and FIFO otherwise, which every surface supports.
+func chooseLatencyPresentMode(modes []vk.PresentMode) vk.PresentMode {
+	for _, mode := range modes {
+		if mode == vk.PresentModeMailbox {
+			return mode
+		}
+	}
+	log.Println("[INFO] surface doesn't support mailbox present mode, using FIFO")
+	return vk.PresentModeFifo
+}
+
+// chooseCompositeAlpha validates the requested composite alpha mode against the supported
+// ones. Without a request the images are opaque, or the compositor decides with
+// vk.CompositeAlphaInheritBit when opaque isn't supported.
+func chooseCompositeAlpha(requested vk.CompositeAlphaFlagBits,
+	supported vk.CompositeAlphaFlags) (vk.CompositeAlphaFlagBits, error) {
+
+	if requested != 0 {
+		if supported&vk.CompositeAlphaFlags(requested) == 0 {
+			err := fmt.Errorf("composite alpha %s is not supported, only %s",
+				compositeAlphaFlagsString(vk.CompositeAlphaFlags(requested)),
+				compositeAlphaFlagsString(supported))
+			return 0, err
+		}
+		return requested, nil
+	}
+	for _, mode := range []vk.CompositeAlphaFlagBits{
+		vk.CompositeAlphaOpaqueBit, vk.CompositeAlphaInheritBit,
+		vk.CompositeAlphaPreMultipliedBit, vk.CompositeAlphaPostMultipliedBit,
+	} {
+		if supported&vk.CompositeAlphaFlags(mode) != 0 {
+			return mode, nil
+		}
+	}
+	err := fmt.Errorf("the surface supports no composite alpha mode")
+	return 0, err
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+	"github.com/xlab/android-go/android"
+)
+
+// RecreateSurface replaces the lost device surface with a new one for the window,
+// once it's attached again. The whole sequence goes:
+//
+//  1. wait for the device to go idle and destroy the swapchain, it can't outlive its surface
+//  2. RecreateSurface
+//  3. CreateSwapchain, CreateFramebuffers, RecreatePipeline and record the command buffers
+//
+// The present queue family must be able to present to the new surface.
+func (v *VulkanDeviceInfo) RecreateSurface(window *android.NativeWindow) error {
+	surfaceCreateInfo := vk.AndroidSurfaceCreateInfo{
+		SType:  vk.StructureTypeAndroidSurfaceCreateInfo,
+		Window: (*vk.ANativeWindow)(window),
+	}
+	var surface vk.Surface
+	err := vk.Error(vk.CreateAndroidSurface(v.instance, &surfaceCreateInfo, nil, &surface))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateAndroidSurface failed with %s", err)
+		return err
+	}
+	var supported vk.Bool32
+	vk.GetPhysicalDeviceSurfaceSupport(v.gpu, v.queueFamilies[QueuePresent], surface, &supported)
+	if supported != vk.Bool32(vk.True) {
+		vk.DestroySurface(v.instance, surface, nil)
+		err := fmt.Errorf("RecreateSurface: present queue family %d can't present to the new surface",
+			v.queueFamilies[QueuePresent])
+		return err
+	}
+	vk.DestroySurface(v.instance, v.surface, nil)
+	v.surface = surface
+	// the cached queries belong to the old surface
+	v.surfaceInfo = nil
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// Color spaces of VK_EXT_swapchain_colorspace, the bindings only have the sRGB one.
+const (
+	ColorSpaceExtendedSrgbLinear vk.ColorSpace = 1000104002
+	ColorSpaceBt2020Linear       vk.ColorSpace = 1000104007
+	ColorSpaceHdr10St2084        vk.ColorSpace = 1000104008
+	ColorSpaceHdr10Hlg           vk.ColorSpace = 1000104010
+)
+
+// SDRSurfaceFormats are the 8-bit sRGB formats, in order of preference.
+var SDRSurfaceFormats = []vk.SurfaceFormat{
+	{Format: vk.FormatR8g8b8a8Unorm, ColorSpace: vk.ColorSpaceSrgbNonlinear},
+	{Format: vk.FormatB8g8r8a8Unorm, ColorSpace: vk.ColorSpaceSrgbNonlinear},
+}
+
+// HDRSurfaceFormats prefer 10-bit and float formats with HDR color spaces,
+// falling back to SDRSurfaceFormats. The HDR color spaces are only reported
+// when VK_EXT_swapchain_colorspace is enabled.
+var HDRSurfaceFormats = append([]vk.SurfaceFormat{
+	{Format: vk.FormatA2b10g10r10UnormPack32, ColorSpace: ColorSpaceHdr10St2084},
+	{Format: vk.FormatR16g16b16a16Sfloat, ColorSpace: ColorSpaceExtendedSrgbLinear},
+	{Format: vk.FormatR16g16b16a16Sfloat, ColorSpace: ColorSpaceBt2020Linear},
+	{Format: vk.FormatA2b10g10r10UnormPack32, ColorSpace: ColorSpaceHdr10Hlg},
+}, SDRSurfaceFormats...)
+
+func colorSpaceName(space vk.ColorSpace) string {
+	switch space {
+	case vk.ColorSpaceSrgbNonlinear:
+		return "sRGB nonlinear"
+	case ColorSpaceExtendedSrgbLinear:
+		return "extended sRGB linear"
+	case ColorSpaceBt2020Linear:
+		return "BT2020 linear"
+	case ColorSpaceHdr10St2084:
+		return "HDR10 ST2084"
+	case ColorSpaceHdr10Hlg:
+		return "HDR10 HLG"
+	default:
+		return fmt.Sprintf("color space %d", space)
+	}
+}
+
+// isHDRColorSpace reports whether the color space comes from VK_EXT_swapchain_colorspace.
+func isHDRColorSpace(space vk.ColorSpace) bool {
+	return space != vk.ColorSpaceSrgbNonlinear
+}
+
+// chooseSurfaceFormat picks the first preferred format/color space pair the surface reports,
+// both must match since a format is not valid with every color space. HDR pairs are
+// skipped without VK_EXT_swapchain_colorspace.
+func chooseSurfaceFormat(available, preferred []vk.SurfaceFormat,
+	hasColorspaceExt bool) (vk.SurfaceFormat, error) {
+
+	if len(available) == 1 && available[0].Format == vk.FormatUndefined {
+		// a single vk.FormatUndefined means the surface has no preferred format
+		// and any of them can be used with its color space, older drivers do report that
+		for _, pref := range preferred {
+			if pref.ColorSpace == available[0].ColorSpace {
+				return pref, nil
+			}
+		}
+		err := fmt.Errorf("no preferred format in %s", colorSpaceName(available[0].ColorSpace))
+		return vk.SurfaceFormat{}, err
+	}
+	for _, pref := range preferred {
+		if isHDRColorSpace(pref.ColorSpace) && !hasColorspaceExt {
+			continue
+		}
+		for _, format := range available {
+			if format.Format == pref.Format && format.ColorSpace == pref.ColorSpace {
+				return pref, nil
+			}
+		}
+	}
+	err := fmt.Errorf("none of %d preferred formats is supported by the surface", len(preferred))
+	return vk.SurfaceFormat{}, err
+}
+
+// findSurfaceFormat looks up the format among the ones the surface reports, taking
+// the first color space it comes with. HDR color spaces are skipped
+// without VK_EXT_swapchain_colorspace.
+func findSurfaceFormat(available []vk.SurfaceFormat, format vk.Format,
+	hasColorspaceExt bool) (vk.SurfaceFormat, error) {
+
+	if len(available) == 1 && available[0].Format == vk.FormatUndefined {
+		// any format goes, see chooseSurfaceFormat
+		return vk.SurfaceFormat{Format: format, ColorSpace: available[0].ColorSpace}, nil
+	}
+	for _, f := range available {
+		if isHDRColorSpace(f.ColorSpace) && !hasColorspaceExt {
+			continue
+		}
+		if f.Format == format {
+			return f, nil
+		}
+	}
+	err := fmt.Errorf("format %d is not supported by the surface", format)
+	return vk.SurfaceFormat{}, err
+}
@@ -0,0 +1,708 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/png"
+	"io"
+	"log"
+	"math/bits"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+type VulkanTextureInfo struct {
+	device vk.Device
+
+	image   vk.Image
+	mem     vk.DeviceMemory
+	view    vk.ImageView
+	sampler vk.Sampler
+
+	format    vk.Format
+	width     uint32
+	height    uint32
+	mipLevels uint32
+}
+
+func (t *VulkanTextureInfo) Destroy() {
+	if t == nil || t.device == nil {
+		return
+	}
+	vk.DestroySampler(t.device, t.sampler, nil)
+	vk.DestroyImageView(t.device, t.view, nil)
+	vk.DestroyImage(t.device, t.image, nil)
+	vk.FreeMemory(t.device, t.mem, nil)
+}
+
+// formatBlock describes the texel block of a format, uncompressed formats
+// have 1x1 blocks.
+type formatBlock struct {
+	width  uint32
+	height uint32
+	size   uint32
+}
+
+func formatBlockInfo(format vk.Format) (formatBlock, bool) {
+	switch format {
+	case vk.FormatEtc2R8g8b8UnormBlock, vk.FormatBc1RgbaUnormBlock:
+		return formatBlock{4, 4, 8}, true
+	case vk.FormatEtc2R8g8b8a8UnormBlock, vk.FormatBc3UnormBlock,
+		vk.FormatBc7UnormBlock, vk.FormatAstc4x4UnormBlock:
+		return formatBlock{4, 4, 16}, true
+	case vk.FormatR8g8b8a8Unorm:
+		return formatBlock{1, 1, 4}, true
+	default:
+		return formatBlock{}, false
+	}
+}
+
+// levelSize returns the size in bytes of a mip level, rounded up to whole blocks.
+func (b formatBlock) levelSize(width, height uint32) uint32 {
+	blocksX := (width + b.width - 1) / b.width
+	blocksY := (height + b.height - 1) / b.height
+	return blocksX * blocksY * b.size
+}
+
+// formatSupported checks that the format can be sampled with optimal tiling,
+// for compressed formats the corresponding textureCompression feature must be enabled.
+func (v *VulkanDeviceInfo) formatSupported(format vk.Format) bool {
+	enabled := true
+	switch format {
+	case vk.FormatEtc2R8g8b8UnormBlock, vk.FormatEtc2R8g8b8a8UnormBlock:
+		enabled = v.enabledFeatures.TextureCompressionETC2 == vk.Bool32(vk.True)
+	case vk.FormatBc1RgbaUnormBlock, vk.FormatBc3UnormBlock, vk.FormatBc7UnormBlock:
+		enabled = v.enabledFeatures.TextureCompressionBC == vk.Bool32(vk.True)
+	case vk.FormatAstc4x4UnormBlock:
+		enabled = v.enabledFeatures.TextureCompressionASTC_LDR == vk.Bool32(vk.True)
+	}
+	if !enabled {
+		return false
+	}
+	var props vk.FormatProperties
+	vk.GetPhysicalDeviceFormatProperties(v.gpu, format, &props)
+	props.Deref()
+	return props.OptimalTilingFeatures&vk.FormatFeatureFlags(vk.FormatFeatureSampledImageBit) != 0
+}
+
+// mipLevels returns the length of the full mip chain of an image, down to 1x1.
+func mipLevels(width, height uint32) uint32 {
+	return uint32(bits.Len32(maxUint32(maxUint32(width, height), 1)))
+}
+
+// blitSupported checks that mipmaps of the format can be generated with linear blits.
+func (v *VulkanDeviceInfo) blitSupported(format vk.Format) bool {
+	var props vk.FormatProperties
+	vk.GetPhysicalDeviceFormatProperties(v.gpu, format, &props)
+	props.Deref()
+	features := vk.FormatFeatureFlags(vk.FormatFeatureBlitSrcBit | vk.FormatFeatureBlitDstBit |
+		vk.FormatFeatureSampledImageFilterLinearBit)
+	return props.OptimalTilingFeatures&features == features
+}
+
+// linearTilingSupported checks that the format can be sampled with linear tiling.
+func (v *VulkanDeviceInfo) linearTilingSupported(format vk.Format) bool {
+	var props vk.FormatProperties
+	vk.GetPhysicalDeviceFormatProperties(v.gpu, format, &props)
+	props.Deref()
+	return props.LinearTilingFeatures&vk.FormatFeatureFlags(vk.FormatFeatureSampledImageBit) != 0
+}
+
+// linearTextureMaxTexels bounds the textures written directly into linear tiled images,
+// sampling linear images is slower, so it only pays off for small ones.
+const linearTextureMaxTexels = 256 * 256
+
+// ktxIdentifier is the file signature of KTX 1.1 containers.
+var ktxIdentifier = []byte{
+	0xAB, 0x4B, 0x54, 0x58, 0x20, 0x31, 0x31, 0xBB, 0x0D, 0x0A, 0x1A, 0x0A,
+}
+
+// ktxFormats maps glInternalFormat values to the Vulkan formats we can upload.
+var ktxFormats = map[uint32]vk.Format{
+	0x9274: vk.FormatEtc2R8g8b8UnormBlock,   // GL_COMPRESSED_RGB8_ETC2
+	0x9278: vk.FormatEtc2R8g8b8a8UnormBlock, // GL_COMPRESSED_RGBA8_ETC2_EAC
+	0x83F1: vk.FormatBc1RgbaUnormBlock,      // GL_COMPRESSED_RGBA_S3TC_DXT1_EXT
+	0x83F3: vk.FormatBc3UnormBlock,          // GL_COMPRESSED_RGBA_S3TC_DXT5_EXT
+	0x8E8C: vk.FormatBc7UnormBlock,          // GL_COMPRESSED_RGBA_BPTC_UNORM
+	0x93B0: vk.FormatAstc4x4UnormBlock,      // GL_COMPRESSED_RGBA_ASTC_4x4_KHR
+	0x8058: vk.FormatR8g8b8a8Unorm,          // GL_RGBA8
+}
+
+type ktxHeader struct {
+	Endianness            uint32
+	GlType                uint32
+	GlTypeSize            uint32
+	GlFormat              uint32
+	GlInternalFormat      uint32
+	GlBaseInternalFormat  uint32
+	PixelWidth            uint32
+	PixelHeight           uint32
+	PixelDepth            uint32
+	NumberOfArrayElements uint32
+	NumberOfFaces         uint32
+	NumberOfMipmapLevels  uint32
+	BytesOfKeyValueData   uint32
+}
+
+type ktxTexture struct {
+	format vk.Format
+	width  uint32
+	height uint32
+	levels [][]byte
+}
+
+func isKTX(data []byte) bool {
+	return bytes.HasPrefix(data, ktxIdentifier)
+}
+
+// parseKTX reads a KTX 1.1 container with a single 2D image and its mip chain.
+func parseKTX(data []byte) (*ktxTexture, error) {
+	if !isKTX(data) {
+		return nil, fmt.Errorf("parseKTX: not a KTX 1.1 file")
+	}
+	data = data[len(ktxIdentifier):]
+	var order binary.ByteOrder = binary.LittleEndian
+	if len(data) >= 4 && binary.BigEndian.Uint32(data) == 0x04030201 {
+		order = binary.BigEndian
+	}
+	r := bytes.NewReader(data)
+	var hdr ktxHeader
+	if err := binary.Read(r, order, &hdr); err != nil {
+		return nil, fmt.Errorf("parseKTX: failed to read header: %s", err)
+	}
+	if hdr.PixelDepth > 1 || hdr.NumberOfArrayElements > 0 || hdr.NumberOfFaces != 1 {
+		return nil, fmt.Errorf("parseKTX: only 2D non-array textures are supported")
+	}
+	format, ok := ktxFormats[hdr.GlInternalFormat]
+	if !ok {
+		return nil, fmt.Errorf("parseKTX: unsupported glInternalFormat 0x%x", hdr.GlInternalFormat)
+	}
+	block, _ := formatBlockInfo(format)
+	if _, err := r.Seek(int64(hdr.BytesOfKeyValueData), 1); err != nil {
+		return nil, fmt.Errorf("parseKTX: failed to skip key-value data: %s", err)
+	}
+	tex := &ktxTexture{
+		format: format,
+		width:  hdr.PixelWidth,
+		height: maxUint32(hdr.PixelHeight, 1),
+	}
+	levelCount := maxUint32(hdr.NumberOfMipmapLevels, 1)
+	for level := uint32(0); level < levelCount; level++ {
+		var imageSize uint32
+		if err := binary.Read(r, order, &imageSize); err != nil {
+			return nil, fmt.Errorf("parseKTX: failed to read level %d size: %s", level, err)
+		}
+		w := maxUint32(tex.width>>level, 1)
+		h := maxUint32(tex.height>>level, 1)
+		if expected := block.levelSize(w, h); imageSize != expected {
+			err := fmt.Errorf("parseKTX: level %d is %d bytes, expected %d for %dx%d",
+				level, imageSize, expected, w, h)
+			return nil, err
+		}
+		levelData := make([]byte, imageSize)
+		if _, err := io.ReadFull(r, levelData); err != nil {
+			return nil, fmt.Errorf("parseKTX: failed to read level %d: %s", level, err)
+		}
+		tex.levels = append(tex.levels, levelData)
+		// mipPadding aligns the next level to 4 bytes
+		r.Seek(int64(3-(imageSize+3)%4), 1)
+	}
+	return tex, nil
+}
+
+// CreateCompressedTexture uploads a pre-compressed KTX texture asset as is,
+// if the GPU lacks support for the format the fallback asset gets loaded instead.
+func (v *VulkanDeviceInfo) CreateCompressedTexture(cmdPool vk.CommandPool,
+	name, fallbackName string) (VulkanTextureInfo, error) {
+
+	data, err := LoadAsset(name)
+	if err != nil {
+		err := fmt.Errorf("asset %s not found: %s", name, err)
+		return VulkanTextureInfo{}, err
+	}
+	ktx, err := parseKTX(data)
+	if err != nil {
+		err = fmt.Errorf("texture %s: %s", name, err)
+		return VulkanTextureInfo{}, err
+	}
+	if v.formatSupported(ktx.format) {
+		return v.createTexture(cmdPool, ktx.format, ktx.width, ktx.height, ktx.levels, false)
+	}
+	if len(fallbackName) == 0 {
+		err := fmt.Errorf("texture %s: format %d is not supported by GPU", name, ktx.format)
+		return VulkanTextureInfo{}, err
+	}
+	log.Printf("[WARN] texture %s: format %d is not supported, falling back to %s",
+		name, ktx.format, fallbackName)
+	return v.LoadTexture(cmdPool, fallbackName)
+}
+
+// LoadTexture uploads a texture asset, either a KTX container or
+// an image in any format registered with the image package (e.g. PNG).
+func (v *VulkanDeviceInfo) LoadTexture(cmdPool vk.CommandPool,
+	name string) (VulkanTextureInfo, error) {
+
+	data, err := LoadAsset(name)
+	if err != nil {
+		err := fmt.Errorf("asset %s not found: %s", name, err)
+		return VulkanTextureInfo{}, err
+	}
+	if isKTX(data) {
+		ktx, err := parseKTX(data)
+		if err != nil {
+			err = fmt.Errorf("texture %s: %s", name, err)
+			return VulkanTextureInfo{}, err
+		}
+		if !v.formatSupported(ktx.format) {
+			err := fmt.Errorf("texture %s: format %d is not supported by GPU", name, ktx.format)
+			return VulkanTextureInfo{}, err
+		}
+		return v.createTexture(cmdPool, ktx.format, ktx.width, ktx.height, ktx.levels, false)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		err = fmt.Errorf("texture %s: %s", name, err)
+		return VulkanTextureInfo{}, err
+	}
+	return v.CreateTextureFromImage(cmdPool, img)
+}
+
+// CreateTextureFromImage uploads an image as a vk.FormatR8g8b8a8Unorm texture,
+// the mip chain gets generated on the GPU.
+func (v *VulkanDeviceInfo) CreateTextureFromImage(cmdPool vk.CommandPool,
+	img image.Image) (VulkanTextureInfo, error) {
+
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return v.createTexture(cmdPool, vk.FormatR8g8b8a8Unorm,
+		uint32(bounds.Dx()), uint32(bounds.Dy()), [][]byte{rgba.Pix}, true)
+}
+
+// createTexture uploads the mip levels into an optimal tiled image via a staging buffer.
+// Data is copied as is, so compressed levels are uploaded in whole blocks. With genMips
+// only the first level is uploaded and the rest of the chain gets blitted from it,
+// if the format supports it. Small uncompressed textures without mipmaps get written
+// into a linear tiled image directly instead, if the GPU can sample the format
+// with linear tiling.
+func (v *VulkanDeviceInfo) createTexture(cmdPool vk.CommandPool, format vk.Format,
+	width, height uint32, levels [][]byte, genMips bool) (VulkanTextureInfo, error) {
+
+	tex := VulkanTextureInfo{
+		device:    v.device,
+		format:    format,
+		width:     width,
+		height:    height,
+		mipLevels: uint32(len(levels)),
+	}
+	if genMips && len(levels) == 1 {
+		if v.blitSupported(format) {
+			tex.mipLevels = mipLevels(width, height)
+		} else {
+			log.Printf("[WARN] format %d can't be blitted, texture left without mipmaps", format)
+			genMips = false
+		}
+	}
+	block, ok := formatBlockInfo(format)
+	if ok && block.width == 1 && block.height == 1 && tex.mipLevels == 1 &&
+		width*height <= linearTextureMaxTexels && v.linearTilingSupported(format) {
+		return v.createLinearTexture(cmdPool, tex, block, levels[0])
+	}
+
+	// Phase 1: vk.CreateBuffer
+	//			fill a staging buffer with all the levels,
+	//			offsets must be aligned to the block size and 4 bytes
+
+	const offsetAlign = 16
+	offsets := make([]vk.DeviceSize, len(levels))
+	var stagingSize vk.DeviceSize
+	for i := range levels {
+		offsets[i] = stagingSize
+		stagingSize += vk.DeviceSize(len(levels[i]))
+		stagingSize = (stagingSize + offsetAlign - 1) &^ (offsetAlign - 1)
+	}
+	staging, stagingMem, err := v.createBuffer(stagingSize,
+		vk.BufferUsageTransferSrcBit, vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+	if err != nil {
+		return tex, err
+	}
+	defer func() {
+		vk.DestroyBuffer(v.device, staging, nil)
+		vk.FreeMemory(v.device, stagingMem, nil)
+	}()
+	var data unsafe.Pointer
+	err = vk.Error(vk.MapMemory(v.device, stagingMem, 0, stagingSize, 0, &data))
+	if err != nil {
+		err = fmt.Errorf("vk.MapMemory failed with %s", err)
+		return tex, err
+	}
+	for i := range levels {
+		vk.MemCopyByte(unsafe.Pointer(uintptr(data)+uintptr(offsets[i])), levels[i])
+	}
+	vk.UnmapMemory(v.device, stagingMem)
+
+	// Phase 2: vk.CreateImage
+	//			vk.AllocateMemory
+	//			create the texture image in device local memory
+
+	imageCreateInfo := vk.ImageCreateInfo{
+		SType:     vk.StructureTypeImageCreateInfo,
+		ImageType: vk.ImageType2d,
+		Format:    format,
+		Extent: vk.Extent3D{
+			Width:  width,
+			Height: height,
+			Depth:  1,
+		},
+		MipLevels:     tex.mipLevels,
+		ArrayLayers:   1,
+		Samples:       vk.SampleCount1Bit,
+		Tiling:        vk.ImageTilingOptimal,
+		Usage:         vk.ImageUsageFlags(vk.ImageUsageTransferDstBit | vk.ImageUsageSampledBit),
+		SharingMode:   vk.SharingModeExclusive,
+		InitialLayout: vk.ImageLayoutUndefined,
+	}
+	if genMips {
+		// each level is the blit source of the next one
+		imageCreateInfo.Usage |= vk.ImageUsageFlags(vk.ImageUsageTransferSrcBit)
+	}
+	if err := validateImageCreateInfo(v.gpu, &imageCreateInfo); err != nil {
+		err = fmt.Errorf("createTexture: %s", err)
+		return tex, err
+	}
+	err = vk.Error(vk.CreateImage(v.device, &imageCreateInfo, nil, &tex.image))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateImage failed with %s", err)
+		return tex, err
+	}
+	tex.mem, err = allocateImageMemory(v.device, v.gpu, tex.image, vk.MemoryPropertyDeviceLocalBit)
+	if err != nil {
+		tex.Destroy()
+		return tex, err
+	}
+
+	// Phase 3: vk.CmdCopyBufferToImage
+	//			copy each level and transition the image for sampling
+
+	cmd, err := beginSingleTimeCommands(v.device, cmdPool)
+	if err != nil {
+		tex.Destroy()
+		return tex, err
+	}
+	subresourceRange := vk.ImageSubresourceRange{
+		AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+		LevelCount: tex.mipLevels,
+		LayerCount: 1,
+	}
+	transitionImageLayout(cmd, tex.image, subresourceRange,
+		vk.ImageLayoutUndefined, vk.ImageLayoutTransferDstOptimal)
+	regions := make([]vk.BufferImageCopy, len(levels))
+	for i := range regions {
+		regions[i] = vk.BufferImageCopy{
+			BufferOffset: offsets[i],
+			ImageSubresource: vk.ImageSubresourceLayers{
+				AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+				MipLevel:   uint32(i),
+				LayerCount: 1,
+			},
+			ImageExtent: vk.Extent3D{
+				Width:  maxUint32(width>>uint(i), 1),
+				Height: maxUint32(height>>uint(i), 1),
+				Depth:  1,
+			},
+		}
+	}
+	vk.CmdCopyBufferToImage(cmd, staging, tex.image, vk.ImageLayoutTransferDstOptimal,
+		uint32(len(regions)), regions)
+	if genMips {
+		cmdGenerateMipmaps(cmd, tex.image, width, height, tex.mipLevels)
+	} else {
+		transitionImageLayout(cmd, tex.image, subresourceRange,
+			vk.ImageLayoutTransferDstOptimal, vk.ImageLayoutShaderReadOnlyOptimal)
+	}
+	if err := endSingleTimeCommands(v, cmdPool, v.graphicsQueue, cmd); err != nil {
+		tex.Destroy()
+		return tex, err
+	}
+
+	// Phase 4: vk.CreateImageView
+	//			vk.CreateSampler
+
+	if err := tex.createView(subresourceRange); err != nil {
+		tex.Destroy()
+		return tex, err
+	}
+	if err := tex.createSampler(); err != nil {
+		tex.Destroy()
+		return tex, err
+	}
+	return tex, nil
+}
+
+// createLinearTexture writes the pixels of a single level texture straight into
+// a host visible linear tiled image, no staging buffer nor copy is involved.
+func (v *VulkanDeviceInfo) createLinearTexture(cmdPool vk.CommandPool, tex VulkanTextureInfo,
+	block formatBlock, pixels []byte) (VulkanTextureInfo, error) {
+
+	// Phase 1: vk.CreateImage
+	//			vk.AllocateMemory
+	//			create the texture image in host visible memory
+
+	imageCreateInfo := vk.ImageCreateInfo{
+		SType:     vk.StructureTypeImageCreateInfo,
+		ImageType: vk.ImageType2d,
+		Format:    tex.format,
+		Extent: vk.Extent3D{
+			Width:  tex.width,
+			Height: tex.height,
+			Depth:  1,
+		},
+		MipLevels:   1,
+		ArrayLayers: 1,
+		Samples:     vk.SampleCount1Bit,
+		Tiling:      vk.ImageTilingLinear,
+		Usage:       vk.ImageUsageFlags(vk.ImageUsageSampledBit),
+		SharingMode: vk.SharingModeExclusive,
+		// keeps the contents written by the host across the first transition
+		InitialLayout: vk.ImageLayoutPreinitialized,
+	}
+	if err := validateImageCreateInfo(v.gpu, &imageCreateInfo); err != nil {
+		err = fmt.Errorf("createTexture: %s", err)
+		return tex, err
+	}
+	err := vk.Error(vk.CreateImage(v.device, &imageCreateInfo, nil, &tex.image))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateImage failed with %s", err)
+		return tex, err
+	}
+	tex.mem, err = allocateImageMemory(v.device, v.gpu, tex.image,
+		vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+	if err != nil {
+		tex.Destroy()
+		return tex, err
+	}
+
+	// Phase 2: vk.MapMemory
+	//			copy row by row, the driver decides the row pitch
+
+	subresource := vk.ImageSubresource{
+		AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+	}
+	var layout vk.SubresourceLayout
+	vk.GetImageSubresourceLayout(v.device, tex.image, &subresource, &layout)
+	layout.Deref()
+	var data unsafe.Pointer
+	err = vk.Error(vk.MapMemory(v.device, tex.mem, layout.Offset, layout.Size, 0, &data))
+	if err != nil {
+		tex.Destroy()
+		err = fmt.Errorf("vk.MapMemory failed with %s", err)
+		return tex, err
+	}
+	rowSize := tex.width * block.size
+	for y := uint32(0); y < tex.height; y++ {
+		row := pixels[y*rowSize : (y+1)*rowSize]
+		vk.MemCopyByte(unsafe.Pointer(uintptr(data)+uintptr(vk.DeviceSize(y)*layout.RowPitch)), row)
+	}
+	vk.UnmapMemory(v.device, tex.mem)
+
+	// Phase 3: transition the image for sampling
+
+	cmd, err := beginSingleTimeCommands(v.device, cmdPool)
+	if err != nil {
+		tex.Destroy()
+		return tex, err
+	}
+	subresourceRange := vk.ImageSubresourceRange{
+		AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+		LevelCount: 1,
+		LayerCount: 1,
+	}
+	transitionImageLayout(cmd, tex.image, subresourceRange,
+		vk.ImageLayoutPreinitialized, vk.ImageLayoutShaderReadOnlyOptimal)
+	if err := endSingleTimeCommands(v, cmdPool, v.graphicsQueue, cmd); err != nil {
+		tex.Destroy()
+		return tex, err
+	}
+
+	// Phase 4: vk.CreateImageView
+	//			vk.CreateSampler
+
+	if err := tex.createView(subresourceRange); err != nil {
+		tex.Destroy()
+		return tex, err
+	}
+	if err := tex.createSampler(); err != nil {
+		tex.Destroy()
+		return tex, err
+	}
+	return tex, nil
+}
+
+func (t *VulkanTextureInfo) createView(subresourceRange vk.ImageSubresourceRange) error {
+	viewCreateInfo := vk.ImageViewCreateInfo{
+		SType:    vk.StructureTypeImageViewCreateInfo,
+		Image:    t.image,
+		ViewType: vk.ImageViewType2d,
+		Format:   t.format,
+		Components: vk.ComponentMapping{
+			R: vk.ComponentSwizzleR,
+			G: vk.ComponentSwizzleG,
+			B: vk.ComponentSwizzleB,
+			A: vk.ComponentSwizzleA,
+		},
+		SubresourceRange: subresourceRange,
+	}
+	err := vk.Error(vk.CreateImageView(t.device, &viewCreateInfo, nil, &t.view))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateImageView failed with %s", err)
+		return err
+	}
+	return nil
+}
+
+// createSampler creates a trilinear sampler covering all the mip levels of the texture.
+func (t *VulkanTextureInfo) createSampler() error {
+	samplerCreateInfo := vk.SamplerCreateInfo{
+		SType:         vk.StructureTypeSamplerCreateInfo,
+		MagFilter:     vk.FilterLinear,
+		MinFilter:     vk.FilterLinear,
+		MipmapMode:    vk.SamplerMipmapModeLinear,
+		AddressModeU:  vk.SamplerAddressModeRepeat,
+		AddressModeV:  vk.SamplerAddressModeRepeat,
+		AddressModeW:  vk.SamplerAddressModeRepeat,
+		MaxAnisotropy: 1,
+		CompareOp:     vk.CompareOpNever,
+		MinLod:        0,
+		MaxLod:        float32(t.mipLevels),
+		BorderColor:   vk.BorderColorFloatOpaqueBlack,
+	}
+	err := vk.Error(vk.CreateSampler(t.device, &samplerCreateInfo, nil, &t.sampler))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateSampler failed with %s", err)
+		return err
+	}
+	return nil
+}
+
+// cmdGenerateMipmaps fills the mip chain by blitting each level into the next one
+// with a linear filter. All levels must be in vk.ImageLayoutTransferDstOptimal with
+// the first one uploaded, they end up in vk.ImageLayoutShaderReadOnlyOptimal.
+func cmdGenerateMipmaps(cmd vk.CommandBuffer, image vk.Image, width, height, levels uint32) {
+	levelRange := func(level uint32) vk.ImageSubresourceRange {
+		return vk.ImageSubresourceRange{
+			AspectMask:   vk.ImageAspectFlags(vk.ImageAspectColorBit),
+			BaseMipLevel: level,
+			LevelCount:   1,
+			LayerCount:   1,
+		}
+	}
+	levelLayers := func(level uint32) vk.ImageSubresourceLayers {
+		return vk.ImageSubresourceLayers{
+			AspectMask: vk.ImageAspectFlags(vk.ImageAspectColorBit),
+			MipLevel:   level,
+			LayerCount: 1,
+		}
+	}
+	w, h := int32(width), int32(height)
+	for i := uint32(1); i < levels; i++ {
+		transitionImageLayout(cmd, image, levelRange(i-1),
+			vk.ImageLayoutTransferDstOptimal, vk.ImageLayoutTransferSrcOptimal)
+		nextW, nextH := w/2, h/2
+		if nextW < 1 {
+			nextW = 1
+		}
+		if nextH < 1 {
+			nextH = 1
+		}
+		regions := []vk.ImageBlit{{
+			SrcSubresource: levelLayers(i - 1),
+			SrcOffsets:     [2]vk.Offset3D{{}, {X: w, Y: h, Z: 1}},
+			DstSubresource: levelLayers(i),
+			DstOffsets:     [2]vk.Offset3D{{}, {X: nextW, Y: nextH, Z: 1}},
+		}}
+		vk.CmdBlitImage(cmd, image, vk.ImageLayoutTransferSrcOptimal,
+			image, vk.ImageLayoutTransferDstOptimal, 1, regions, vk.FilterLinear)
+		transitionImageLayout(cmd, image, levelRange(i-1),
+			vk.ImageLayoutTransferSrcOptimal, vk.ImageLayoutShaderReadOnlyOptimal)
+		w, h = nextW, nextH
+	}
+	transitionImageLayout(cmd, image, levelRange(levels-1),
+		vk.ImageLayoutTransferDstOptimal, vk.ImageLayoutShaderReadOnlyOptimal)
+}
+
+// Texture pairs an image with its GPU texture, which only gets created on the first Upload,
+// e.g. so textures can be loaded before the device exists. Destroy releases the image,
+// memory, view and sampler, the CPU image is kept so it can be uploaded again.
+type Texture struct {
+	Image image.Image
+
+	gpu      VulkanTextureInfo
+	uploaded bool
+}
+
+// NewTexture wraps the image, nothing is created on the GPU until Upload.
+func NewTexture(img image.Image) *Texture {
+	return &Texture{
+		Image: img,
+	}
+}
+
+// Upload stages, transitions and copies the image into a vk.FormatR8g8b8a8Unorm texture
+// with mipmaps, see CreateTextureFromImage. It's a no-op once uploaded.
+func (t *Texture) Upload(v *VulkanDeviceInfo, cmdPool vk.CommandPool) error {
+	if t.uploaded {
+		return nil
+	}
+	if t.Image == nil {
+		err := fmt.Errorf("Texture.Upload: there's no image to upload")
+		return err
+	}
+	gpu, err := v.CreateTextureFromImage(cmdPool, t.Image)
+	if err != nil {
+		return err
+	}
+	t.gpu = gpu
+	t.uploaded = true
+	return nil
+}
+
+// Uploaded reports whether the texture is on the GPU.
+func (t *Texture) Uploaded() bool {
+	return t.uploaded
+}
+
+// Bind writes the texture into the combined image sampler binding of the set,
+// it must have been uploaded and the set must not be in use by the GPU.
+func (t *Texture) Bind(set vk.DescriptorSet, binding uint32) error {
+	if !t.uploaded {
+		err := fmt.Errorf("Texture.Bind: the texture must be uploaded first")
+		return err
+	}
+	writes := []vk.WriteDescriptorSet{{
+		SType:           vk.StructureTypeWriteDescriptorSet,
+		DstSet:          set,
+		DstBinding:      binding,
+		DescriptorCount: 1,
+		DescriptorType:  vk.DescriptorTypeCombinedImageSampler,
+		PImageInfo: []vk.DescriptorImageInfo{{
+			Sampler:     t.gpu.sampler,
+			ImageView:   t.gpu.view,
+			ImageLayout: vk.ImageLayoutShaderReadOnlyOptimal,
+		}},
+	}}
+	vk.UpdateDescriptorSets(t.gpu.device, uint32(len(writes)), writes, 0, nil)
+	return nil
+}
+
+func (t *Texture) Destroy() {
+	if t == nil || !t.uploaded {
+		return
+	}
+	t.gpu.Destroy()
+	t.gpu = VulkanTextureInfo{}
+	t.uploaded = false
+}
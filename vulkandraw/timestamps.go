@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// VulkanTimestampsInfo holds a pair of timestamp queries for each command buffer,
+// written before and after its render passes.
+type VulkanTimestampsInfo struct {
+	device vk.Device
+	pool   vk.QueryPool
+
+	// period is the number of nanoseconds per timestamp tick
+	period float32
+	// mask keeps the valid bits of the timestamps, the others are undefined
+	mask uint64
+}
+
+// CreateTimestamps creates a query pool with a pair of timestamp queries for each of the
+// n command buffers. The graphics queue must support timestamps, which is the case on
+// every queue when the timestampComputeAndGraphics limit is set.
+func (v *VulkanDeviceInfo) CreateTimestamps(n uint32) (VulkanTimestampsInfo, error) {
+	ts := VulkanTimestampsInfo{
+		device: v.device,
+		period: v.caps.Limits.TimestampPeriod,
+	}
+	queueProps := getQueueFamilyProperties(v.gpu)
+	validBits := queueProps[v.queueFamilies[QueueGraphics]].TimestampValidBits
+	if v.caps.Limits.TimestampComputeAndGraphics != vk.Bool32(vk.True) && validBits == 0 {
+		err := fmt.Errorf("CreateTimestamps: graphics queue family %d doesn't support timestamps",
+			v.queueFamilies[QueueGraphics])
+		return ts, err
+	}
+	ts.mask = ^uint64(0)
+	if validBits > 0 && validBits < 64 {
+		ts.mask = 1<<validBits - 1
+	}
+	queryPoolCreateInfo := vk.QueryPoolCreateInfo{
+		SType:      vk.StructureTypeQueryPoolCreateInfo,
+		QueryType:  vk.QueryTypeTimestamp,
+		QueryCount: 2 * n,
+	}
+	err := vk.Error(vk.CreateQueryPool(v.device, &queryPoolCreateInfo, nil, &ts.pool))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateQueryPool failed with %s", err)
+		return ts, err
+	}
+	return ts, nil
+}
+
+// cmdBegin resets the pair of queries of command buffer i and writes the first timestamp
+// once all previous commands started, it must be called outside of a render pass.
+func (ts *VulkanTimestampsInfo) cmdBegin(cmd vk.CommandBuffer, i int) {
+	vk.CmdResetQueryPool(cmd, ts.pool, uint32(2*i), 2)
+	vk.CmdWriteTimestamp(cmd, vk.PipelineStageTopOfPipeBit, ts.pool, uint32(2*i))
+}
+
+// cmdEnd writes the second timestamp once all previous commands completed.
+func (ts *VulkanTimestampsInfo) cmdEnd(cmd vk.CommandBuffer, i int) {
+	vk.CmdWriteTimestamp(cmd, vk.PipelineStageBottomOfPipeBit, ts.pool, uint32(2*i+1))
+}
+
+// read returns the time between the pair of timestamps of command buffer i,
+// ok is false when the results are not yet available.
+func (ts *VulkanTimestampsInfo) read(i int) (time.Duration, bool, error) {
+	var results [2]uint64
+	const dataSize = 2 * 8 // 8 = sizeof(uint64)
+	ret := vk.GetQueryPoolResults(ts.device, ts.pool, uint32(2*i), 2,
+		dataSize, unsafe.Pointer(&results[0]), 8, vk.QueryResultFlags(vk.QueryResult64Bit))
+	if ret == vk.NotReady {
+		return 0, false, nil
+	}
+	if err := vk.Error(ret); err != nil {
+		err = fmt.Errorf("vk.GetQueryPoolResults failed with %s", err)
+		return 0, false, err
+	}
+	// the timestamps wrap around after the valid bits
+	ticks := (results[1] - results[0]) & ts.mask
+	return time.Duration(float64(ticks) * float64(ts.period)), true, nil
+}
+
+func (ts *VulkanTimestampsInfo) Destroy() {
+	if ts == nil || ts.device == nil {
+		return
+	}
+	vk.DestroyQueryPool(ts.device, ts.pool, nil)
+}
+
+// UseTimestamps makes the command buffers write timestamps around their render passes,
+// the pool needs a pair of queries per command buffer.
+func (r *VulkanRenderInfo) UseTimestamps(ts *VulkanTimestampsInfo) {
+	r.timestamps = ts
+}
+
+// ReadPassDuration returns the GPU time the render passes of the last frame submitted took,
+// ok is false until the frame completes.
+func (r *VulkanRenderInfo) ReadPassDuration() (d time.Duration, ok bool, err error) {
+	if r.timestamps == nil || r.lastImageIdx < 0 {
+		return 0, false, nil
+	}
+	return r.timestamps.read(r.lastImageIdx)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ValidationCounters count the messages of the debug report callback, which may be
+// invoked from any thread the driver calls from. The callback counts into the
+// ones given as VulkanDeviceOptions.DebugUserData, or into global ones otherwise.
+type ValidationCounters struct {
+	errors   int64
+	warnings int64
+
+	mux        sync.Mutex
+	firstError error
+}
+
+var validationCounters ValidationCounters
+
+func (c *ValidationCounters) count(isError bool, messageCode int32, message, layerPrefix string) {
+	if !isError {
+		atomic.AddInt64(&c.warnings, 1)
+		return
+	}
+	atomic.AddInt64(&c.errors, 1)
+	c.mux.Lock()
+	if c.firstError == nil {
+		c.firstError = fmt.Errorf("validation error %d on layer %s: %s",
+			messageCode, layerPrefix, message)
+	}
+	c.mux.Unlock()
+}
+
+// ErrorCount returns the number of validation errors counted so far.
+func (c *ValidationCounters) ErrorCount() int {
+	return int(atomic.LoadInt64(&c.errors))
+}
+
+// WarningCount returns the number of validation warnings counted so far.
+func (c *ValidationCounters) WarningCount() int {
+	return int(atomic.LoadInt64(&c.warnings))
+}
+
+// FirstError returns the first validation error counted, if any.
+func (c *ValidationCounters) FirstError() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.firstError
+}
+
+// Reset zeroes the counters and forgets the first error.
+func (c *ValidationCounters) Reset() {
+	c.mux.Lock()
+	atomic.StoreInt64(&c.errors, 0)
+	atomic.StoreInt64(&c.warnings, 0)
+	c.firstError = nil
+	c.mux.Unlock()
+}
+
+// ValidationErrorCount returns the number of validation errors reported so far,
+// messages are only reported when enableDebug is set.
+func ValidationErrorCount() int {
+	return validationCounters.ErrorCount()
+}
+
+// ValidationWarningCount returns the number of validation warnings reported so far.
+func ValidationWarningCount() int {
+	return validationCounters.WarningCount()
+}
+
+// CheckValidation returns the first validation error reported, if any. Call it after
+// rendering a frame to fail a test on validation errors, a panic within the callback
+// would have to unwind through the driver.
+func CheckValidation() error {
+	return validationCounters.FirstError()
+}
+
+// ResetValidationCounters zeroes the counters and forgets the first error.
+func ResetValidationCounters() {
+	validationCounters.Reset()
+}
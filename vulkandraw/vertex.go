@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// formatSize returns the size in bytes of a vertex attribute format or of a pixel
+// of the common color attachment formats.
+func formatSize(format vk.Format) (uint32, bool) {
+	switch format {
+	case vk.FormatR32Sfloat, vk.FormatR32Uint, vk.FormatR32Sint:
+		return 4, true
+	case vk.FormatR32g32Sfloat, vk.FormatR32g32Uint, vk.FormatR32g32Sint:
+		return 8, true
+	case vk.FormatR32g32b32Sfloat, vk.FormatR32g32b32Uint, vk.FormatR32g32b32Sint:
+		return 12, true
+	case vk.FormatR32g32b32a32Sfloat, vk.FormatR32g32b32a32Uint, vk.FormatR32g32b32a32Sint:
+		return 16, true
+	case vk.FormatR8g8b8a8Unorm, vk.FormatR8g8b8a8Snorm,
+		vk.FormatR8g8b8a8Uint, vk.FormatR8g8b8a8Sint:
+		return 4, true
+	case vk.FormatR8g8b8a8Srgb, vk.FormatB8g8r8a8Unorm, vk.FormatB8g8r8a8Srgb,
+		vk.FormatA2b10g10r10UnormPack32, vk.FormatA2r10g10b10UnormPack32:
+		return 4, true
+	case vk.FormatR16g16Sfloat, vk.FormatR16g16Unorm, vk.FormatR16g16Snorm:
+		return 4, true
+	case vk.FormatR16g16b16a16Sfloat, vk.FormatR16g16b16a16Unorm, vk.FormatR16g16b16a16Snorm:
+		return 8, true
+	default:
+		return 0, false
+	}
+}
+
+// validateVertexInput checks that every attribute fits within the stride of its binding
+// and that attributes of the same binding don't overlap.
+func validateVertexInput(bindings []vk.VertexInputBindingDescription,
+	attributes []vk.VertexInputAttributeDescription) error {
+
+	strides := make(map[uint32]uint32, len(bindings))
+	for _, b := range bindings {
+		if _, ok := strides[b.Binding]; ok {
+			return fmt.Errorf("vertex binding %d is described twice", b.Binding)
+		}
+		strides[b.Binding] = b.Stride
+	}
+	locations := make(map[uint32]bool, len(attributes))
+	for i, a := range attributes {
+		stride, ok := strides[a.Binding]
+		if !ok {
+			return fmt.Errorf("vertex attribute at location %d refers to missing binding %d",
+				a.Location, a.Binding)
+		}
+		if locations[a.Location] {
+			return fmt.Errorf("vertex attribute location %d is used twice", a.Location)
+		}
+		locations[a.Location] = true
+		size, ok := formatSize(a.Format)
+		if !ok {
+			return fmt.Errorf("vertex attribute at location %d has unknown format %d",
+				a.Location, a.Format)
+		}
+		if a.Offset+size > stride {
+			return fmt.Errorf("vertex attribute at location %d (offset %d, size %d) exceeds stride %d of binding %d",
+				a.Location, a.Offset, size, stride, a.Binding)
+		}
+		for _, other := range attributes[:i] {
+			if other.Binding != a.Binding {
+				continue
+			}
+			otherSize, _ := formatSize(other.Format)
+			if a.Offset < other.Offset+otherSize && other.Offset < a.Offset+size {
+				return fmt.Errorf("vertex attributes at locations %d and %d overlap in binding %d",
+					other.Location, a.Location, a.Binding)
+			}
+		}
+	}
+	return nil
+}
+
+// VertexLayout describes the vertex buffer bindings and the attributes read from them.
+type VertexLayout struct {
+	Bindings   []vk.VertexInputBindingDescription
+	Attributes []vk.VertexInputAttributeDescription
+	// Points draws each vertex as a point instead of triangle lists,
+	// the vertex shader must write gl_PointSize.
+	Points bool
+}
+
+// PositionLayout is the layout of CreateBuffers, positions in a single binding.
+func PositionLayout() VertexLayout {
+	return VertexLayout{
+		Bindings: []vk.VertexInputBindingDescription{{
+			Binding:   0,
+			Stride:    3 * 4, // 4 = sizeof(float32)
+			InputRate: vk.VertexInputRateVertex,
+		}},
+		Attributes: []vk.VertexInputAttributeDescription{{
+			Binding:  0,
+			Location: 0,
+			Format:   vk.FormatR32g32b32Sfloat,
+		}},
+	}
+}
+
+// SeparateLayout is the layout of CreateBuffersSeparate, positions, normals and uvs
+// come from their own bindings 0, 1 and 2 at locations 0, 1 and 2.
+func SeparateLayout() VertexLayout {
+	formats := []vk.Format{
+		vk.FormatR32g32b32Sfloat, // position
+		vk.FormatR32g32b32Sfloat, // normal
+		vk.FormatR32g32Sfloat,    // uv
+	}
+	var layout VertexLayout
+	for i, format := range formats {
+		size, _ := formatSize(format)
+		layout.Bindings = append(layout.Bindings, vk.VertexInputBindingDescription{
+			Binding:   uint32(i),
+			Stride:    size,
+			InputRate: vk.VertexInputRateVertex,
+		})
+		layout.Attributes = append(layout.Attributes, vk.VertexInputAttributeDescription{
+			Binding:  uint32(i),
+			Location: uint32(i),
+			Format:   format,
+		})
+	}
+	return layout
+}
+
+// InterleavedLayout packs attributes of the formats one after another in a single binding,
+// at locations 0, 1, 2... in order, e.g. R32G32B32_SFLOAT positions followed by
+// R8G8B8A8_UNORM colors and R16G16_SFLOAT uvs for a 20 bytes stride.
+func InterleavedLayout(formats ...vk.Format) (VertexLayout, error) {
+	var layout VertexLayout
+	var offset uint32
+	for i, format := range formats {
+		size, ok := formatSize(format)
+		if !ok {
+			err := fmt.Errorf("InterleavedLayout: unknown size of format %d", format)
+			return layout, err
+		}
+		layout.Attributes = append(layout.Attributes, vk.VertexInputAttributeDescription{
+			Binding:  0,
+			Location: uint32(i),
+			Format:   format,
+			Offset:   offset,
+		})
+		offset += size
+	}
+	layout.Bindings = []vk.VertexInputBindingDescription{{
+		Binding:   0,
+		Stride:    offset,
+		InputRate: vk.VertexInputRateVertex,
+	}}
+	return layout, nil
+}
+
+// Vertex is an interleaved vertex as the shaders read it, with the position at
+// location 0, the normal at 1, the uv at 2 and the color at 3. Its fields are all
+// float32, so there's no padding and a []Vertex can be copied into a buffer as is.
+type Vertex struct {
+	Position [3]float32
+	Normal   [3]float32
+	UV       [2]float32
+	Color    [4]float32
+}
+
+// BindingDescription describes the binding the vertices are read from, one per vertex.
+func (Vertex) BindingDescription(binding uint32) vk.VertexInputBindingDescription {
+	return vk.VertexInputBindingDescription{
+		Binding:   binding,
+		Stride:    uint32(unsafe.Sizeof(Vertex{})),
+		InputRate: vk.VertexInputRateVertex,
+	}
+}
+
+// AttributeDescriptions describes the fields of the vertex read from the binding.
+func (Vertex) AttributeDescriptions(binding uint32) []vk.VertexInputAttributeDescription {
+	var v Vertex
+	return []vk.VertexInputAttributeDescription{{
+		Binding:  binding,
+		Location: 0,
+		Format:   vk.FormatR32g32b32Sfloat,
+		Offset:   uint32(unsafe.Offsetof(v.Position)),
+	}, {
+		Binding:  binding,
+		Location: 1,
+		Format:   vk.FormatR32g32b32Sfloat,
+		Offset:   uint32(unsafe.Offsetof(v.Normal)),
+	}, {
+		Binding:  binding,
+		Location: 2,
+		Format:   vk.FormatR32g32Sfloat,
+		Offset:   uint32(unsafe.Offsetof(v.UV)),
+	}, {
+		Binding:  binding,
+		Location: 3,
+		Format:   vk.FormatR32g32b32a32Sfloat,
+		Offset:   uint32(unsafe.Offsetof(v.Color)),
+	}}
+}
+
+// VertexLayout is the layout to create the graphics pipeline with for vertices in binding 0.
+func (v Vertex) VertexLayout() VertexLayout {
+	return VertexLayout{
+		Bindings:   []vk.VertexInputBindingDescription{v.BindingDescription(0)},
+		Attributes: v.AttributeDescriptions(0),
+	}
+}
+
+// vertexBytes returns the memory of the vertices, as laid out by Vertex.VertexLayout.
+func vertexBytes(vertices []Vertex) []byte {
+	if len(vertices) == 0 {
+		return nil
+	}
+	size := len(vertices) * int(unsafe.Sizeof(Vertex{}))
+	return unsafe.Slice((*byte)(unsafe.Pointer(&vertices[0])), size)
+}
+
+// ValidateVertexLayout checks that the GPU can read every attribute format of the layout
+// from vertex buffers, not every packed format is mandatory, e.g. R8G8B8A8_UNORM colors
+// or R16G16_SFLOAT uvs. The error lists all the unsupported attributes.
+func (v *VulkanDeviceInfo) ValidateVertexLayout(layout VertexLayout) error {
+	var unsupported []string
+	for _, a := range layout.Attributes {
+		if !v.vertexFormatSupported(a.Format) {
+			unsupported = append(unsupported,
+				fmt.Sprintf("location %d (format %d)", a.Location, a.Format))
+		}
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("ValidateVertexLayout: GPU can't read vertex attributes of %s from vertex buffers",
+			strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// vertexFormatSupported reports whether the format has the vertex buffer feature.
+func (v *VulkanDeviceInfo) vertexFormatSupported(format vk.Format) bool {
+	var props vk.FormatProperties
+	vk.GetPhysicalDeviceFormatProperties(v.gpu, format, &props)
+	props.Deref()
+	return props.BufferFeatures&vk.FormatFeatureFlags(vk.FormatFeatureVertexBufferBit) != 0
+}
+
+// InterleavedLayout is InterleavedLayout checked against the GPU, packed formats
+// are only accepted when it can read them from vertex buffers, see ValidateVertexLayout.
+func (v *VulkanDeviceInfo) InterleavedLayout(formats ...vk.Format) (VertexLayout, error) {
+	layout, err := InterleavedLayout(formats...)
+	if err != nil {
+		return layout, err
+	}
+	if err := v.ValidateVertexLayout(layout); err != nil {
+		return VertexLayout{}, err
+	}
+	return layout, nil
+}
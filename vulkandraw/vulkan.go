@@ -6,7 +6,6 @@ import (
 	"unsafe"
 
 	vk "github.com/vulkan-go/vulkan"
-	"github.com/xlab/android-go/android"
 )
 
 // enableDebug is disabled by default since VK_EXT_debug_report
@@ -16,27 +15,117 @@ import (
 // on fw 1.2.0 it works fine.
 const enableDebug = false
 
+// validateVertexLayout enables the CPU-side check of vertex bindings and attributes
+// before the pipeline gets created, it catches stride and offset mistakes
+// that otherwise result in garbage geometry without any error.
+const validateVertexLayout = true
+
+// traceFrames logs the frame in flight, the fence and semaphores, the acquired image
+// and the present result of every frame, to debug present ordering and stalls.
+// It's very verbose, so it's off by default.
+const traceFrames = false
+
+// instanceCreateEnumeratePortabilityBit is VK_INSTANCE_CREATE_ENUMERATE_PORTABILITY_BIT_KHR,
+// it lets portability drivers such as MoltenVK be enumerated.
+const instanceCreateEnumeratePortabilityBit vk.InstanceCreateFlags = 0x00000001
+
+// maxFramesInFlight is the number of frames the CPU may record and submit
+// ahead of the GPU, each one has its own fence and semaphores.
+const maxFramesInFlight = 2
+
 type VulkanDeviceInfo struct {
 	gpuDevices []vk.PhysicalDevice
-
-	dbg      vk.DebugReportCallback
-	instance vk.Instance
-	surface  vk.Surface
-	queue    vk.Queue
-	device   vk.Device
+	gpu        vk.PhysicalDevice
+
+	dbg         vk.DebugReportCallback
+	dbgUserData *debugUserData // see VulkanDeviceOptions.DebugUserData
+	instance    vk.Instance
+	surface     vk.Surface
+	device      vk.Device
+
+	graphicsQueue vk.Queue
+	presentQueue  vk.Queue // same as graphicsQueue when the family can present
+
+	hasColorspaceExt bool           // VK_EXT_swapchain_colorspace is enabled
+	setHDRMetadata   unsafe.Pointer // vkSetHdrMetadataEXT, nil without VK_EXT_hdr_metadata
+	getFeatures2     unsafe.Pointer // vkGetPhysicalDeviceFeatures2KHR, see features2.go
+	headless         bool           // can't present, compute and offscreen work only
+
+	queues        map[QueuePurpose]vk.Queue
+	queueFamilies map[QueuePurpose]uint32
+
+	enabledFeatures vk.PhysicalDeviceFeatures
+	features2       *FeatureChain // chained to the device create info, see features2.go
+	surfaceInfo     *SurfaceInfo  // cached queries of the surface, see surface.go
+	caps            DeviceCapabilities
+	bufferSharing   BufferSharing // of the buffers created, see SetBufferSharing
+	samplers        []vk.Sampler  // destroyed with the device, see NewSampler
+	fences          *FencePool    // of one time submissions, see endSingleTimeCommands
 }
 
 type VulkanSwapchainInfo struct {
 	device vk.Device
+	gpu    vk.PhysicalDevice
 
+	// swapchains are presented together in every frame, see AddSwapchain. The images,
+	// views, framebuffers and attachments of all of them are kept in single slices,
+	// those of swapchain k start at imageBase(k).
 	swapchains   []vk.Swapchain
 	swapchainLen []uint32
 
-	displaySize   vk.Extent2D
-	displayFormat vk.Format
+	displaySize       vk.Extent2D
+	displayFormat     vk.Format
+	displayColorSpace vk.ColorSpace
+	imageUsage        vk.ImageUsageFlags // the usage swapchain images were created with
+	compositeAlpha    vk.CompositeAlphaFlagBits
+
+	framebuffers  []vk.Framebuffer
+	displayImages []vk.Image
+	displayViews  []vk.ImageView
+
+	// extra color attachments of each framebuffer
+	attachments [][]VulkanAttachmentImage
+}
 
-	framebuffers []vk.Framebuffer
-	displayViews []vk.ImageView
+// VulkanSwapchainOptions configures CreateSwapchain.
+type VulkanSwapchainOptions struct {
+	// ImageUsage is added to vk.ImageUsageColorAttachmentBit, e.g. vk.ImageUsageTransferDstBit
+	// is required to blit an offscreen target onto the swapchain images.
+	ImageUsage vk.ImageUsageFlagBits
+	// OptionalImageUsage is added only as far as the surface supports it,
+	// e.g. vk.ImageUsageTransferSrcBit for screenshots.
+	OptionalImageUsage vk.ImageUsageFlagBits
+	// SurfaceFormats lists the format and color space pairs to pick from, in order
+	// of preference. SDRSurfaceFormats is used when empty, see HDRSurfaceFormats.
+	SurfaceFormats []vk.SurfaceFormat
+	// DesiredFormat forces the format of the images, e.g. to match the colors of
+	// another app exactly, SurfaceFormats is ignored then. CreateSwapchain fails when
+	// the surface doesn't support it. It's unset with vk.FormatUndefined.
+	DesiredFormat vk.Format
+	// CompositeAlpha is how the compositor blends the images with what's behind the surface,
+	// e.g. vk.CompositeAlphaPreMultipliedBit for an AR overlay over the camera feed.
+	// The alpha of the clear color and of the rendering matters then, see SetClearColor.
+	// CreateSwapchain fails when the surface doesn't support it, opaque is used when 0.
+	CompositeAlpha vk.CompositeAlphaFlagBits
+	// OldSwapchain is the swapchain being replaced, if any. The driver may reuse its
+	// resources and keep presenting its images during the transition.
+	OldSwapchain vk.Swapchain
+	// Surface is the surface to create the swapchain for, e.g. of a second window,
+	// the surface of the device is used when it's vk.NullHandle.
+	Surface vk.Surface
+	// ImageCount is the number of images desired, e.g. 3 for triple buffering which lets
+	// the GPU render the next frame while one image waits to be presented, at the cost of
+	// a frame of latency. It's clamped to the range of the surface, MinImageCount when 0.
+	ImageCount uint32
+	// Extent is the size of the images when the surface lets the app pick it, e.g. with
+	// some desktop window managers, clamped to the extents the surface supports. It's
+	// ignored when the surface dictates its current extent, as on Android.
+	Extent vk.Extent2D
+	// LatencyMode requests exactly the MinImageCount of the surface, ImageCount is ignored,
+	// and presents in mailbox mode when the surface supports it, FIFO otherwise. Frames then
+	// reach the display as soon as possible, but the GPU stalls more often waiting for an
+	// image, so throughput drops, unlike with triple buffering.
+	LatencyMode bool
 }
 
 func (v *VulkanSwapchainInfo) DefaultSwapchain() vk.Swapchain {
@@ -47,21 +136,124 @@ func (v *VulkanSwapchainInfo) DefaultSwapchainLen() uint32 {
 	return v.swapchainLen[0]
 }
 
+// ImageCount is the number of images of all swapchains, thus of framebuffers
+// and command buffers.
+func (v *VulkanSwapchainInfo) ImageCount() uint32 {
+	var n uint32
+	for _, l := range v.swapchainLen {
+		n += l
+	}
+	return n
+}
+
+// checkImageCount verifies that there are n images and that the views
+// and framebuffers match them, n being the number of command buffers.
+func (v *VulkanSwapchainInfo) checkImageCount(n uint32) error {
+	count := v.ImageCount()
+	switch {
+	case n != count:
+		return fmt.Errorf("%d command buffers for %d swapchain images", n, count)
+	case len(v.displayImages) != int(count):
+		return fmt.Errorf("%d images retrieved for %d swapchain images", len(v.displayImages), count)
+	case len(v.displayViews) != int(count):
+		return fmt.Errorf("%d image views for %d swapchain images", len(v.displayViews), count)
+	case len(v.framebuffers) != int(count):
+		return fmt.Errorf("%d framebuffers for %d swapchain images", len(v.framebuffers), count)
+	}
+	return nil
+}
+
+// imageBase is the index of the first image of swapchain k within the image slices.
+func (v *VulkanSwapchainInfo) imageBase(k int) uint32 {
+	var base uint32
+	for _, l := range v.swapchainLen[:k] {
+		base += l
+	}
+	return base
+}
+
+// AddSwapchain takes over the swapchain of other, created for another surface
+// with CreateSwapchain, so both get presented in every frame. The swapchains share the
+// render pass and pipeline, thus the format and extent must match. Framebuffers must be
+// created once all swapchains are added.
+func (v *VulkanSwapchainInfo) AddSwapchain(other *VulkanSwapchainInfo) error {
+	if len(other.swapchains) != 1 {
+		err := fmt.Errorf("AddSwapchain: expected a single swapchain, got %d", len(other.swapchains))
+		return err
+	}
+	if v.framebuffers != nil {
+		err := fmt.Errorf("AddSwapchain: framebuffers are already created")
+		return err
+	}
+	if other.displayFormat != v.displayFormat ||
+		other.displaySize.Width != v.displaySize.Width ||
+		other.displaySize.Height != v.displaySize.Height {
+		err := fmt.Errorf("AddSwapchain: swapchain %dx%d in format %d doesn't match %dx%d in format %d",
+			other.displaySize.Width, other.displaySize.Height, other.displayFormat,
+			v.displaySize.Width, v.displaySize.Height, v.displayFormat)
+		return err
+	}
+	v.swapchains = append(v.swapchains, other.swapchains[0])
+	v.swapchainLen = append(v.swapchainLen, other.swapchainLen[0])
+	other.swapchains = nil
+	other.swapchainLen = nil
+	return nil
+}
+
 type VulkanBufferInfo struct {
 	device        vk.Device
 	vertexBuffers []vk.Buffer
+	// memory of CreateBuffersSeparate, one allocation per stream
+	vertexMems []vk.DeviceMemory
+	layout     VertexLayout
+	// the vertices of non-indexed draws and the parts drawn, see SetDrawRanges
+	vertexCount uint32
+	ranges      []DrawRange
+
+	// indices of vk.CmdDrawIndexed, see quad.go
+	indexBuffer vk.Buffer
+	indexMem    vk.DeviceMemory
+	indexCount  uint32
+	indexType   vk.IndexType
+
+	// draw commands of vk.CmdDrawIndirect, see indirect.go
+	indirectBuffer vk.Buffer
+	indirectMem    vk.DeviceMemory
+	indirectCount  uint32
+	indirectStride uint32
 }
 
 func (v *VulkanBufferInfo) DefaultVertexBuffer() vk.Buffer {
 	return v.vertexBuffers[0]
 }
 
+// VertexLayout is the layout to create the graphics pipeline with, matching the buffers.
+func (v *VulkanBufferInfo) VertexLayout() VertexLayout {
+	return v.layout
+}
+
 type VulkanGfxPipelineInfo struct {
 	device vk.Device
 
 	layout   vk.PipelineLayout
 	cache    vk.PipelineCache
 	pipeline vk.Pipeline
+
+	// ownsLayout is false when the layout was passed in and is shared with other pipelines
+	ownsLayout bool
+
+	// the states to rebuild the pipeline with, see RecreatePipeline
+	colorAttachments int
+	depth            DepthState
+	multisample      MultisampleState
+	vertex           VertexLayout
+	shaders          []ShaderStage
+}
+
+// Layout returns the pipeline layout, it can be passed to CreateGraphicsPipeline
+// to create pipelines compatible with the same descriptor sets and push constants.
+func (gfx *VulkanGfxPipelineInfo) Layout() vk.PipelineLayout {
+	return gfx.layout
 }
 
 type VulkanRenderInfo struct {
@@ -70,8 +262,41 @@ type VulkanRenderInfo struct {
 	renderPass vk.RenderPass
 	cmdPool    vk.CommandPool
 	cmdBuffers []vk.CommandBuffer
-	semaphores []vk.Semaphore
-	fences     []vk.Fence
+	uploadPool vk.CommandPool // transient, for one time uploads, see commands.go
+	semaphores []vk.Semaphore // signaled when a swapchain image is acquired, per frame and swapchain
+	fences     []vk.Fence     // signaled when a frame's submission completes
+
+	renderSemaphores []vk.Semaphore // signaled when a frame is ready to present
+	imagesInFlight   []vk.Fence     // the fence of the last frame using each swapchain image
+	frameIdx         int
+
+	synchronous bool                       // see UseSynchronousMode
+	cmdUsage    vk.CommandBufferUsageFlags // see SetCommandBufferUsage
+	clear       []float32                  // see SetClearColor
+	staticScene bool                       // see UseStaticScene
+	outdated    []bool                     // command buffers recorded before the scene last changed
+
+	colorAttachments int       // number of color attachments of the render pass
+	attachmentCount  int       // number of attachments of the render pass, framebuffers must match
+	depthFormat      vk.Format // of the depth attachment after the color ones, if any
+
+	overlay     OverlayFunc
+	offscreen   *VulkanOffscreenInfo
+	post        *VulkanPostInfo
+	recordFrame func(i int)
+	prePasses   [][]vk.CommandBuffer // per pass and frame in flight, see AddPrePass
+
+	secondary     *VulkanSecondaryInfo // see UseSecondaryBuffers
+	recordDynamic func(i int)          // recordFrame keeping the static secondary buffer
+
+	stats        *VulkanStatsInfo
+	timestamps   *VulkanTimestampsInfo
+	lastImageIdx int // the swapchain image of the last frame submitted
+
+	swapchainResults []vk.Result // of the last frame, per swapchain, see StaleSwapchains
+
+	frameCount uint64             // frames submitted so far
+	retired    []retiredSwapchain // see RecreateSwapchainDeferred and retire
 }
 
 func (v *VulkanRenderInfo) DefaultFence() vk.Fence {
@@ -83,118 +308,486 @@ func (v *VulkanRenderInfo) DefaultSemaphore() vk.Semaphore {
 }
 
 func VulkanInit(v *VulkanDeviceInfo, s *VulkanSwapchainInfo,
-	r *VulkanRenderInfo, b *VulkanBufferInfo, gfx *VulkanGfxPipelineInfo) {
+	r *VulkanRenderInfo, b *VulkanBufferInfo, gfx *VulkanGfxPipelineInfo) error {
 
-	clearValues := []vk.ClearValue{
-		vk.NewClearValue([]float32{0.098, 0.71, 0.996, 1}),
+	// everything per image is indexed by the same image index
+	if err := s.checkImageCount(uint32(len(r.cmdBuffers))); err != nil {
+		err = fmt.Errorf("VulkanInit: %s", err)
+		return err
+	}
+	r.recordFrame = func(i int) {
+		if r.secondary != nil {
+			r.secondary.staticOutdated[i] = true
+		}
+		recordCommandBuffer(i, s, r, b, gfx)
+	}
+	r.recordDynamic = func(i int) {
+		recordCommandBuffer(i, s, r, b, gfx)
 	}
 	for i := range r.cmdBuffers {
-		cmdBufferBeginInfo := vk.CommandBufferBeginInfo{
-			SType: vk.StructureTypeCommandBufferBeginInfo,
-		}
-		renderPassBeginInfo := vk.RenderPassBeginInfo{
-			SType:       vk.StructureTypeRenderPassBeginInfo,
-			RenderPass:  r.renderPass,
-			Framebuffer: s.framebuffers[i],
-			RenderArea: vk.Rect2D{
-				Offset: vk.Offset2D{
-					X: 0, Y: 0,
-				},
-				Extent: s.displaySize,
-			},
-			ClearValueCount: 1,
-			PClearValues:    clearValues,
+		r.recordFrame(i)
+	}
+	fenceCreateInfo := vk.FenceCreateInfo{
+		SType: vk.StructureTypeFenceCreateInfo,
+		// created signaled, so the first wait for each frame doesn't block
+		Flags: vk.FenceCreateFlags(vk.FenceCreateSignaledBit),
+	}
+	semaphoreCreateInfo := vk.SemaphoreCreateInfo{
+		SType: vk.StructureTypeSemaphoreCreateInfo,
+	}
+	r.fences = make([]vk.Fence, maxFramesInFlight)
+	// an acquire semaphore per swapchain in each frame
+	r.semaphores = make([]vk.Semaphore, maxFramesInFlight*len(s.swapchains))
+	r.renderSemaphores = make([]vk.Semaphore, maxFramesInFlight)
+	for i := 0; i < maxFramesInFlight; i++ {
+		ret := vk.CreateFence(v.device, &fenceCreateInfo, nil, &r.fences[i])
+		check(ret, "vk.CreateFence")
+		ret = vk.CreateSemaphore(v.device, &semaphoreCreateInfo, nil, &r.renderSemaphores[i])
+		check(ret, "vk.CreateSemaphore")
+	}
+	for i := range r.semaphores {
+		ret := vk.CreateSemaphore(v.device, &semaphoreCreateInfo, nil, &r.semaphores[i])
+		check(ret, "vk.CreateSemaphore")
+	}
+	r.imagesInFlight = make([]vk.Fence, len(r.cmdBuffers))
+	r.outdated = make([]bool, len(r.cmdBuffers))
+	r.lastImageIdx = -1
+	return nil
+}
+
+// waitImageInFlight blocks until the previous frame rendered into the swapchain image
+// completes, then marks the image as used by the current frame. An image may be acquired
+// while the frame that rendered into it is still in flight, its command buffer must not
+// be re-recorded nor resubmitted until then.
+func (r *VulkanRenderInfo) waitImageInFlight(imageIdx uint32, timeout uint64) vk.Result {
+	if fence := r.imagesInFlight[imageIdx]; fence != vk.NullHandle {
+		ret := vk.WaitForFences(r.device, 1, []vk.Fence{fence}, vk.True, timeout)
+		if ret != vk.Success {
+			return ret
 		}
-		ret := vk.BeginCommandBuffer(r.cmdBuffers[i], &cmdBufferBeginInfo)
-		check(ret, "vk.BeginCommandBuffer")
+	}
+	r.imagesInFlight[imageIdx] = r.fences[r.frameIdx]
+	return vk.Success
+}
+
+func recordCommandBuffer(i int, s *VulkanSwapchainInfo,
+	r *VulkanRenderInfo, b *VulkanBufferInfo, gfx *VulkanGfxPipelineInfo) {
 
+	clearValues := make([]vk.ClearValue, r.attachmentCount)
+	clearValues[0] = vk.NewClearValue(r.clearColor())
+	for j := 1; j < r.colorAttachments; j++ {
+		clearValues[j] = vk.NewClearValue([]float32{0, 0, 0, 0})
+	}
+	if r.depthFormat != vk.FormatUndefined {
+		clearValues[r.colorAttachments] = vk.NewClearDepthStencil(1, 0)
+	}
+	cmdBufferBeginInfo := vk.CommandBufferBeginInfo{
+		SType: vk.StructureTypeCommandBufferBeginInfo,
+		Flags: r.commandBufferUsage(),
+	}
+	renderPassBeginInfo := vk.RenderPassBeginInfo{
+		SType:       vk.StructureTypeRenderPassBeginInfo,
+		RenderPass:  r.renderPass,
+		Framebuffer: s.framebuffers[i],
+		RenderArea: vk.Rect2D{
+			Offset: vk.Offset2D{
+				X: 0, Y: 0,
+			},
+			Extent: s.displaySize,
+		},
+		ClearValueCount: uint32(len(clearValues)),
+		PClearValues:    clearValues,
+	}
+	finalPassBeginInfo := renderPassBeginInfo
+	switch {
+	case r.post != nil:
+		// draw into the post target, it gets sampled by the post pass
+		renderPassBeginInfo.RenderPass = r.post.target.renderPass
+		renderPassBeginInfo.Framebuffer = r.post.target.framebuffer
+		renderPassBeginInfo.RenderArea.Extent = r.post.target.extent
+	case r.offscreen != nil:
+		// draw into the offscreen target, it gets blitted after the render pass
+		renderPassBeginInfo.RenderPass = r.offscreen.renderPass
+		renderPassBeginInfo.Framebuffer = r.offscreen.framebuffer
+		renderPassBeginInfo.RenderArea.Extent = r.offscreen.extent
+	}
+	ret := vk.BeginCommandBuffer(r.cmdBuffers[i], &cmdBufferBeginInfo)
+	check(ret, "vk.BeginCommandBuffer")
+	// with secondary command buffers the query must be active before the render pass
+	// begins, the inline draw queries only the scene draw
+	stats := r.stats != nil && (r.secondary == nil || r.stats.inherited)
+	if stats {
+		// queries must be reset outside of a render pass
+		vk.CmdResetQueryPool(r.cmdBuffers[i], r.stats.pool, uint32(i), 1)
+	}
+	if r.timestamps != nil {
+		r.timestamps.cmdBegin(r.cmdBuffers[i], i)
+	}
+
+	BeginLabel(r.cmdBuffers[i], "Triangle Pass", [4]float32{0.812, 0, 0.059, 1})
+	if r.secondary != nil {
+		if stats {
+			vk.CmdBeginQuery(r.cmdBuffers[i], r.stats.pool, uint32(i), 0)
+		}
+		// the scene and the dynamic draws come from secondary command buffers
+		vk.CmdBeginRenderPass(r.cmdBuffers[i], &renderPassBeginInfo,
+			vk.SubpassContentsSecondaryCommandBuffers)
+		r.secondary.cmdExecute(r.cmdBuffers[i], i, OverlayFrame{
+			Extent:     renderPassBeginInfo.RenderArea.Extent,
+			ImageIndex: i,
+			RenderPass: renderPassBeginInfo.RenderPass,
+		}, renderPassBeginInfo.Framebuffer, r, b, gfx)
+	} else {
 		vk.CmdBeginRenderPass(r.cmdBuffers[i], &renderPassBeginInfo, vk.SubpassContentsInline)
 		vk.CmdBindPipeline(r.cmdBuffers[i], vk.PipelineBindPointGraphics, gfx.pipeline)
 		offsets := make([]vk.DeviceSize, len(b.vertexBuffers))
-		vk.CmdBindVertexBuffers(r.cmdBuffers[i], 0, 1, b.vertexBuffers, offsets)
-		vk.CmdDraw(r.cmdBuffers[i], 3, 1, 0, 0)
+		vk.CmdBindVertexBuffers(r.cmdBuffers[i], 0, uint32(len(b.vertexBuffers)), b.vertexBuffers, offsets)
+		if stats {
+			vk.CmdBeginQuery(r.cmdBuffers[i], r.stats.pool, uint32(i), 0)
+		}
+		b.cmdDraw(r.cmdBuffers[i])
+		if stats {
+			vk.CmdEndQuery(r.cmdBuffers[i], r.stats.pool, uint32(i))
+		}
+	}
+	if r.post != nil {
+		// the scene pass transitions the target to vk.ImageLayoutShaderReadOnlyOptimal
+		// as it ends, the overlay goes on top of the post-processed image
 		vk.CmdEndRenderPass(r.cmdBuffers[i])
+		if stats && r.secondary != nil {
+			vk.CmdEndQuery(r.cmdBuffers[i], r.stats.pool, uint32(i))
+		}
+		EndLabel(r.cmdBuffers[i])
+		BeginLabel(r.cmdBuffers[i], "Post Pass", [4]float32{0.098, 0.71, 0.996, 1})
+		renderPassBeginInfo = finalPassBeginInfo
+		vk.CmdBeginRenderPass(r.cmdBuffers[i], &renderPassBeginInfo, vk.SubpassContentsInline)
+		r.post.cmdDraw(r.cmdBuffers[i])
+	}
+	if r.overlay != nil && (r.secondary == nil || r.post != nil) {
+		r.overlay(OverlayFrame{
+			Cmd:        r.cmdBuffers[i],
+			Extent:     renderPassBeginInfo.RenderArea.Extent,
+			ImageIndex: i,
+			RenderPass: renderPassBeginInfo.RenderPass,
+		})
+	}
+	vk.CmdEndRenderPass(r.cmdBuffers[i])
+	if stats && r.secondary != nil && r.post == nil {
+		vk.CmdEndQuery(r.cmdBuffers[i], r.stats.pool, uint32(i))
+	}
+	EndLabel(r.cmdBuffers[i])
+	if r.timestamps != nil {
+		r.timestamps.cmdEnd(r.cmdBuffers[i], i)
+	}
+	if r.post == nil && r.offscreen != nil {
+		BeginLabel(r.cmdBuffers[i], "Blit", [4]float32{0.5, 0.5, 0.5, 1})
+		r.offscreen.cmdBlitToSwapchain(r.cmdBuffers[i], s.displayImages[i], s.displaySize)
+		EndLabel(r.cmdBuffers[i])
+	}
+
+	ret = vk.EndCommandBuffer(r.cmdBuffers[i])
+	check(ret, "vk.EndCommandBuffer")
+}
+
+// DrawResult tells the caller of VulkanDrawFrame how the frame went.
+type DrawResult int
+
+const (
+	// DrawSuccess means the frame got presented.
+	DrawSuccess DrawResult = iota
+	// DrawNeedsRecreate means a swapchain is out of date, see StaleSwapchains,
+	// it must be recreated before drawing again.
+	DrawNeedsRecreate
+	// DrawSkipped means the frame was dropped over a transient issue, e.g. a timeout,
+	// the next one may go through.
+	DrawSkipped
+	// DrawSurfaceLost means the surface went away, the error is a *SurfaceLostError.
+	// The surface and the swapchain must be recreated, see RecreateSurface.
+	DrawSurfaceLost
+	// DrawFatal means the device is no longer usable, e.g. it was lost,
+	// everything must be created again.
+	DrawFatal
+)
 
-		ret = vk.EndCommandBuffer(r.cmdBuffers[i])
-		check(ret, "vk.EndCommandBuffer")
+func (d DrawResult) String() string {
+	switch d {
+	case DrawSuccess:
+		return "success"
+	case DrawNeedsRecreate:
+		return "needs recreate"
+	case DrawSkipped:
+		return "skipped"
+	case DrawSurfaceLost:
+		return "surface lost"
+	case DrawFatal:
+		return "fatal"
+	default:
+		return "unknown"
 	}
-	fenceCreateInfo := vk.FenceCreateInfo{
-		SType: vk.StructureTypeFenceCreateInfo,
+}
+
+// frameError classifies the failure of a call made by VulkanDrawFrame.
+func frameError(name string, ret vk.Result) (DrawResult, error) {
+	switch ret {
+	case vk.Timeout, vk.NotReady:
+		return DrawSkipped, fmt.Errorf("%s timed out", name)
+	case vk.ErrorOutOfDate:
+		return DrawNeedsRecreate, fmt.Errorf("%s failed with %s", name, vk.Error(ret))
+	case vk.ErrorSurfaceLost:
+		return DrawSurfaceLost, &SurfaceLostError{Call: name}
+	default:
+		return DrawFatal, fmt.Errorf("%s failed with %s", name, vk.Error(ret))
 	}
-	semaphoreCreateInfo := vk.SemaphoreCreateInfo{
-		SType: vk.StructureTypeSemaphoreCreateInfo,
+}
+
+// resultString names a result for logs, vk.Error has no name for the successful ones.
+func resultString(ret vk.Result) string {
+	switch ret {
+	case vk.Success:
+		return "success"
+	case vk.Suboptimal:
+		return "suboptimal"
+	}
+	if err := vk.Error(ret); err != nil {
+		return err.Error()
 	}
-	r.fences = make([]vk.Fence, 1)
-	ret := vk.CreateFence(v.device, &fenceCreateInfo, nil, &r.fences[0])
-	check(ret, "vk.CreateFence")
-	r.semaphores = make([]vk.Semaphore, 1)
-	ret = vk.CreateSemaphore(v.device, &semaphoreCreateInfo, nil, &r.semaphores[0])
-	check(ret, "vk.CreateSemaphore")
+	return fmt.Sprintf("result %d", ret)
 }
 
-func VulkanDrawFrame(v VulkanDeviceInfo,
-	s VulkanSwapchainInfo, r VulkanRenderInfo) bool {
-	var nextIdx uint32
+// VulkanDrawFrame renders and presents a frame to every swapchain. The error tells what
+// went wrong unless the result is DrawSuccess. Suboptimal swapchains still get presented
+// to and count as a success, StaleSwapchains reports them.
+func VulkanDrawFrame(v *VulkanDeviceInfo,
+	s *VulkanSwapchainInfo, r *VulkanRenderInfo) (DrawResult, error) {
+	frame := r.frameIdx
+	n := len(s.swapchains)
+
+	// Phase 1: vk.WaitForFences
+	//			wait until the GPU is done with the frame submitted
+	//			maxFramesInFlight frames ago
+
+	const timeoutNano = 10 * 1000 * 1000 * 1000 // 10 sec
+	ret := vk.WaitForFences(v.device, 1, r.fences[frame:], vk.True, timeoutNano)
+	if ret != vk.Success {
+		return frameError("vk.WaitForFences", ret)
+	}
+	r.destroyRetired(false)
 
-	// Phase 1: vk.AcquireNextImage
-	// 			get the framebuffer index we should draw in
+	// Phase 2: vk.AcquireNextImage
+	// 			get the framebuffer index we should draw in for each swapchain
 	//
 	//			N.B. non-infinite timeouts may be not yet implemented
 	//			by your Vulkan driver
 
-	err := vk.Error(vk.AcquireNextImage(v.device, s.DefaultSwapchain(),
-		vk.MaxUint64, r.DefaultSemaphore(), vk.NullHandle, &nextIdx))
+	acquireSemaphores := r.semaphores[frame*n : (frame+1)*n]
+	imageIndices := make([]uint32, n)
+	cmdBuffers := make([]vk.CommandBuffer, 0, len(r.prePasses)+n)
+	for _, pass := range r.prePasses {
+		cmdBuffers = append(cmdBuffers, pass[frame])
+	}
+	waitStages := make([]vk.PipelineStageFlags, n)
+	r.swapchainResults = make([]vk.Result, n)
+	for k := range s.swapchains {
+		ret := vk.AcquireNextImage(v.device, s.swapchains[k],
+			vk.MaxUint64, acquireSemaphores[k], vk.NullHandle, &imageIndices[k])
+		r.swapchainResults[k] = ret
+		if ret != vk.Success && ret != vk.Suboptimal {
+			r.releaseAcquired(v, frame, acquireSemaphores[:k])
+			return frameError(fmt.Sprintf("vk.AcquireNextImage of swapchain %d", k), ret)
+		}
+		if imageIndices[k] >= s.swapchainLen[k] {
+			r.releaseAcquired(v, frame, acquireSemaphores[:k+1])
+			err := fmt.Errorf("vk.AcquireNextImage returned image %d of %d",
+				imageIndices[k], s.swapchainLen[k])
+			return DrawFatal, err
+		}
+		// framebuffers and command buffers of all swapchains are in the same slices
+		idx := s.imageBase(k) + imageIndices[k]
+		if traceFrames {
+			log.Printf("[TRACE] frame %d in flight %d: fence %d, semaphore %d, swapchain %d acquired image %d (%s)",
+				r.frameCount, frame, frame, frame*n+k, k, imageIndices[k], resultString(ret))
+		}
+		if ret := r.waitImageInFlight(idx, timeoutNano); ret != vk.Success {
+			r.releaseAcquired(v, frame, acquireSemaphores[:k+1])
+			return frameError("vk.WaitForFences", ret)
+		}
+		switch {
+		case r.secondary != nil:
+			// the dynamic secondary command buffer gets recorded again,
+			// the frame command buffer executing it must follow
+			r.recordDynamic(int(idx))
+			r.outdated[idx] = false
+		case r.overlay != nil && (!r.staticScene || r.outdated[idx]):
+			// the overlay is dynamic, so the command buffer must be re-recorded
+			r.recordFrame(int(idx))
+			r.outdated[idx] = false
+		case r.outdated[idx]:
+			// the frame that last used it has completed, e.g. after a resize
+			r.recordFrame(int(idx))
+			r.outdated[idx] = false
+		}
+		cmdBuffers = append(cmdBuffers, r.cmdBuffers[idx])
+		waitStages[k] = vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit |
+			vk.PipelineStageTransferBit)
+	}
+
+	// Phase 3: vk.QueueSubmit
+	//			the pre-passes and the swapchain command buffers in one batch
+
+	submitInfo, err := submitInfos([]SubmitBatch{{
+		CommandBuffers:   cmdBuffers,
+		WaitSemaphores:   acquireSemaphores,
+		WaitStages:       waitStages,
+		SignalSemaphores: r.renderSemaphores[frame : frame+1],
+	}})
 	if err != nil {
-		err = fmt.Errorf("vk.AcquireNextImage failed with %s", err)
-		log.Println("[WARN]", err)
-		return false
+		return DrawFatal, err
+	}
+	vk.ResetFences(v.device, 1, r.fences[frame:])
+	BeginQueueLabel(v.graphicsQueue, "Frame", [4]float32{1, 1, 1, 1})
+	ret = vk.QueueSubmit(v.graphicsQueue, uint32(len(submitInfo)), submitInfo, r.fences[frame])
+	EndQueueLabel(v.graphicsQueue)
+	if ret != vk.Success {
+		return frameError("vk.QueueSubmit", ret)
 	}
+	r.frameIdx = (frame + 1) % maxFramesInFlight
+	r.frameCount++
+	r.lastImageIdx = int(imageIndices[0])
 
-	// Phase 2: vk.QueueSubmit
-	//			vk.WaitForFences
+	// Phase 4: vk.QueuePresent
+	//			present all swapchains at once, each reports its own result
 
-	vk.ResetFences(v.device, 1, r.fences)
-	submitInfo := []vk.SubmitInfo{{
-		SType:              vk.StructureTypeSubmitInfo,
+	results := make([]vk.Result, n)
+	presentInfo := vk.PresentInfo{
+		SType:              vk.StructureTypePresentInfo,
 		WaitSemaphoreCount: 1,
-		PWaitSemaphores:    r.semaphores,
-		CommandBufferCount: 1,
-		PCommandBuffers:    r.cmdBuffers[nextIdx:],
-	}}
-	err = vk.Error(vk.QueueSubmit(v.queue, 1, submitInfo, r.DefaultFence()))
-	if err != nil {
-		err = fmt.Errorf("vk.QueueSubmit failed with %s", err)
-		log.Println("[WARN]", err)
-		return false
+		PWaitSemaphores:    r.renderSemaphores[frame:],
+		SwapchainCount:     uint32(n),
+		PSwapchains:        s.swapchains,
+		PImageIndices:      imageIndices,
+		PResults:           results,
+	}
+	ret = vk.QueuePresent(v.presentQueue, &presentInfo)
+	r.swapchainResults = results
+	if traceFrames {
+		for k := range results {
+			log.Printf("[TRACE] frame %d in flight %d: render semaphore %d, swapchain %d presented image %d (%s)",
+				r.frameCount-1, frame, frame, k, imageIndices[k], resultString(results[k]))
+		}
+	}
+	if r.synchronous {
+		vk.QueueWaitIdle(v.graphicsQueue)
+		vk.QueueWaitIdle(v.presentQueue)
 	}
+	// the worst result of all swapchains wins
+	result := DrawSuccess
+	for k, ret := range results {
+		if ret == vk.Success || ret == vk.Suboptimal {
+			continue
+		}
+		res, retErr := frameError(fmt.Sprintf("vk.QueuePresent of swapchain %d", k), ret)
+		if res > result {
+			result, err = res, retErr
+		}
+	}
+	if result == DrawSuccess && ret != vk.Success && ret != vk.Suboptimal {
+		return frameError("vk.QueuePresent", ret)
+	}
+	return result, err
+}
 
-	const timeoutNano = 10 * 1000 * 1000 * 1000 // 10 sec
-	err = vk.Error(vk.WaitForFences(v.device, 1, r.fences, vk.True, timeoutNano))
+// releaseAcquired consumes the semaphores signaled by the swapchains acquired before the
+// frame was given up, so they can be acquired with again. An empty batch waits on them,
+// the fence of the frame covers it, so the next frame using them waits for it too.
+func (r *VulkanRenderInfo) releaseAcquired(v *VulkanDeviceInfo, frame int, semaphores []vk.Semaphore) {
+	if len(semaphores) == 0 {
+		return
+	}
+	waitStages := make([]vk.PipelineStageFlags, len(semaphores))
+	for k := range waitStages {
+		waitStages[k] = vk.PipelineStageFlags(vk.PipelineStageAllCommandsBit)
+	}
+	vk.ResetFences(v.device, 1, r.fences[frame:])
+	err := SubmitBatches(v.graphicsQueue, r.fences[frame], SubmitBatch{
+		WaitSemaphores: semaphores,
+		WaitStages:     waitStages,
+	})
 	if err != nil {
-		err = fmt.Errorf("vk.WaitForFences failed with %s", err)
-		log.Println("[WARN]", err)
-		return false
+		log.Println("[WARN] releasing the acquired swapchain images:", err)
 	}
+}
 
-	// Phase 3: vk.QueuePresent
+// StaleSwapchains returns the indices of the swapchains that were reported out of date
+// or suboptimal by the last VulkanDrawFrame, either when acquiring or presenting.
+// They no longer match the surface and should be recreated.
+func (r *VulkanRenderInfo) StaleSwapchains() []int {
+	var stale []int
+	for k, ret := range r.swapchainResults {
+		if ret == vk.ErrorOutOfDate || ret == vk.Suboptimal {
+			stale = append(stale, k)
+		}
+	}
+	return stale
+}
 
-	imageIndices := []uint32{nextIdx}
-	presentInfo := vk.PresentInfo{
-		SType:          vk.StructureTypePresentInfo,
-		SwapchainCount: 1,
-		PSwapchains:    s.swapchains,
-		PImageIndices:  imageIndices,
+// UseSynchronousMode makes VulkanDrawFrame wait for the queues to go idle after
+// presenting, so each frame completes on the GPU before the next one starts. Captures
+// and validation output become deterministic, but it's slow and meant for debugging only.
+func (r *VulkanRenderInfo) UseSynchronousMode() {
+	r.synchronous = true
+}
+
+// UseStaticScene stops VulkanDrawFrame from re-recording the command buffers of an overlay
+// every frame, each one gets re-recorded only once after Invalidate and is resubmitted
+// as is otherwise. It saves CPU work while nothing on screen changes.
+func (r *VulkanRenderInfo) UseStaticScene() {
+	r.staticScene = true
+}
+
+// Invalidate tells that the scene changed, every command buffer gets re-recorded
+// before it's submitted again.
+func (r *VulkanRenderInfo) Invalidate() {
+	for i := range r.outdated {
+		r.outdated[i] = true
 	}
-	err = vk.Error(vk.QueuePresent(v.queue, &presentInfo))
-	if err != nil {
-		err = fmt.Errorf("vk.QueuePresent failed with %s", err)
-		log.Println("[WARN]", err)
-		return false
+	r.secondary.invalidate()
+}
+
+// SetCommandBufferUsage sets the flags the frame command buffers begin with, e.g.
+// vk.CommandBufferUsageSimultaneousUseBit when a buffer gets submitted to several queues
+// at once. Without it, buffers re-recorded every frame for an overlay are begun with
+// vk.CommandBufferUsageOneTimeSubmitBit and buffers that get resubmitted with no flags.
+func (r *VulkanRenderInfo) SetCommandBufferUsage(flags vk.CommandBufferUsageFlagBits) {
+	r.cmdUsage = vk.CommandBufferUsageFlags(flags)
+}
+
+// SetClearColor sets the color the swapchain images are cleared to, as RGBA. Its alpha
+// only matters with a transparent CompositeAlpha of the swapchain options, with
+// vk.CompositeAlphaPreMultipliedBit the RGB must be multiplied by the alpha already.
+// The command buffers must be recorded again.
+func (r *VulkanRenderInfo) SetClearColor(color [4]float32) {
+	r.clear = color[:]
+}
+
+func (r *VulkanRenderInfo) clearColor() []float32 {
+	if r.clear == nil {
+		return []float32{0.098, 0.71, 0.996, 1}
+	}
+	return r.clear
+}
+
+func (r *VulkanRenderInfo) commandBufferUsage() vk.CommandBufferUsageFlags {
+	if r.cmdUsage == 0 && (r.secondary != nil || r.overlay != nil && !r.staticScene) {
+		return vk.CommandBufferUsageFlags(vk.CommandBufferUsageOneTimeSubmitBit)
 	}
-	return true
+	return r.cmdUsage
 }
 
 func (r *VulkanRenderInfo) CreateCommandBuffers(n uint32) error {
+	if n == 0 {
+		err := fmt.Errorf("CreateCommandBuffers: no command buffers requested")
+		return err
+	}
 	r.cmdBuffers = make([]vk.CommandBuffer, n)
 	cmdBufferAllocateInfo := vk.CommandBufferAllocateInfo{
 		SType:              vk.StructureTypeCommandBufferAllocateInfo,
@@ -210,90 +803,185 @@ func (r *VulkanRenderInfo) CreateCommandBuffers(n uint32) error {
 	return nil
 }
 
-func CreateRenderer(device vk.Device, displayFormat vk.Format) (VulkanRenderInfo, error) {
-	attachmentDescriptions := []vk.AttachmentDescription{{
-		Format:         displayFormat,
-		Samples:        vk.SampleCount1Bit,
-		LoadOp:         vk.AttachmentLoadOpClear,
-		StoreOp:        vk.AttachmentStoreOpStore,
-		StencilLoadOp:  vk.AttachmentLoadOpDontCare,
-		StencilStoreOp: vk.AttachmentStoreOpDontCare,
-		InitialLayout:  vk.ImageLayoutColorAttachmentOptimal,
-		FinalLayout:    vk.ImageLayoutColorAttachmentOptimal,
-	}}
-	colorAttachments := []vk.AttachmentReference{{
-		Attachment: 0,
-		Layout:     vk.ImageLayoutColorAttachmentOptimal,
-	}}
-	subpassDescriptions := []vk.SubpassDescription{{
-		PipelineBindPoint:    vk.PipelineBindPointGraphics,
-		ColorAttachmentCount: 1,
-		PColorAttachments:    colorAttachments,
-	}}
-	renderPassCreateInfo := vk.RenderPassCreateInfo{
-		SType:           vk.StructureTypeRenderPassCreateInfo,
-		AttachmentCount: 1,
-		PAttachments:    attachmentDescriptions,
-		SubpassCount:    1,
-		PSubpasses:      subpassDescriptions,
+// CreateRenderer creates the render pass, the command pool and the upload pool. The first color
+// attachment is the swapchain image, depth follows unless depthFormat is vk.FormatUndefined.
+func CreateRenderer(device vk.Device, queueFamily uint32,
+	attachmentDescriptions []vk.AttachmentDescription, depthFormat vk.Format) (VulkanRenderInfo, error) {
+
+	r := VulkanRenderInfo{
+		device: device,
+	}
+	if err := r.createRenderPass(attachmentDescriptions, depthFormat); err != nil {
+		return r, err
 	}
 	cmdPoolCreateInfo := vk.CommandPoolCreateInfo{
 		SType:            vk.StructureTypeCommandPoolCreateInfo,
 		Flags:            vk.CommandPoolCreateFlags(vk.CommandPoolCreateResetCommandBufferBit),
-		QueueFamilyIndex: 0,
+		QueueFamilyIndex: queueFamily,
 	}
-	var r VulkanRenderInfo
-	err := vk.Error(vk.CreateRenderPass(device, &renderPassCreateInfo, nil, &r.renderPass))
+	err := vk.Error(vk.CreateCommandPool(device, &cmdPoolCreateInfo, nil, &r.cmdPool))
 	if err != nil {
-		err = fmt.Errorf("vk.CreateRenderPass failed with %s", err)
+		err = fmt.Errorf("vk.CreateCommandPool failed with %s", err)
 		return r, err
 	}
-	err = vk.Error(vk.CreateCommandPool(device, &cmdPoolCreateInfo, nil, &r.cmdPool))
+	uploadPoolCreateInfo := vk.CommandPoolCreateInfo{
+		SType:            vk.StructureTypeCommandPoolCreateInfo,
+		Flags:            vk.CommandPoolCreateFlags(vk.CommandPoolCreateTransientBit),
+		QueueFamilyIndex: queueFamily,
+	}
+	err = vk.Error(vk.CreateCommandPool(device, &uploadPoolCreateInfo, nil, &r.uploadPool))
 	if err != nil {
 		err = fmt.Errorf("vk.CreateCommandPool failed with %s", err)
 		return r, err
 	}
-	r.device = device
 	return r, nil
 }
 
-func NewVulkanDeviceAndroid(appInfo vk.ApplicationInfo,
-	window *android.NativeWindow) (VulkanDeviceInfo, error) {
+// RecreateRenderPass replaces the render pass with one for the attachments and depth format,
+// e.g. to turn depth testing on or off. The render pass and the framebuffers are coupled by
+// their attachment count, so the framebuffers must be destroyed before and created again after,
+// see DestroyFramebuffers, and so must the pipelines. The device must be idle.
+func (r *VulkanRenderInfo) RecreateRenderPass(attachmentDescriptions []vk.AttachmentDescription,
+	depthFormat vk.Format) error {
+
+	old := r.renderPass
+	if err := r.createRenderPass(attachmentDescriptions, depthFormat); err != nil {
+		return err
+	}
+	vk.DestroyRenderPass(r.device, old, nil)
+	return nil
+}
+
+func (r *VulkanRenderInfo) createRenderPass(attachmentDescriptions []vk.AttachmentDescription,
+	depthFormat vk.Format) error {
+
+	if len(attachmentDescriptions) == 0 {
+		err := fmt.Errorf("createRenderPass: no color attachments given")
+		return err
+	}
+	colorAttachments := make([]vk.AttachmentReference, len(attachmentDescriptions))
+	for i := range colorAttachments {
+		colorAttachments[i] = vk.AttachmentReference{
+			Attachment: uint32(i),
+			Layout:     vk.ImageLayoutColorAttachmentOptimal,
+		}
+	}
+	subpassDescriptions := []vk.SubpassDescription{{
+		PipelineBindPoint:    vk.PipelineBindPointGraphics,
+		ColorAttachmentCount: uint32(len(colorAttachments)),
+		PColorAttachments:    colorAttachments,
+	}}
+	// the transition from vk.ImageLayoutUndefined happens at the start of the render pass,
+	// it must wait for the acquire semaphore which is waited on at the same stage
+	dependencies := []vk.SubpassDependency{{
+		SrcSubpass:    vk.SubpassExternal,
+		DstSubpass:    0,
+		SrcStageMask:  vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit),
+		DstStageMask:  vk.PipelineStageFlags(vk.PipelineStageColorAttachmentOutputBit),
+		SrcAccessMask: 0,
+		DstAccessMask: vk.AccessFlags(vk.AccessColorAttachmentWriteBit),
+	}}
+	descriptions := attachmentDescriptions
+	if depthFormat != vk.FormatUndefined {
+		descriptions = append(descriptions[:len(descriptions):len(descriptions)],
+			DepthAttachmentDescription(depthFormat))
+		subpassDescriptions[0].PDepthStencilAttachment = &vk.AttachmentReference{
+			Attachment: uint32(len(attachmentDescriptions)),
+			Layout:     vk.ImageLayoutDepthStencilAttachmentOptimal,
+		}
+		// the single depth image is shared by the frames in flight, the previous frame
+		// must be done testing against it before it gets cleared
+		depthStages := vk.PipelineStageFlags(vk.PipelineStageEarlyFragmentTestsBit |
+			vk.PipelineStageLateFragmentTestsBit)
+		dependencies[0].SrcStageMask |= depthStages
+		dependencies[0].DstStageMask |= depthStages
+		dependencies[0].DstAccessMask |= vk.AccessFlags(vk.AccessDepthStencilAttachmentWriteBit)
+	}
+	renderPassCreateInfo := vk.RenderPassCreateInfo{
+		SType:           vk.StructureTypeRenderPassCreateInfo,
+		AttachmentCount: uint32(len(descriptions)),
+		PAttachments:    descriptions,
+		SubpassCount:    1,
+		PSubpasses:      subpassDescriptions,
+		DependencyCount: uint32(len(dependencies)),
+		PDependencies:   dependencies,
+	}
+	var renderPass vk.RenderPass
+	err := vk.Error(vk.CreateRenderPass(r.device, &renderPassCreateInfo, nil, &renderPass))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateRenderPass failed with %s", err)
+		return err
+	}
+	r.renderPass = renderPass
+	r.depthFormat = depthFormat
+	r.colorAttachments = len(attachmentDescriptions)
+	r.attachmentCount = len(descriptions)
+	return nil
+}
 
+// createInstance creates the instance with the surface extensions, none for a headless device,
+// and the optional extensions that are available.
+func createInstance(appInfo vk.ApplicationInfo, surfaceExtensions []string) (VulkanDeviceInfo, error) {
 	// Phase 1: vk.CreateInstance with vk.InstanceCreateInfo
 
 	existingExtensions := getInstanceExtensions()
 	log.Println("[INFO] Instance extensions:", existingExtensions)
+	// validation layers are not present on stock Android images
+	log.Println("[INFO] Instance layers:", getInstanceLayers())
 
-	instanceExtensions := []string{
-		"VK_KHR_surface\x00",
-		"VK_KHR_android_surface\x00",
-	}
+	instanceExtensions := append([]string(nil), surfaceExtensions...)
 	if enableDebug {
 		instanceExtensions = append(instanceExtensions,
-			"VK_EXT_debug_report\x00")
+			"VK_EXT_debug_report")
+	}
+	// HDR color spaces, see surfaceformat.go
+	hasColorspaceExt := len(surfaceExtensions) > 0 &&
+		hasExtension(existingExtensions, "VK_EXT_swapchain_colorspace")
+	if hasColorspaceExt {
+		instanceExtensions = append(instanceExtensions,
+			"VK_EXT_swapchain_colorspace")
+	}
+	// portability drivers (MoltenVK) are only enumerated when asked for
+	var instanceFlags vk.InstanceCreateFlags
+	if hasExtension(existingExtensions, "VK_KHR_portability_enumeration") {
+		instanceExtensions = append(instanceExtensions,
+			"VK_KHR_portability_enumeration")
+		instanceFlags |= instanceCreateEnumeratePortabilityBit
+	}
+	// Vulkan 1.1+ feature queries, see features2.go
+	hasProperties2 := hasExtension(existingExtensions, "VK_KHR_get_physical_device_properties2")
+	if hasProperties2 {
+		instanceExtensions = append(instanceExtensions,
+			"VK_KHR_get_physical_device_properties2")
+	}
+	// labels for capture tools, see debugutils.go
+	hasDebugUtils := hasExtension(existingExtensions, "VK_EXT_debug_utils")
+	if hasDebugUtils {
+		instanceExtensions = append(instanceExtensions,
+			"VK_EXT_debug_utils")
 	}
 
 	// these layers must be included in APK,
 	// see Android.mk and ValidationLayers.mk
 	instanceLayers := []string{
-	// "VK_LAYER_GOOGLE_threading\x00",
-	// "VK_LAYER_LUNARG_parameter_validation\x00",
-	// "VK_LAYER_LUNARG_object_tracker\x00",
-	// "VK_LAYER_LUNARG_core_validation\x00",
-	// "VK_LAYER_LUNARG_api_dump\x00",
-	// "VK_LAYER_LUNARG_image\x00",
-	// "VK_LAYER_LUNARG_swapchain\x00",
-	// "VK_LAYER_GOOGLE_unique_objects\x00",
+	// "VK_LAYER_GOOGLE_threading",
+	// "VK_LAYER_LUNARG_parameter_validation",
+	// "VK_LAYER_LUNARG_object_tracker",
+	// "VK_LAYER_LUNARG_core_validation",
+	// "VK_LAYER_LUNARG_api_dump",
+	// "VK_LAYER_LUNARG_image",
+	// "VK_LAYER_LUNARG_swapchain",
+	// "VK_LAYER_GOOGLE_unique_objects",
 	}
 
 	instanceCreateInfo := vk.InstanceCreateInfo{
 		SType:                   vk.StructureTypeInstanceCreateInfo,
+		Flags:                   instanceFlags,
 		PApplicationInfo:        &appInfo,
 		EnabledExtensionCount:   uint32(len(instanceExtensions)),
-		PpEnabledExtensionNames: instanceExtensions,
+		PpEnabledExtensionNames: nullTerminated(instanceExtensions),
 		EnabledLayerCount:       uint32(len(instanceLayers)),
-		PpEnabledLayerNames:     instanceLayers,
+		PpEnabledLayerNames:     nullTerminated(instanceLayers),
 	}
 	var v VulkanDeviceInfo
 	err := vk.Error(vk.CreateInstance(&instanceCreateInfo, nil, &v.instance))
@@ -301,94 +989,190 @@ func NewVulkanDeviceAndroid(appInfo vk.ApplicationInfo,
 		err = fmt.Errorf("vk.CreateInstance failed with %s", err)
 		return v, err
 	}
-
-	// Phase 2: vk.CreateAndroidSurface with vk.AndroidSurfaceCreateInfo
-
-	surfaceCreateInfo := vk.AndroidSurfaceCreateInfo{
-		SType:  vk.StructureTypeAndroidSurfaceCreateInfo,
-		Window: (*vk.ANativeWindow)(window),
+	v.hasColorspaceExt = hasColorspaceExt
+	if hasProperties2 {
+		v.getFeatures2 = instanceProcAddr(v.instance, "vkGetPhysicalDeviceFeatures2KHR")
 	}
-	err = vk.Error(vk.CreateAndroidSurface(v.instance, &surfaceCreateInfo, nil, &v.surface))
-	if err != nil {
-		vk.DestroyInstance(v.instance, nil)
-		err = fmt.Errorf("vk.CreateAndroidSurface failed with %s", err)
-		return v, err
-	}
-	if v.gpuDevices, err = getPhysicalDevices(v.instance); err != nil {
-		v.gpuDevices = nil
-		vk.DestroySurface(v.instance, v.surface, nil)
-		vk.DestroyInstance(v.instance, nil)
-		return v, err
+	if hasDebugUtils && !loadDebugLabels(v.instance) {
+		log.Println("[WARN] VK_EXT_debug_utils label functions not found")
 	}
+	return v, nil
+}
 
-	existingExtensions = getDeviceExtensions(v.gpuDevices[0])
+// createDevice creates the logical device on the chosen GPU along with its queues and the debug
+// callback, present is false when there's no surface or the GPU can't present to it. The
+// instance and the surface are destroyed when it fails.
+func (v *VulkanDeviceInfo) createDevice(opts VulkanDeviceOptions, present bool) error {
+	v.caps = queryCapabilities(v.gpu)
+	existingExtensions := v.caps.Extensions
 	log.Println("[INFO] Device extensions:", existingExtensions)
+	log.Println("[INFO] Device layers:", getDeviceLayers(v.gpu))
 
 	// Phase 3: vk.CreateDevice with vk.DeviceCreateInfo (a logical device)
 
 	// these layers must be included in APK,
 	// see Android.mk and ValidationLayers.mk
 	deviceLayers := []string{
-	// "VK_LAYER_GOOGLE_threading\x00",
-	// "VK_LAYER_LUNARG_parameter_validation\x00",
-	// "VK_LAYER_LUNARG_object_tracker\x00",
-	// "VK_LAYER_LUNARG_core_validation\x00",
-	// "VK_LAYER_LUNARG_api_dump\x00",
-	// "VK_LAYER_LUNARG_image\x00",
-	// "VK_LAYER_LUNARG_swapchain\x00",
-	// "VK_LAYER_GOOGLE_unique_objects\x00",
-	}
-
-	queueCreateInfos := []vk.DeviceQueueCreateInfo{{
-		SType:            vk.StructureTypeDeviceQueueCreateInfo,
-		QueueCount:       1,
-		PQueuePriorities: []float32{1.0},
-	}}
-	deviceExtensions := []string{
-		"VK_KHR_swapchain\x00",
+	// "VK_LAYER_GOOGLE_threading",
+	// "VK_LAYER_LUNARG_parameter_validation",
+	// "VK_LAYER_LUNARG_object_tracker",
+	// "VK_LAYER_LUNARG_core_validation",
+	// "VK_LAYER_LUNARG_api_dump",
+	// "VK_LAYER_LUNARG_image",
+	// "VK_LAYER_LUNARG_swapchain",
+	// "VK_LAYER_GOOGLE_unique_objects",
+	}
+
+	// a compute-only device can't present, everything but the swapchain still works
+	v.headless = !present || !hasExtension(existingExtensions, "VK_KHR_swapchain")
+	var deviceExtensions []string
+	if !present {
+		log.Println("[INFO] nothing to present to, running in compute-only mode")
+	} else if v.headless {
+		log.Println("[WARN] VK_KHR_swapchain is missing, running in compute-only mode")
+	} else {
+		deviceExtensions = append(deviceExtensions, "VK_KHR_swapchain")
+	}
+	// HDR metadata of the swapchain, see hdrmetadata.go
+	hasHDRMetadata := !v.headless && hasExtension(existingExtensions, "VK_EXT_hdr_metadata")
+	if hasHDRMetadata {
+		deviceExtensions = append(deviceExtensions, "VK_EXT_hdr_metadata")
+	}
+	// dedicated allocations of large images, see dedicated.go
+	hasDedicatedAllocation := hasExtension(existingExtensions, "VK_KHR_get_memory_requirements2") &&
+		hasExtension(existingExtensions, "VK_KHR_dedicated_allocation")
+	if hasDedicatedAllocation {
+		deviceExtensions = append(deviceExtensions,
+			"VK_KHR_get_memory_requirements2", "VK_KHR_dedicated_allocation")
+	}
+	// must be enabled when present, the device is not fully conformant then
+	if hasExtension(existingExtensions, "VK_KHR_portability_subset") {
+		deviceExtensions = append(deviceExtensions, "VK_KHR_portability_subset")
+	}
+
+	plan, err := planQueues(v.gpu, v.surface, opts.Queues, !v.headless)
+	if err != nil {
+		v.gpuDevices = nil
+		vk.DestroySurface(v.instance, v.surface, nil)
+		vk.DestroyInstance(v.instance, nil)
+		return err
+	}
+	queueCreateInfos := plan.createInfos()
+
+	// enable the compressed texture formats the GPU has, see texture.go, pipeline
+	// statistics also across secondary command buffers, see stats.go, multiple
+	// indirect draws, see indirect.go,
+	// tessellation, see shaders.go, sparse binding, see sparse.go, depth clamp
+	// and bias clamp, see DepthState, and anisotropic filtering, see sampler.go
+	supportedFeatures := v.caps.SupportedFeatures
+	v.enabledFeatures = vk.PhysicalDeviceFeatures{
+		TextureCompressionETC2:     supportedFeatures.TextureCompressionETC2,
+		TextureCompressionBC:       supportedFeatures.TextureCompressionBC,
+		TextureCompressionASTC_LDR: supportedFeatures.TextureCompressionASTC_LDR,
+		PipelineStatisticsQuery:    supportedFeatures.PipelineStatisticsQuery,
+		InheritedQueries:           supportedFeatures.InheritedQueries,
+		MultiDrawIndirect:          supportedFeatures.MultiDrawIndirect,
+		TessellationShader:         supportedFeatures.TessellationShader,
+		SparseBinding:              supportedFeatures.SparseBinding,
+		DepthClamp:                 supportedFeatures.DepthClamp,
+		DepthBiasClamp:             supportedFeatures.DepthBiasClamp,
+		SamplerAnisotropy:          supportedFeatures.SamplerAnisotropy,
+	}
+	if opts.RobustBufferAccess {
+		if supportedFeatures.RobustBufferAccess != vk.Bool32(vk.True) {
+			v.gpuDevices = nil
+			vk.DestroySurface(v.instance, v.surface, nil)
+			vk.DestroyInstance(v.instance, nil)
+			err = fmt.Errorf("createDevice: robustBufferAccess feature is not supported")
+			return err
+		}
+		v.enabledFeatures.RobustBufferAccess = vk.Bool32(vk.True)
+		log.Println("[INFO] robustBufferAccess enabled, expect lower performance")
+	}
+	// Vulkan 1.1+ features are chained through pNext along with their extensions
+	v.features2, err = v.chainFeatures2(opts.Features2)
+	if err != nil {
+		v.gpuDevices = nil
+		vk.DestroySurface(v.instance, v.surface, nil)
+		vk.DestroyInstance(v.instance, nil)
+		err = fmt.Errorf("createDevice: %s", err)
+		return err
+	}
+	for _, name := range v.features2.extensions() {
+		if !hasExtension(deviceExtensions, name) {
+			deviceExtensions = append(deviceExtensions, name)
+		}
 	}
 	deviceCreateInfo := vk.DeviceCreateInfo{
 		SType:                   vk.StructureTypeDeviceCreateInfo,
+		PNext:                   v.features2.head(),
 		QueueCreateInfoCount:    uint32(len(queueCreateInfos)),
 		PQueueCreateInfos:       queueCreateInfos,
 		EnabledExtensionCount:   uint32(len(deviceExtensions)),
-		PpEnabledExtensionNames: deviceExtensions,
+		PpEnabledExtensionNames: nullTerminated(deviceExtensions),
 		EnabledLayerCount:       uint32(len(deviceLayers)),
-		PpEnabledLayerNames:     deviceLayers,
+		PpEnabledLayerNames:     nullTerminated(deviceLayers),
+		PEnabledFeatures:        []vk.PhysicalDeviceFeatures{v.enabledFeatures},
 	}
-	var device vk.Device // we choose the first GPU available for this device
-	err = vk.Error(vk.CreateDevice(v.gpuDevices[0], &deviceCreateInfo, nil, &device))
+	var device vk.Device // the GPU chosen by PickPhysicalDevice
+	err = vk.Error(vk.CreateDevice(v.gpu, &deviceCreateInfo, nil, &device))
 	if err != nil {
+		v.features2.Free()
+		v.features2 = nil
 		v.gpuDevices = nil
 		vk.DestroySurface(v.instance, v.surface, nil)
 		vk.DestroyInstance(v.instance, nil)
 		err = fmt.Errorf("vk.CreateDevice failed with %s", err)
-		return v, err
+		return err
 	} else {
 		v.device = device
-		var queue vk.Queue
-		vk.GetDeviceQueue(device, 0, 0, &queue)
-		v.queue = queue
+		v.fences = NewFencePool(device)
+		v.queues = make(map[QueuePurpose]vk.Queue, len(plan.slots))
+		v.queueFamilies = make(map[QueuePurpose]uint32, len(plan.slots))
+		for purpose, slot := range plan.slots {
+			var queue vk.Queue
+			vk.GetDeviceQueue(device, slot.family, slot.index, &queue)
+			v.queues[purpose] = queue
+			v.queueFamilies[purpose] = slot.family
+		}
+		v.graphicsQueue = v.queues[QueueGraphics]
+		v.presentQueue = v.queues[QueuePresent]
+		v.caps.EnabledFeatures = v.enabledFeatures
+		v.caps.QueueFamilies = v.queueFamilies
+		v.caps.Headless = v.headless
+		if hasDedicatedAllocation && !loadDedicatedAllocation(v.instance) {
+			log.Println("[WARN] vkGetImageMemoryRequirements2KHR not found, no dedicated allocations")
+		}
+		if hasHDRMetadata {
+			v.setHDRMetadata = instanceProcAddr(v.instance, "vkSetHdrMetadataEXT")
+		}
 	}
 
 	if enableDebug {
 		// Phase 4: vk.CreateDebugReportCallback
 
+		var userData *debugUserData
+		if opts.DebugUserData != nil {
+			userData = newDebugUserData(opts.DebugUserData)
+		}
 		dbgCreateInfo := vk.DebugReportCallbackCreateInfo{
 			SType:       vk.StructureTypeDebugReportCallbackCreateInfo,
 			Flags:       vk.DebugReportFlags(vk.DebugReportErrorBit | vk.DebugReportWarningBit),
 			PfnCallback: dbgCallbackFunc,
+			PUserData:   userData.pointer(),
 		}
 		var dbg vk.DebugReportCallback
 		err = vk.Error(vk.CreateDebugReportCallback(v.instance, &dbgCreateInfo, nil, &dbg))
 		if err != nil {
+			userData.Free()
 			err = fmt.Errorf("vk.CreateDebugReportCallback failed with %s", err)
 			log.Println("[WARN]", err)
-			return v, nil
+			return nil
 		}
 		v.dbg = dbg
+		v.dbgUserData = userData
 	}
-	return v, nil
+	return nil
 }
 
 func getInstanceExtensions() (extNames []string) {
@@ -421,15 +1205,65 @@ func getDeviceExtensions(gpu vk.PhysicalDevice) (extNames []string) {
 	return extNames
 }
 
+// layerInfo names a layer available to the instance or device.
+type layerInfo struct {
+	name        string
+	description string
+}
+
+func (l layerInfo) String() string {
+	return l.name + " (" + l.description + ")"
+}
+
+func getInstanceLayers() (layers []layerInfo) {
+	var instanceLayerLen uint32
+	ret := vk.EnumerateInstanceLayerProperties(&instanceLayerLen, nil)
+	check(ret, "vk.EnumerateInstanceLayerProperties")
+	instanceLayers := make([]vk.LayerProperties, instanceLayerLen)
+	ret = vk.EnumerateInstanceLayerProperties(&instanceLayerLen, instanceLayers)
+	check(ret, "vk.EnumerateInstanceLayerProperties")
+	for _, layer := range instanceLayers {
+		layer.Deref()
+		layers = append(layers, layerInfo{
+			name:        vk.ToString(layer.LayerName[:]),
+			description: vk.ToString(layer.Description[:]),
+		})
+	}
+	return layers
+}
+
+func getDeviceLayers(gpu vk.PhysicalDevice) (layers []layerInfo) {
+	var deviceLayerLen uint32
+	ret := vk.EnumerateDeviceLayerProperties(gpu, &deviceLayerLen, nil)
+	check(ret, "vk.EnumerateDeviceLayerProperties")
+	deviceLayers := make([]vk.LayerProperties, deviceLayerLen)
+	ret = vk.EnumerateDeviceLayerProperties(gpu, &deviceLayerLen, deviceLayers)
+	check(ret, "vk.EnumerateDeviceLayerProperties")
+	for _, layer := range deviceLayers {
+		layer.Deref()
+		layers = append(layers, layerInfo{
+			name:        vk.ToString(layer.LayerName[:]),
+			description: vk.ToString(layer.Description[:]),
+		})
+	}
+	return layers
+}
+
 func dbgCallbackFunc(flags vk.DebugReportFlags, objectType vk.DebugReportObjectType,
 	object uint64, location uint, messageCode int32, pLayerPrefix string,
 	pMessage string, pUserData unsafe.Pointer) vk.Bool32 {
 
+	counters := &validationCounters
+	if c, ok := DebugUserData(pUserData).(*ValidationCounters); ok {
+		counters = c
+	}
 	switch {
 	case flags&vk.DebugReportFlags(vk.DebugReportErrorBit) != 0:
 		log.Printf("[ERROR %d] %s on layer %s", messageCode, pMessage, pLayerPrefix)
+		counters.count(true, messageCode, pMessage, pLayerPrefix)
 	case flags&vk.DebugReportFlags(vk.DebugReportWarningBit) != 0:
 		log.Printf("[WARN %d] %s on layer %s", messageCode, pMessage, pLayerPrefix)
+		counters.count(false, messageCode, pMessage, pLayerPrefix)
 	default:
 		log.Printf("[WARN] unknown debug message %d (layer %s)", messageCode, pLayerPrefix)
 	}
@@ -456,63 +1290,129 @@ func getPhysicalDevices(instance vk.Instance) ([]vk.PhysicalDevice, error) {
 	return gpuList, nil
 }
 
-func (v *VulkanDeviceInfo) CreateSwapchain() (VulkanSwapchainInfo, error) {
-	gpu := v.gpuDevices[0]
+func (v *VulkanDeviceInfo) CreateSwapchain(opts VulkanSwapchainOptions) (VulkanSwapchainInfo, error) {
+	gpu := v.gpu
+	if v.headless {
+		err := fmt.Errorf("CreateSwapchain: device has no VK_KHR_swapchain support, it's compute-only")
+		return VulkanSwapchainInfo{}, err
+	}
+
+	surface := v.surface
+	if opts.Surface != vk.NullHandle {
+		surface = opts.Surface
+		var supported vk.Bool32
+		vk.GetPhysicalDeviceSurfaceSupport(gpu, v.queueFamilies[QueuePresent], surface, &supported)
+		if supported != vk.Bool32(vk.True) {
+			err := fmt.Errorf("CreateSwapchain: present queue family %d can't present to the surface",
+				v.queueFamilies[QueuePresent])
+			return VulkanSwapchainInfo{}, err
+		}
+	}
 
 	// Phase 1: vk.GetPhysicalDeviceSurfaceCapabilities
 	//			vk.GetPhysicalDeviceSurfaceFormats
+	//			cached for the device surface, see surface.go
 
 	var s VulkanSwapchainInfo
-	var surfaceCapabilities vk.SurfaceCapabilities
-	err := vk.Error(vk.GetPhysicalDeviceSurfaceCapabilities(gpu, v.surface, &surfaceCapabilities))
+	var si *SurfaceInfo
+	var err error
+	if surface == v.surface {
+		si, err = v.SurfaceInfo()
+	} else {
+		si, err = newSurfaceInfo(gpu, surface)
+	}
 	if err != nil {
-		err = fmt.Errorf("vk.GetPhysicalDeviceSurfaceCapabilities failed with %s", err)
 		return s, err
 	}
-	var formatCount uint32
-	vk.GetPhysicalDeviceSurfaceFormats(gpu, v.surface, &formatCount, nil)
-	formats := make([]vk.SurfaceFormat, formatCount)
-	vk.GetPhysicalDeviceSurfaceFormats(gpu, v.surface, &formatCount, formats)
-
-	log.Println("[INFO] got", formatCount, "physical device surface formats")
-
-	chosenFormat := -1
-	for i := 0; i < int(formatCount); i++ {
-		formats[i].Deref()
-		if formats[i].Format == vk.FormatR8g8b8a8Unorm {
-			chosenFormat = i
-			break
+	surfaceCapabilities := si.Capabilities
+	formats := si.Formats
+	for i := range formats {
+		log.Printf("[INFO] surface format %d in %s", formats[i].Format,
+			colorSpaceName(formats[i].ColorSpace))
+	}
+	var chosenFormat vk.SurfaceFormat
+	if opts.DesiredFormat != vk.FormatUndefined {
+		chosenFormat, err = findSurfaceFormat(formats, opts.DesiredFormat, v.hasColorspaceExt)
+	} else {
+		preferredFormats := opts.SurfaceFormats
+		if len(preferredFormats) == 0 {
+			preferredFormats = SDRSurfaceFormats
 		}
+		chosenFormat, err = chooseSurfaceFormat(formats, preferredFormats, v.hasColorspaceExt)
 	}
-	if chosenFormat < 0 {
-		err := fmt.Errorf("vk.GetPhysicalDeviceSurfaceFormats not found vk.FormatR8g8b8a8Unorm format")
+	if err != nil {
+		err = fmt.Errorf("vk.GetPhysicalDeviceSurfaceFormats: %s", err)
 		return s, err
 	}
+	log.Printf("[INFO] chosen surface format %d in %s", chosenFormat.Format,
+		colorSpaceName(chosenFormat.ColorSpace))
 
 	// Phase 2: vk.CreateSwapchain
 	//			create a swapchain with supported capabilities and format
 
-	surfaceCapabilities.Deref()
-	s.displaySize = surfaceCapabilities.CurrentExtent
-	s.displaySize.Deref()
-	s.displayFormat = formats[chosenFormat].Format
-	queueFamily := []uint32{0}
+	requiredUsage := vk.ImageUsageFlags(vk.ImageUsageColorAttachmentBit | opts.ImageUsage)
+	if missing := requiredUsage &^ surfaceCapabilities.SupportedUsageFlags; missing != 0 {
+		err := fmt.Errorf("CreateSwapchain: surface doesn't support image usage %s (supported %s)",
+			usageFlagsString(missing), usageFlagsString(surfaceCapabilities.SupportedUsageFlags))
+		return s, err
+	}
+	optionalUsage := vk.ImageUsageFlags(opts.OptionalImageUsage) & surfaceCapabilities.SupportedUsageFlags
+	if dropped := vk.ImageUsageFlags(opts.OptionalImageUsage) &^ optionalUsage; dropped != 0 {
+		log.Printf("[INFO] surface doesn't support optional image usage %s", usageFlagsString(dropped))
+	}
+	if surfaceCapabilities.CurrentTransform != vk.SurfaceTransformIdentityBit {
+		// the images are presented with the identity transform, the compositor rotates them
+		log.Printf("[INFO] surface transform is %s, supported %s",
+			transformFlagsString(vk.SurfaceTransformFlags(surfaceCapabilities.CurrentTransform)),
+			transformFlagsString(surfaceCapabilities.SupportedTransforms))
+	}
+	compositeAlpha, err := chooseCompositeAlpha(opts.CompositeAlpha,
+		surfaceCapabilities.SupportedCompositeAlpha)
+	if err != nil {
+		err = fmt.Errorf("CreateSwapchain: %s", err)
+		return s, err
+	}
+	if compositeAlpha != vk.CompositeAlphaOpaqueBit {
+		log.Printf("[INFO] composite alpha %s",
+			compositeAlphaFlagsString(vk.CompositeAlphaFlags(compositeAlpha)))
+	}
+	minImageCount := chooseImageCount(surfaceCapabilities, opts.ImageCount)
+	presentMode := vk.PresentModeFifo
+	if opts.LatencyMode {
+		minImageCount = chooseImageCount(surfaceCapabilities, 0)
+		presentMode = chooseLatencyPresentMode(si.PresentModes)
+	}
+	log.Printf("[INFO] swapchain of at least %d images (surface allows %d to %d), present mode %d",
+		minImageCount, surfaceCapabilities.MinImageCount, surfaceCapabilities.MaxImageCount, presentMode)
+	s.imageUsage = requiredUsage | optionalUsage
+	s.compositeAlpha = compositeAlpha
+	s.displaySize = chooseExtent(surfaceCapabilities, opts.Extent)
+	s.displayFormat = chosenFormat.Format
+	s.displayColorSpace = chosenFormat.ColorSpace
+	// images are shared when rendering and presentation use different families
+	sharingMode := vk.SharingModeExclusive
+	queueFamilies := []uint32{v.queueFamilies[QueueGraphics]}
+	if v.queueFamilies[QueuePresent] != v.queueFamilies[QueueGraphics] {
+		sharingMode = vk.SharingModeConcurrent
+		queueFamilies = append(queueFamilies, v.queueFamilies[QueuePresent])
+	}
 	swapchainCreateInfo := vk.SwapchainCreateInfo{
 		SType:           vk.StructureTypeSwapchainCreateInfo,
-		Surface:         v.surface,
-		MinImageCount:   surfaceCapabilities.MinImageCount,
-		ImageFormat:     formats[chosenFormat].Format,
-		ImageColorSpace: formats[chosenFormat].ColorSpace,
-		ImageExtent:     surfaceCapabilities.CurrentExtent,
-		ImageUsage:      vk.ImageUsageFlags(vk.ImageUsageColorAttachmentBit),
+		Surface:         surface,
+		MinImageCount:   minImageCount,
+		ImageFormat:     chosenFormat.Format,
+		ImageColorSpace: chosenFormat.ColorSpace,
+		ImageExtent:     s.displaySize,
+		ImageUsage:      s.imageUsage,
 		PreTransform:    vk.SurfaceTransformIdentityBit,
+		CompositeAlpha:  compositeAlpha,
 
 		ImageArrayLayers:      1,
-		ImageSharingMode:      vk.SharingModeExclusive,
-		QueueFamilyIndexCount: 1,
-		PQueueFamilyIndices:   queueFamily,
-		PresentMode:           vk.PresentModeFifo,
-		OldSwapchain:          vk.NullHandle,
+		ImageSharingMode:      sharingMode,
+		QueueFamilyIndexCount: uint32(len(queueFamilies)),
+		PQueueFamilyIndices:   queueFamilies,
+		PresentMode:           presentMode,
+		OldSwapchain:          opts.OldSwapchain,
 		Clipped:               vk.False,
 	}
 	s.swapchains = make([]vk.Swapchain, 1)
@@ -527,33 +1427,144 @@ func (v *VulkanDeviceInfo) CreateSwapchain() (VulkanSwapchainInfo, error) {
 		err = fmt.Errorf("vk.GetSwapchainImages failed with %s", err)
 		return s, err
 	}
-	for i := range formats {
-		formats[i].Free()
-	}
 	s.device = v.device
+	s.gpu = v.gpu
 	return s, nil
 }
 
-func (s *VulkanSwapchainInfo) CreateFramebuffers(renderPass vk.RenderPass, depthView vk.ImageView) error {
-	// Phase 1: vk.GetSwapchainImages
+// RecreateSwapchain creates a new swapchain in place of the old one, e.g. after a resize,
+// and destroys the old one only when the new one got created. The framebuffers
+// must be created again for the new swapchain.
+func (v *VulkanDeviceInfo) RecreateSwapchain(old *VulkanSwapchainInfo,
+	opts VulkanSwapchainOptions) (VulkanSwapchainInfo, error) {
 
-	var swapchainImagesCount uint32
-	err := vk.Error(vk.GetSwapchainImages(s.device, s.DefaultSwapchain(), &swapchainImagesCount, nil))
+	s, err := v.recreateSwapchain(old, opts)
 	if err != nil {
-		err = fmt.Errorf("vk.GetSwapchainImages failed with %s", err)
+		return s, err
+	}
+	// the old swapchain images may still be in use
+	vk.DeviceWaitIdle(v.device)
+	old.Destroy()
+	return s, nil
+}
+
+// RecreateSwapchainDeferred is RecreateSwapchain without waiting for the device to go idle,
+// the old swapchain along with its framebuffers and views is handed over to r instead.
+// VulkanDrawFrame destroys it once the frames in flight that used it have completed.
+func (v *VulkanDeviceInfo) RecreateSwapchainDeferred(old *VulkanSwapchainInfo,
+	opts VulkanSwapchainOptions, r *VulkanRenderInfo) (VulkanSwapchainInfo, error) {
+
+	s, err := v.recreateSwapchain(old, opts)
+	if err != nil {
+		return s, err
+	}
+	r.retired = append(r.retired, retiredSwapchain{
+		swapchain: *old,
+		lastFrame: r.frameCount,
+	})
+	*old = VulkanSwapchainInfo{}
+	return s, nil
+}
+
+func (v *VulkanDeviceInfo) recreateSwapchain(old *VulkanSwapchainInfo,
+	opts VulkanSwapchainOptions) (VulkanSwapchainInfo, error) {
+
+	if len(old.swapchains) > 1 {
+		err := fmt.Errorf("RecreateSwapchain: only a single swapchain can be recreated, got %d",
+			len(old.swapchains))
+		return VulkanSwapchainInfo{}, err
+	}
+	// the current extent has changed
+	if si, err := v.SurfaceInfo(); err != nil {
+		return VulkanSwapchainInfo{}, err
+	} else if err := si.Refresh(); err != nil {
+		return VulkanSwapchainInfo{}, err
+	}
+	opts.OldSwapchain = old.DefaultSwapchain()
+	return v.CreateSwapchain(opts)
+}
+
+// retiredSwapchain is a replaced swapchain, or other objects that depended on it,
+// waiting for the frames using them to complete.
+type retiredSwapchain struct {
+	swapchain VulkanSwapchainInfo
+	destroy   func() // destroys the other objects, see retire
+	lastFrame uint64 // the frames before it rendered into the swapchain
+}
+
+// retire hands objects the frames in flight may still use over to r, like
+// RecreateSwapchainDeferred does with the swapchain, destroy gets called once they completed.
+func (r *VulkanRenderInfo) retire(destroy func()) {
+	r.retired = append(r.retired, retiredSwapchain{
+		destroy:   destroy,
+		lastFrame: r.frameCount,
+	})
+}
+
+// destroyRetired destroys the retired swapchains no frame in flight uses anymore,
+// or all of them. It must be called right after waiting for the fence of the current
+// frame: frames submitted maxFramesInFlight frames ago or earlier have completed then.
+func (r *VulkanRenderInfo) destroyRetired(all bool) {
+	kept := r.retired[:0]
+	for _, old := range r.retired {
+		if !all && old.lastFrame+maxFramesInFlight > r.frameCount {
+			kept = append(kept, old)
+			continue
+		}
+		old.swapchain.Destroy()
+		if old.destroy != nil {
+			old.destroy()
+		}
+	}
+	r.retired = kept
+}
+
+// CreateFramebuffers creates a framebuffer for each swapchain image. Each framebuffer gets
+// its own image for every extra color attachment, these follow the swapchain image in the
+// order of the render pass attachments, depthView goes last. The number of attachments
+// must match the render pass of r.
+func (s *VulkanSwapchainInfo) CreateFramebuffers(r *VulkanRenderInfo, depthView vk.ImageView,
+	extraAttachments []vk.AttachmentDescription) error {
+
+	attachmentCount := 1 + len(extraAttachments)
+	if depthView != vk.NullHandle {
+		attachmentCount++
+	}
+	if attachmentCount != r.attachmentCount {
+		err := fmt.Errorf("CreateFramebuffers: %d attachments don't match the %d of the render pass",
+			attachmentCount, r.attachmentCount)
 		return err
 	}
-	swapchainImages := make([]vk.Image, swapchainImagesCount)
-	vk.GetSwapchainImages(s.device, s.DefaultSwapchain(), &swapchainImagesCount, swapchainImages)
+
+	// Phase 1: vk.GetSwapchainImages
+	//			the images of all swapchains, one after another
+
+	s.displayImages = make([]vk.Image, 0, s.ImageCount())
+	for k := range s.swapchains {
+		var swapchainImagesCount uint32
+		err := vk.Error(vk.GetSwapchainImages(s.device, s.swapchains[k], &swapchainImagesCount, nil))
+		if err != nil {
+			err = fmt.Errorf("vk.GetSwapchainImages failed with %s", err)
+			return err
+		}
+		images := make([]vk.Image, swapchainImagesCount)
+		vk.GetSwapchainImages(s.device, s.swapchains[k], &swapchainImagesCount, images)
+		if swapchainImagesCount != s.swapchainLen[k] {
+			err := fmt.Errorf("CreateFramebuffers: swapchain %d has %d images, %d when created",
+				k, swapchainImagesCount, s.swapchainLen[k])
+			return err
+		}
+		s.displayImages = append(s.displayImages, images[:swapchainImagesCount]...)
+	}
 
 	// Phase 2: vk.CreateImageView
 	//			create image view for each swapchain image
 
-	s.displayViews = make([]vk.ImageView, len(swapchainImages))
+	s.displayViews = make([]vk.ImageView, len(s.displayImages))
 	for i := range s.displayViews {
 		viewCreateInfo := vk.ImageViewCreateInfo{
 			SType:    vk.StructureTypeImageViewCreateInfo,
-			Image:    swapchainImages[i],
+			Image:    s.displayImages[i],
 			ViewType: vk.ImageViewType2d,
 			Format:   s.displayFormat,
 			Components: vk.ComponentMapping{
@@ -574,28 +1585,45 @@ func (s *VulkanSwapchainInfo) CreateFramebuffers(renderPass vk.RenderPass, depth
 			return err // bail out
 		}
 	}
-	swapchainImages = nil
 
-	// Phase 3: vk.CreateFramebuffer
+	// Phase 3: vk.CreateImage
+	//			create images for the extra color attachments of each framebuffer
+
+	s.attachments = make([][]VulkanAttachmentImage, len(s.displayImages))
+	for i := range s.attachments {
+		for _, desc := range extraAttachments {
+			a, err := s.createAttachmentImage(desc)
+			if err != nil {
+				return err // bail out
+			}
+			s.attachments[i] = append(s.attachments[i], a)
+		}
+	}
+
+	// Phase 4: vk.CreateFramebuffer
 	//			create a framebuffer from each swapchain image
 
-	s.framebuffers = make([]vk.Framebuffer, s.DefaultSwapchainLen())
+	s.framebuffers = make([]vk.Framebuffer, len(s.displayImages))
 	for i := range s.framebuffers {
 		attachments := []vk.ImageView{
-			s.displayViews[i], depthView,
+			s.displayViews[i],
+		}
+		for _, a := range s.attachments[i] {
+			attachments = append(attachments, a.view)
+		}
+		if depthView != vk.NullHandle {
+			attachments = append(attachments, depthView)
 		}
+		// the count comes from the slice, so no view is left out or dangling
 		fbCreateInfo := vk.FramebufferCreateInfo{
 			SType:           vk.StructureTypeFramebufferCreateInfo,
-			RenderPass:      renderPass,
+			RenderPass:      r.renderPass,
 			Layers:          1,
-			AttachmentCount: 1, // 2 if has depthView
+			AttachmentCount: uint32(len(attachments)),
 			PAttachments:    attachments,
 			Width:           s.displaySize.Width,
 			Height:          s.displaySize.Height,
 		}
-		if depthView != vk.NullHandle {
-			fbCreateInfo.AttachmentCount = 2
-		}
 		err := vk.Error(vk.CreateFramebuffer(s.device, &fbCreateInfo, nil, &s.framebuffers[i]))
 		if err != nil {
 			err = fmt.Errorf("vk.CreateFramebuffer failed with %s", err)
@@ -606,7 +1634,7 @@ func (s *VulkanSwapchainInfo) CreateFramebuffers(renderPass vk.RenderPass, depth
 }
 
 func (v VulkanDeviceInfo) CreateBuffers() (VulkanBufferInfo, error) {
-	gpu := v.gpuDevices[0]
+	gpu := v.gpu
 
 	// Phase 1: vk.CreateBuffer
 	//			create the triangle vertex buffer
@@ -617,17 +1645,16 @@ func (v VulkanDeviceInfo) CreateBuffers() (VulkanBufferInfo, error) {
 		0, 1, 0,
 	}
 	vertexDataSize := 4 * len(vertexData)
-	queueFamilyIdx := []uint32{0}
 	bufferCreateInfo := vk.BufferCreateInfo{
-		SType:                 vk.StructureTypeBufferCreateInfo,
-		Size:                  vk.DeviceSize(vertexDataSize),
-		Usage:                 vk.BufferUsageFlags(vk.BufferUsageVertexBufferBit),
-		SharingMode:           vk.SharingModeExclusive,
-		QueueFamilyIndexCount: 1,
-		PQueueFamilyIndices:   queueFamilyIdx,
+		SType: vk.StructureTypeBufferCreateInfo,
+		Size:  vk.DeviceSize(vertexDataSize),
+		Usage: vk.BufferUsageFlags(vk.BufferUsageVertexBufferBit),
 	}
+	v.bufferSharing.apply(&bufferCreateInfo)
 	buffer := VulkanBufferInfo{
 		vertexBuffers: make([]vk.Buffer, 1),
+		layout:        PositionLayout(),
+		vertexCount:   3,
 	}
 	err := vk.Error(vk.CreateBuffer(v.device, &bufferCreateInfo, nil, &buffer.vertexBuffers[0]))
 	if err != nil {
@@ -682,15 +1709,149 @@ func (v VulkanDeviceInfo) CreateBuffers() (VulkanBufferInfo, error) {
 	return buffer, err
 }
 
+// CreateBuffersSeparate creates the triangle with positions, normals and uvs in a buffer
+// each instead of interleaved, so a stream can be updated without touching the others.
+// The pipeline must be created with SeparateLayout.
+func (v *VulkanDeviceInfo) CreateBuffersSeparate() (VulkanBufferInfo, error) {
+	streams := [][]float32{{
+		-1, -1, 0, // positions
+		1, -1, 0,
+		0, 1, 0,
+	}, {
+		0, 0, 1, // normals
+		0, 0, 1,
+		0, 0, 1,
+	}, {
+		0, 0, // uvs
+		1, 0,
+		0.5, 1,
+	}}
+	buffer := VulkanBufferInfo{
+		device:      v.device,
+		layout:      SeparateLayout(),
+		vertexCount: 3,
+	}
+	for _, stream := range streams {
+		size := vk.DeviceSize(4 * len(stream)) // 4 = sizeof(float32)
+		buf, mem, err := v.createBuffer(size, vk.BufferUsageVertexBufferBit,
+			vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+		if err != nil {
+			buffer.Destroy()
+			return buffer, err
+		}
+		buffer.vertexBuffers = append(buffer.vertexBuffers, buf)
+		buffer.vertexMems = append(buffer.vertexMems, mem)
+
+		var data unsafe.Pointer
+		err = vk.Error(vk.MapMemory(v.device, mem, 0, size, 0, &data))
+		if err != nil {
+			buffer.Destroy()
+			err = fmt.Errorf("vk.MapMemory failed with %s", err)
+			return buffer, err
+		}
+		if n := vk.MemCopyFloat32(data, stream); n != len(stream) {
+			log.Println("[WARN] failed to copy vertex stream data")
+		}
+		vk.UnmapMemory(v.device, mem)
+	}
+	return buffer, nil
+}
+
+// CreateVertexBuffers uploads the vertices as is into a single vertex buffer,
+// the pipeline must be created with Vertex.VertexLayout.
+func (v *VulkanDeviceInfo) CreateVertexBuffers(vertices []Vertex) (VulkanBufferInfo, error) {
+	buffer := VulkanBufferInfo{
+		device:      v.device,
+		layout:      Vertex{}.VertexLayout(),
+		vertexCount: uint32(len(vertices)),
+	}
+	data := vertexBytes(vertices)
+	if len(data) == 0 {
+		err := fmt.Errorf("CreateVertexBuffers: no vertices given")
+		return buffer, err
+	}
+	size := vk.DeviceSize(len(data))
+	buf, mem, err := v.createBuffer(size, vk.BufferUsageVertexBufferBit,
+		vk.MemoryPropertyHostVisibleBit|vk.MemoryPropertyHostCoherentBit)
+	if err != nil {
+		return buffer, err
+	}
+	buffer.vertexBuffers = []vk.Buffer{buf}
+	buffer.vertexMems = []vk.DeviceMemory{mem}
+	var ptr unsafe.Pointer
+	err = vk.Error(vk.MapMemory(v.device, mem, 0, size, 0, &ptr))
+	if err != nil {
+		buffer.Destroy()
+		err = fmt.Errorf("vk.MapMemory failed with %s", err)
+		return buffer, err
+	}
+	vk.MemCopyByte(ptr, data)
+	vk.UnmapMemory(v.device, mem)
+	return buffer, nil
+}
+
+// createBuffer creates a buffer and binds it to a fresh allocation of memory
+// with the requested properties.
+func (v *VulkanDeviceInfo) createBuffer(size vk.DeviceSize, usage vk.BufferUsageFlagBits,
+	props vk.MemoryPropertyFlagBits) (vk.Buffer, vk.DeviceMemory, error) {
+
+	var buffer vk.Buffer
+	var mem vk.DeviceMemory
+	bufferCreateInfo := vk.BufferCreateInfo{
+		SType: vk.StructureTypeBufferCreateInfo,
+		Size:  size,
+		Usage: vk.BufferUsageFlags(usage),
+	}
+	v.bufferSharing.apply(&bufferCreateInfo)
+	err := vk.Error(vk.CreateBuffer(v.device, &bufferCreateInfo, nil, &buffer))
+	if err != nil {
+		err = fmt.Errorf("vk.CreateBuffer failed with %s", err)
+		return buffer, mem, err
+	}
+	var memReq vk.MemoryRequirements
+	vk.GetBufferMemoryRequirements(v.device, buffer, &memReq)
+	memReq.Deref()
+	memTypeIdx, ok := vk.FindMemoryTypeIndex(v.gpu, memReq.MemoryTypeBits, props)
+	if !ok {
+		vk.DestroyBuffer(v.device, buffer, nil)
+		err := fmt.Errorf("vk.FindMemoryTypeIndex: no memory type with properties %x", props)
+		return vk.NullHandle, mem, err
+	}
+	allocInfo := vk.MemoryAllocateInfo{
+		SType:           vk.StructureTypeMemoryAllocateInfo,
+		AllocationSize:  memReq.Size,
+		MemoryTypeIndex: memTypeIdx,
+	}
+	err = vk.Error(vk.AllocateMemory(v.device, &allocInfo, nil, &mem))
+	if err != nil {
+		vk.DestroyBuffer(v.device, buffer, nil)
+		err = fmt.Errorf("vk.AllocateMemory failed with %s", err)
+		return vk.NullHandle, mem, err
+	}
+	err = vk.Error(vk.BindBufferMemory(v.device, buffer, mem, 0))
+	if err != nil {
+		vk.DestroyBuffer(v.device, buffer, nil)
+		vk.FreeMemory(v.device, mem, nil)
+		err = fmt.Errorf("vk.BindBufferMemory failed with %s", err)
+		return vk.NullHandle, vk.NullHandle, err
+	}
+	return buffer, mem, nil
+}
+
 func (buf *VulkanBufferInfo) Destroy() {
 	for i := range buf.vertexBuffers {
 		vk.DestroyBuffer(buf.device, buf.vertexBuffers[i], nil)
 	}
+	for i := range buf.vertexMems {
+		vk.FreeMemory(buf.device, buf.vertexMems[i], nil)
+	}
+	buf.destroyIndices()
+	buf.destroyIndirect()
 }
 
 func LoadShader(device vk.Device, name string) (vk.ShaderModule, error) {
 	var module vk.ShaderModule
-	data, err := Asset(name)
+	data, err := LoadAsset(name)
 	if err != nil {
 		err := fmt.Errorf("asset %s not found: %s", name, err)
 		return module, err
@@ -711,57 +1872,185 @@ func LoadShader(device vk.Device, name string) (vk.ShaderModule, error) {
 	return module, nil
 }
 
-func CreateGraphicsPipeline(device vk.Device,
-	displaySize vk.Extent2D, renderPass vk.RenderPass) (VulkanGfxPipelineInfo, error) {
+// DepthState configures the depth test of a pipeline, it only has effect when
+// the render pass subpass has a depth attachment.
+type DepthState struct {
+	TestEnable  bool
+	WriteEnable bool
+	CompareOp   vk.CompareOp
+	// ClampEnable clamps the fragment depths to the viewport range instead of clipping
+	// the primitives, e.g. for shadow casters behind the near plane of the light.
+	// It needs the depthClamp device feature, see ValidateDepthState.
+	ClampEnable bool
+	// BiasEnable offsets the depths of polygons, e.g. against shadow acne, by
+	// BiasConstantFactor plus BiasSlopeFactor times the depth slope, clamped to
+	// BiasClamp unless it's 0. A non zero BiasClamp needs the depthBiasClamp feature.
+	BiasEnable         bool
+	BiasConstantFactor float32
+	BiasClamp          float32
+	BiasSlopeFactor    float32
+	// DynamicBias ignores the bias factors above, they're set with vk.CmdSetDepthBias
+	// while recording instead, e.g. to tune them per shadow map.
+	DynamicBias bool
+	// StencilTestEnable runs the stencil test with Stencil for front and back faces alike,
+	// the attachment must have a stencil aspect, see outline.go for an example.
+	StencilTestEnable bool
+	Stencil           vk.StencilOpState
+}
+
+// ValidateDepthState checks that the device has the features the depth state needs.
+func (v *VulkanDeviceInfo) ValidateDepthState(depth DepthState) error {
+	if depth.ClampEnable && v.enabledFeatures.DepthClamp != vk.Bool32(vk.True) {
+		return fmt.Errorf("depth clamp needs the depthClamp feature")
+	}
+	if depth.BiasEnable && !depth.DynamicBias && depth.BiasClamp != 0 &&
+		v.enabledFeatures.DepthBiasClamp != vk.Bool32(vk.True) {
+		return fmt.Errorf("depth bias clamp %g needs the depthBiasClamp feature", depth.BiasClamp)
+	}
+	return nil
+}
+
+// MultisampleState configures the rasterization samples of a pipeline, Samples
+// must match the samples of the render pass attachments.
+type MultisampleState struct {
+	Samples vk.SampleCountFlagBits
+	// SampleMask holds a bit per sample, so a uint32 for every 32 samples,
+	// all samples are written when it's empty.
+	SampleMask []vk.SampleMask
+	// AlphaToCoverage derives the coverage from the alpha of the first color output,
+	// e.g. for foliage, AlphaToOne then replaces that alpha with 1 and needs the
+	// alphaToOne device feature.
+	AlphaToCoverage bool
+	AlphaToOne      bool
+}
+
+// DefaultMultisampleState renders a single sample with all bits of the mask set.
+func DefaultMultisampleState() MultisampleState {
+	return MultisampleState{
+		Samples: vk.SampleCount1Bit,
+	}
+}
+
+// sampleMask returns the sample mask to use, checking it has a word per 32 samples.
+func (ms MultisampleState) sampleMask() ([]vk.SampleMask, error) {
+	words := (int(ms.Samples) + 31) / 32
+	if len(ms.SampleMask) == 0 {
+		mask := make([]vk.SampleMask, words)
+		for i := range mask {
+			mask[i] = vk.SampleMask(vk.MaxUint32)
+		}
+		return mask, nil
+	}
+	if len(ms.SampleMask) != words {
+		err := fmt.Errorf("sample mask has %d words, %d samples need %d",
+			len(ms.SampleMask), ms.Samples, words)
+		return nil, err
+	}
+	return ms.SampleMask, nil
+}
+
+// DefaultDepthState tests and writes depth, keeping the nearest fragments. Transparent
+// objects usually test without writing and skyboxes compare with vk.CompareOpLessOrEqual.
+func DefaultDepthState() DepthState {
+	return DepthState{
+		TestEnable:  true,
+		WriteEnable: true,
+		CompareOp:   vk.CompareOpLess,
+	}
+}
+
+// CreateGraphicsPipeline creates the triangle pipeline, colorAttachments is the number
+// of color attachments of the render pass subpass. TriangleShaders are used when
+// shaders is empty and PositionLayout when vertex has no bindings. With tessellation
+// stages the vertices are drawn as patches, see ValidateShaderStages.
+// An empty layout is created when layout is vk.NullHandle, otherwise the given one
+// is used and stays owned by the caller, it must outlive the pipeline.
+func CreateGraphicsPipeline(device vk.Device, displaySize vk.Extent2D, renderPass vk.RenderPass,
+	layout vk.PipelineLayout, colorAttachments int, depth DepthState, multisample MultisampleState,
+	vertex VertexLayout, shaders []ShaderStage) (VulkanGfxPipelineInfo, error) {
 
 	var gfxPipeline VulkanGfxPipelineInfo
+	if multisample.Samples == 0 {
+		multisample.Samples = vk.SampleCount1Bit
+	}
+	if _, err := multisample.sampleMask(); err != nil {
+		err = fmt.Errorf("CreateGraphicsPipeline: %s", err)
+		return gfxPipeline, err
+	}
 
 	// Phase 1: vk.CreatePipelineLayout
-	//			create pipeline layout (empty)
+	//			create pipeline layout (empty) unless one is shared
 
-	pipelineLayoutCreateInfo := vk.PipelineLayoutCreateInfo{
-		SType: vk.StructureTypePipelineLayoutCreateInfo,
+	gfxPipeline.layout = layout
+	if layout == vk.NullHandle {
+		pipelineLayoutCreateInfo := vk.PipelineLayoutCreateInfo{
+			SType: vk.StructureTypePipelineLayoutCreateInfo,
+		}
+		err := vk.Error(vk.CreatePipelineLayout(device, &pipelineLayoutCreateInfo, nil, &gfxPipeline.layout))
+		if err != nil {
+			err = fmt.Errorf("vk.CreatePipelineLayout failed with %s", err)
+			return gfxPipeline, err
+		}
+		gfxPipeline.ownsLayout = true
+	}
+
+	// Phase 2: vk.CreatePipelineCache
+	//			kept along with the layout to rebuild the pipeline
+
+	pipelineCacheInfo := vk.PipelineCacheCreateInfo{
+		SType: vk.StructureTypePipelineCacheCreateInfo,
 	}
-	err := vk.Error(vk.CreatePipelineLayout(device, &pipelineLayoutCreateInfo, nil, &gfxPipeline.layout))
+	err := vk.Error(vk.CreatePipelineCache(device, &pipelineCacheInfo, nil, &gfxPipeline.cache))
 	if err != nil {
-		err = fmt.Errorf("vk.CreatePipelineLayout failed with %s", err)
+		err = fmt.Errorf("vk.CreatePipelineCache failed with %s", err)
 		return gfxPipeline, err
 	}
+	gfxPipeline.device = device
+	gfxPipeline.colorAttachments = colorAttachments
+	gfxPipeline.depth = depth
+	gfxPipeline.multisample = multisample
+	gfxPipeline.vertex = vertex
+	gfxPipeline.shaders = shaders
+	err = gfxPipeline.createPipeline(displaySize, renderPass)
+	return gfxPipeline, err
+}
+
+// createPipeline creates the pipeline for the extent and render pass, with the layout,
+// cache and states gfx got created with.
+func (gfx *VulkanGfxPipelineInfo) createPipeline(displaySize vk.Extent2D,
+	renderPass vk.RenderPass) error {
+
+	device := gfx.device
+	vertex := gfx.vertex
+	shaders := gfx.shaders
+	sampleMask, err := gfx.multisample.sampleMask()
+	if err != nil {
+		err = fmt.Errorf("CreateGraphicsPipeline: %s", err)
+		return err
+	}
+	// the viewport and scissor are baked in, see RecreatePipeline
+	var dynamicStates []vk.DynamicState
+	if gfx.depth.BiasEnable && gfx.depth.DynamicBias {
+		dynamicStates = append(dynamicStates, vk.DynamicStateDepthBias)
+	}
 	dynamicState := vk.PipelineDynamicStateCreateInfo{
-		SType: vk.StructureTypePipelineDynamicStateCreateInfo,
-		// no dynamic state for this demo
+		SType:             vk.StructureTypePipelineDynamicStateCreateInfo,
+		DynamicStateCount: uint32(len(dynamicStates)),
+		PDynamicStates:    dynamicStates,
 	}
 
-	// Phase 2: load shaders and specify shader stages
+	// Phase 1: load shaders and specify shader stages
 
-	vertexShader, err := LoadShader(device, "shaders/tri-vert.spv")
-	if err != nil { // err has enough info
-		return gfxPipeline, err
+	if len(shaders) == 0 {
+		shaders = TriangleShaders
 	}
-	defer vk.DestroyShaderModule(device, vertexShader, nil)
-
-	fragmentShader, err := LoadShader(device, "shaders/tri-frag.spv")
+	shaderStages, shaderModules, err := loadShaderStages(device, shaders)
 	if err != nil { // err has enough info
-		return gfxPipeline, err
-	}
-	defer vk.DestroyShaderModule(device, fragmentShader, nil)
-
-	shaderStages := []vk.PipelineShaderStageCreateInfo{
-		{
-			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
-			Stage:  vk.ShaderStageVertexBit,
-			Module: vertexShader,
-			PName:  "main\x00",
-		},
-		{
-			SType:  vk.StructureTypePipelineShaderStageCreateInfo,
-			Stage:  vk.ShaderStageFragmentBit,
-			Module: fragmentShader,
-			PName:  "main\x00",
-		},
+		return err
 	}
+	defer destroyShaderModules(device, shaderModules)
 
-	// Phase 3: specify viewport state
+	// Phase 2: specify viewport state
 
 	viewports := []vk.Viewport{{
 		MinDepth: 0.0,
@@ -785,43 +2074,64 @@ func CreateGraphicsPipeline(device vk.Device,
 		PScissors:     scissors,
 	}
 
-	// Phase 4: specify multisample state
+	// Phase 3: specify multisample state
 	//					color blend state
 	//					rasterizer state
+	//					depth stencil state
 
-	sampleMask := []vk.SampleMask{vk.SampleMask(vk.MaxUint32)}
 	multisampleState := vk.PipelineMultisampleStateCreateInfo{
-		SType:                vk.StructureTypePipelineMultisampleStateCreateInfo,
-		RasterizationSamples: vk.SampleCount1Bit,
-		SampleShadingEnable:  vk.False,
-		PSampleMask:          sampleMask,
-	}
-	attachmentStates := []vk.PipelineColorBlendAttachmentState{{
-		ColorWriteMask: vk.ColorComponentFlags(
-			vk.ColorComponentRBit | vk.ColorComponentGBit |
-				vk.ColorComponentBBit | vk.ColorComponentABit,
-		),
-		BlendEnable: vk.False,
-	}}
+		SType:                 vk.StructureTypePipelineMultisampleStateCreateInfo,
+		RasterizationSamples:  gfx.multisample.Samples,
+		SampleShadingEnable:   vk.False,
+		PSampleMask:           sampleMask,
+		AlphaToCoverageEnable: vkBool(gfx.multisample.AlphaToCoverage),
+		AlphaToOneEnable:      vkBool(gfx.multisample.AlphaToOne),
+	}
+	// one state per color target
+	attachmentStates := make([]vk.PipelineColorBlendAttachmentState, gfx.colorAttachments)
+	for i := range attachmentStates {
+		attachmentStates[i] = vk.PipelineColorBlendAttachmentState{
+			ColorWriteMask: vk.ColorComponentFlags(
+				vk.ColorComponentRBit | vk.ColorComponentGBit |
+					vk.ColorComponentBBit | vk.ColorComponentABit,
+			),
+			BlendEnable: vk.False,
+		}
+	}
 	colorBlendState := vk.PipelineColorBlendStateCreateInfo{
 		SType:           vk.StructureTypePipelineColorBlendStateCreateInfo,
 		LogicOpEnable:   vk.False,
 		LogicOp:         vk.LogicOpCopy,
-		AttachmentCount: 1,
+		AttachmentCount: uint32(len(attachmentStates)),
 		PAttachments:    attachmentStates,
 	}
 	rasterState := vk.PipelineRasterizationStateCreateInfo{
 		SType:                   vk.StructureTypePipelineRasterizationStateCreateInfo,
-		DepthClampEnable:        vk.False,
+		DepthClampEnable:        vkBool(gfx.depth.ClampEnable),
 		RasterizerDiscardEnable: vk.False,
 		PolygonMode:             vk.PolygonModeFill,
 		CullMode:                vk.CullModeFlags(vk.CullModeNone),
 		FrontFace:               vk.FrontFaceClockwise,
-		DepthBiasEnable:         vk.False,
+		DepthBiasEnable:         vkBool(gfx.depth.BiasEnable),
+		DepthBiasConstantFactor: gfx.depth.BiasConstantFactor,
+		DepthBiasClamp:          gfx.depth.BiasClamp,
+		DepthBiasSlopeFactor:    gfx.depth.BiasSlopeFactor,
 		LineWidth:               1,
 	}
+	depthStencilState := vk.PipelineDepthStencilStateCreateInfo{
+		SType:                 vk.StructureTypePipelineDepthStencilStateCreateInfo,
+		DepthTestEnable:       vkBool(gfx.depth.TestEnable),
+		DepthWriteEnable:      vkBool(gfx.depth.WriteEnable),
+		DepthCompareOp:        gfx.depth.CompareOp,
+		DepthBoundsTestEnable: vk.False,
+		StencilTestEnable:     vkBool(gfx.depth.StencilTestEnable),
+		Front:                 gfx.depth.Stencil,
+		Back:                  gfx.depth.Stencil,
+		MinDepthBounds:        0,
+		MaxDepthBounds:        1,
+	}
 
-	// Phase 5: specify input assembly state
+	// Phase 4: specify input assembly state
 	//					vertex input state and attributes
 
 	inputAssemblyState := vk.PipelineInputAssemblyStateCreateInfo{
@@ -829,60 +2139,145 @@ func CreateGraphicsPipeline(device vk.Device,
 		Topology:               vk.PrimitiveTopologyTriangleList,
 		PrimitiveRestartEnable: vk.True,
 	}
-	vertexInputBindings := []vk.VertexInputBindingDescription{{
-		Binding:   0,
-		Stride:    3 * 4, // 4 = sizeof(float32)
-		InputRate: vk.VertexInputRateVertex,
-	}}
-	vertexInputAttributes := []vk.VertexInputAttributeDescription{{
-		Binding:  0,
-		Location: 0,
-		Format:   vk.FormatR32g32b32Sfloat,
-		Offset:   0,
-	}}
+	if vertex.Points {
+		inputAssemblyState.Topology = vk.PrimitiveTopologyPointList
+		// primitive restart is not allowed with lists of points
+		inputAssemblyState.PrimitiveRestartEnable = vk.False
+	}
+	var tessellationState *vk.PipelineTessellationStateCreateInfo
+	if points, ok := patchControlPoints(shaders); ok {
+		inputAssemblyState.Topology = vk.PrimitiveTopologyPatchList
+		// primitive restart is not allowed with patches
+		inputAssemblyState.PrimitiveRestartEnable = vk.False
+		tessellationState = &vk.PipelineTessellationStateCreateInfo{
+			SType:              vk.StructureTypePipelineTessellationStateCreateInfo,
+			PatchControlPoints: points,
+		}
+	}
+	if len(vertex.Bindings) == 0 {
+		vertex = PositionLayout()
+	}
+	vertexInputBindings := vertex.Bindings
+	vertexInputAttributes := vertex.Attributes
 	vertexInputState := vk.PipelineVertexInputStateCreateInfo{
 		SType: vk.StructureTypePipelineVertexInputStateCreateInfo,
-		VertexBindingDescriptionCount:   1,
+		VertexBindingDescriptionCount:   uint32(len(vertexInputBindings)),
 		PVertexBindingDescriptions:      vertexInputBindings,
-		VertexAttributeDescriptionCount: 1,
+		VertexAttributeDescriptionCount: uint32(len(vertexInputAttributes)),
 		PVertexAttributeDescriptions:    vertexInputAttributes,
 	}
+	if validateVertexLayout {
+		if err := validateVertexInput(vertexInputBindings, vertexInputAttributes); err != nil {
+			err = fmt.Errorf("CreateGraphicsPipeline: %s", err)
+			return err
+		}
+	}
 
-	// Phase 5: vk.CreatePipelineCache
-	//			vk.CreateGraphicsPipelines
+	// Phase 5: vk.CreateGraphicsPipelines
 
-	pipelineCacheInfo := vk.PipelineCacheCreateInfo{
-		SType: vk.StructureTypePipelineCacheCreateInfo,
-	}
-	err = vk.Error(vk.CreatePipelineCache(device, &pipelineCacheInfo, nil, &gfxPipeline.cache))
-	if err != nil {
-		err = fmt.Errorf("vk.CreatePipelineCache failed with %s", err)
-		return gfxPipeline, err
-	}
 	pipelineCreateInfos := []vk.GraphicsPipelineCreateInfo{{
 		SType:               vk.StructureTypeGraphicsPipelineCreateInfo,
-		StageCount:          2, // vert + frag
+		StageCount:          uint32(len(shaderStages)),
 		PStages:             shaderStages,
 		PVertexInputState:   &vertexInputState,
 		PInputAssemblyState: &inputAssemblyState,
+		PTessellationState:  tessellationState,
 		PViewportState:      &viewportState,
 		PRasterizationState: &rasterState,
 		PMultisampleState:   &multisampleState,
 		PColorBlendState:    &colorBlendState,
+		PDepthStencilState:  &depthStencilState,
 		PDynamicState:       &dynamicState,
-		Layout:              gfxPipeline.layout,
+		Layout:              gfx.layout,
 		RenderPass:          renderPass,
 	}}
 	pipelines := make([]vk.Pipeline, 1)
 	err = vk.Error(vk.CreateGraphicsPipelines(device,
-		gfxPipeline.cache, 1, pipelineCreateInfos, nil, pipelines))
+		gfx.cache, 1, pipelineCreateInfos, nil, pipelines))
 	if err != nil {
 		err = fmt.Errorf("vk.CreateGraphicsPipelines failed with %s", err)
-		return gfxPipeline, err
+		return err
 	}
-	gfxPipeline.pipeline = pipelines[0]
-	gfxPipeline.device = device
-	return gfxPipeline, nil
+	gfx.pipeline = pipelines[0]
+	return nil
+}
+
+// RecreatePipeline rebuilds the pipeline of gfx for a new extent or render pass, e.g. after
+// a resize as the viewport and scissor are baked in. The device gets waited for, so the old
+// pipeline is no longer in use, the layout and cache are kept and reused.
+func RecreatePipeline(gfx *VulkanGfxPipelineInfo, device vk.Device,
+	displaySize vk.Extent2D, renderPass vk.RenderPass) error {
+
+	if gfx.device != device {
+		err := fmt.Errorf("RecreatePipeline: the pipeline belongs to another device")
+		return err
+	}
+	vk.DeviceWaitIdle(device)
+	vk.DestroyPipeline(device, gfx.pipeline, nil)
+	gfx.pipeline = vk.NullHandle
+	return gfx.createPipeline(displaySize, renderPass)
+}
+
+// RecreatePipelineDeferred is RecreatePipeline without waiting for the device to go idle,
+// the old pipeline is handed over to r instead, see RecreateSwapchainDeferred. It's kept
+// when the new one fails. The command buffers using the old pipeline must be recorded
+// again before they're submitted, see Invalidate.
+func RecreatePipelineDeferred(gfx *VulkanGfxPipelineInfo, device vk.Device,
+	displaySize vk.Extent2D, renderPass vk.RenderPass, r *VulkanRenderInfo) error {
+
+	if gfx.device != device {
+		err := fmt.Errorf("RecreatePipelineDeferred: the pipeline belongs to another device")
+		return err
+	}
+	// gfx.pipeline is only replaced once the new one got created
+	old := gfx.pipeline
+	if err := gfx.createPipeline(displaySize, renderPass); err != nil {
+		return err
+	}
+	r.retire(func() {
+		vk.DestroyPipeline(device, old, nil)
+	})
+	return nil
+}
+
+// ReloadShaders rebuilds the pipeline of gfx with the vertex and fragment stages loaded
+// again from the vertName and fragName assets, e.g. to iterate on shaders without restarting
+// the app when LoadAsset reads files, see FileAssets. Other stages are kept, the layout and cache are reused. The new pipeline is
+// built before the old one gets destroyed, so gfx stays usable when the shaders fail to load.
+// The command buffers referencing the old pipeline must be recorded again.
+func ReloadShaders(gfx *VulkanGfxPipelineInfo, device vk.Device, displaySize vk.Extent2D,
+	renderPass vk.RenderPass, vertName, fragName string) error {
+
+	if gfx.device != device {
+		err := fmt.Errorf("ReloadShaders: the pipeline belongs to another device")
+		return err
+	}
+	shaders := gfx.shaders
+	if len(shaders) == 0 {
+		shaders = TriangleShaders
+	}
+	reloaded := make([]ShaderStage, len(shaders))
+	copy(reloaded, shaders)
+	for i := range reloaded {
+		switch reloaded[i].Stage {
+		case vk.ShaderStageVertexBit:
+			reloaded[i].Asset = vertName
+		case vk.ShaderStageFragmentBit:
+			reloaded[i].Asset = fragName
+		}
+	}
+	next := *gfx
+	next.shaders = reloaded
+	if err := next.createPipeline(displaySize, renderPass); err != nil {
+		err = fmt.Errorf("ReloadShaders: %s", err)
+		return err
+	}
+	vk.DeviceWaitIdle(device)
+	vk.DestroyPipeline(device, gfx.pipeline, nil)
+	gfx.pipeline = next.pipeline
+	gfx.shaders = reloaded
+	log.Printf("[INFO] pipeline reloaded with %s and %s", vertName, fragName)
+	return nil
 }
 
 func (gfx *VulkanGfxPipelineInfo) Destroy() {
@@ -891,16 +2286,32 @@ func (gfx *VulkanGfxPipelineInfo) Destroy() {
 	}
 	vk.DestroyPipeline(gfx.device, gfx.pipeline, nil)
 	vk.DestroyPipelineCache(gfx.device, gfx.cache, nil)
-	vk.DestroyPipelineLayout(gfx.device, gfx.layout, nil)
+	if gfx.ownsLayout {
+		vk.DestroyPipelineLayout(gfx.device, gfx.layout, nil)
+	}
 }
 
-func (s *VulkanSwapchainInfo) Destroy() {
-	for i := uint32(0); i < s.DefaultSwapchainLen(); i++ {
+// DestroyFramebuffers destroys the framebuffers along with the image views and the extra
+// attachments CreateFramebuffers made for them, the swapchain is kept.
+func (s *VulkanSwapchainInfo) DestroyFramebuffers() {
+	for i := range s.framebuffers {
 		vk.DestroyFramebuffer(s.device, s.framebuffers[i], nil)
+	}
+	for i := range s.displayViews {
 		vk.DestroyImageView(s.device, s.displayViews[i], nil)
 	}
 	s.framebuffers = nil
 	s.displayViews = nil
+	for i := range s.attachments {
+		for j := range s.attachments[i] {
+			s.attachments[i][j].Destroy(s.device)
+		}
+	}
+	s.attachments = nil
+}
+
+func (s *VulkanSwapchainInfo) Destroy() {
+	s.DestroyFramebuffers()
 	for i := range s.swapchains {
 		vk.DestroySwapchain(s.device, s.swapchains[i], nil)
 	}
@@ -909,18 +2320,53 @@ func (s *VulkanSwapchainInfo) Destroy() {
 func DestroyInOrder(v *VulkanDeviceInfo, s *VulkanSwapchainInfo,
 	r *VulkanRenderInfo, b *VulkanBufferInfo, gfx *VulkanGfxPipelineInfo) {
 
+	// frames may still be in flight
+	vk.DeviceWaitIdle(v.device)
+	r.destroyRetired(true)
+	for i := range r.fences {
+		vk.DestroyFence(v.device, r.fences[i], nil)
+		vk.DestroySemaphore(v.device, r.renderSemaphores[i], nil)
+	}
+	for i := range r.semaphores {
+		vk.DestroySemaphore(v.device, r.semaphores[i], nil)
+	}
+	r.fences = nil
+	r.semaphores = nil
+	r.renderSemaphores = nil
+	r.imagesInFlight = nil
+
+	r.secondary.free(v.device, r.cmdPool)
+	r.secondary = nil
 	vk.FreeCommandBuffers(v.device, r.cmdPool, uint32(len(r.cmdBuffers)), r.cmdBuffers)
 	r.cmdBuffers = nil
 
 	vk.DestroyCommandPool(v.device, r.cmdPool, nil)
+	vk.DestroyCommandPool(v.device, r.uploadPool, nil)
 	vk.DestroyRenderPass(v.device, r.renderPass, nil)
 
 	s.Destroy()
 	gfx.Destroy()
 	b.Destroy()
+	v.Destroy()
+}
+
+// Destroy destroys the device, the surface and the instance, everything created
+// from them must be destroyed before. See DestroyInOrder.
+func (v *VulkanDeviceInfo) Destroy() {
+	v.destroySamplers()
+	v.fences.Destroy()
 	vk.DestroyDevice(v.device, nil)
+	unloadDedicatedAllocation()
+	v.features2.Free()
+	v.features2 = nil
+	if v.surface != vk.NullHandle {
+		vk.DestroySurface(v.instance, v.surface, nil)
+	}
 	if v.dbg != vk.NullHandle {
 		vk.DestroyDebugReportCallback(v.instance, v.dbg, nil)
+		v.dbgUserData.Free()
+		v.dbgUserData = nil
 	}
+	unloadDebugLabels()
 	vk.DestroyInstance(v.instance, nil)
 }
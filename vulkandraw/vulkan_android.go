@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	vk "github.com/vulkan-go/vulkan"
+	"github.com/xlab/android-go/android"
+)
+
+func NewVulkanDeviceAndroid(appInfo vk.ApplicationInfo,
+	window *android.NativeWindow, opts VulkanDeviceOptions) (VulkanDeviceInfo, error) {
+
+	v, err := createInstance(appInfo, []string{
+		"VK_KHR_surface",
+		"VK_KHR_android_surface",
+	})
+	if err != nil {
+		return v, err
+	}
+
+	// Phase 2: vk.CreateAndroidSurface with vk.AndroidSurfaceCreateInfo
+
+	surfaceCreateInfo := vk.AndroidSurfaceCreateInfo{
+		SType:  vk.StructureTypeAndroidSurfaceCreateInfo,
+		Window: (*vk.ANativeWindow)(window),
+	}
+	err = vk.Error(vk.CreateAndroidSurface(v.instance, &surfaceCreateInfo, nil, &v.surface))
+	if err != nil {
+		vk.DestroyInstance(v.instance, nil)
+		err = fmt.Errorf("vk.CreateAndroidSurface failed with %s", err)
+		return v, err
+	}
+	if v.gpuDevices, err = getPhysicalDevices(v.instance); err != nil {
+		v.gpuDevices = nil
+		vk.DestroySurface(v.instance, v.surface, nil)
+		vk.DestroyInstance(v.instance, nil)
+		return v, err
+	}
+	rate := opts.RateDevice
+	if rate == nil {
+		rate = RateDeviceFor(opts.Preference)
+	}
+	if v.gpu, err = PickPhysicalDevice(v.gpuDevices, v.surface, rate); err != nil {
+		v.gpuDevices = nil
+		vk.DestroySurface(v.instance, v.surface, nil)
+		vk.DestroyInstance(v.instance, nil)
+		return v, err
+	}
+
+	// a GPU that can't present is picked only when no other one can, see DefaultRateDevice
+	if err = v.createDevice(opts, canPresent(v.gpu, v.surface)); err != nil {
+		return v, err
+	}
+	return v, nil
+}
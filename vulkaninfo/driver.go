@@ -0,0 +1,204 @@
+package main
+
+/*
+#cgo LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdint.h>
+#include <stddef.h>
+#include <stdlib.h>
+
+// vkGetPhysicalDeviceProperties2 and VK_KHR_driver_properties are missing from the bindings,
+// so the entry point is resolved by hand and called through these self-contained typedefs.
+
+#define VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_PROPERTIES_2 1000059001
+#define VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_DRIVER_PROPERTIES 1000196000
+
+typedef struct {
+	uint32_t sType;
+	void*    pNext;
+	uint32_t driverID;
+	char     driverName[256];
+	char     driverInfo[256];
+	uint8_t  conformanceVersion[4];
+} driverProperties;
+
+// properties only reserves room for VkPhysicalDeviceProperties, which is not read,
+// uint64_t keeps it aligned like the VkDeviceSize limits within.
+typedef struct {
+	uint32_t sType;
+	void*    pNext;
+	uint64_t properties[128];
+} physicalDeviceProperties2;
+
+typedef void* (*getInstanceProcAddrFn)(void* instance, const char* name);
+typedef void (*getProperties2Fn)(void* gpu, physicalDeviceProperties2* props);
+
+static void* instanceProcAddr(void* instance, const char* name) {
+	static getInstanceProcAddrFn getProcAddr = NULL;
+	if (getProcAddr == NULL) {
+		void* lib = dlopen("libvulkan.so", RTLD_NOW | RTLD_LOCAL);
+		if (lib == NULL) {
+			lib = dlopen("libvulkan.so.1", RTLD_NOW | RTLD_LOCAL);
+		}
+		if (lib == NULL) {
+			return NULL;
+		}
+		getProcAddr = (getInstanceProcAddrFn)dlsym(lib, "vkGetInstanceProcAddr");
+		if (getProcAddr == NULL) {
+			return NULL;
+		}
+	}
+	return getProcAddr(instance, name);
+}
+
+static void callGetDriverProperties(void* fn, void* gpu, driverProperties* driver) {
+	physicalDeviceProperties2 props = {0};
+	props.sType = VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_PROPERTIES_2;
+	props.pNext = driver;
+	driver->sType = VK_STRUCTURE_TYPE_PHYSICAL_DEVICE_DRIVER_PROPERTIES;
+	driver->pNext = NULL;
+	((getProperties2Fn)fn)(gpu, &props);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// instanceProcAddr resolves an entry point missing from the bindings, nil when
+// no driver provides it.
+func instanceProcAddr(instance vk.Instance, name string) unsafe.Pointer {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return C.instanceProcAddr(unsafe.Pointer(instance), cName)
+}
+
+// driverProperties are the VK_KHR_driver_properties of a GPU, useful when filing driver bugs.
+type driverProperties struct {
+	id                 uint32
+	name               string
+	info               string
+	conformanceVersion string
+}
+
+// getDriverProperties queries the driver properties through vkGetPhysicalDeviceProperties2KHR,
+// the instance must have VK_KHR_get_physical_device_properties2 enabled. ok is false when
+// the GPU has neither VK_KHR_driver_properties nor Vulkan 1.2.
+func getDriverProperties(instance vk.Instance, gpu vk.PhysicalDevice) (props driverProperties, ok bool) {
+	var gpuProperties vk.PhysicalDeviceProperties
+	vk.GetPhysicalDeviceProperties(gpu, &gpuProperties)
+	gpuProperties.Deref()
+	if gpuProperties.ApiVersion < vk.MakeVersion(1, 2, 0) &&
+		!hasExtension(getDeviceExtensions(gpu), "VK_KHR_driver_properties") {
+		return props, false
+	}
+	fn := instanceProcAddr(instance, "vkGetPhysicalDeviceProperties2KHR")
+	if fn == nil {
+		return props, false
+	}
+	var driver C.driverProperties
+	C.callGetDriverProperties(fn, unsafe.Pointer(gpu), &driver)
+	cv := driver.conformanceVersion
+	return driverProperties{
+		id:   uint32(driver.driverID),
+		name: C.GoString(&driver.driverName[0]),
+		info: C.GoString(&driver.driverInfo[0]),
+		conformanceVersion: fmt.Sprintf("%d.%d.%d.%d",
+			uint8(cv[0]), uint8(cv[1]), uint8(cv[2]), uint8(cv[3])),
+	}, true
+}
+
+func (v *VulkanDeviceInfo) driverProperties() (driverProperties, bool) {
+	if !v.hasProperties2 {
+		return driverProperties{}, false
+	}
+	return getDriverProperties(v.instance, v.gpuDevices[0])
+}
+
+// driverIDName decodes a VkDriverId.
+func driverIDName(id uint32) string {
+	switch id {
+	case 1:
+		return "AMD Proprietary"
+	case 2:
+		return "AMD Open Source"
+	case 3:
+		return "Mesa RADV"
+	case 4:
+		return "NVIDIA"
+	case 5:
+		return "Intel Proprietary Windows"
+	case 6:
+		return "Intel Mesa"
+	case 7:
+		return "Imagination Proprietary"
+	case 8:
+		return "Qualcomm Proprietary"
+	case 9:
+		return "ARM Proprietary"
+	case 10:
+		return "Google SwiftShader"
+	case 11:
+		return "GGP Proprietary"
+	case 12:
+		return "Broadcom Proprietary"
+	case 13:
+		return "Mesa llvmpipe"
+	case 14:
+		return "MoltenVK"
+	case 15:
+		return "CoreAVI Proprietary"
+	case 16:
+		return "Juice Proprietary"
+	case 17:
+		return "VeriSilicon Proprietary"
+	case 18:
+		return "Mesa Turnip"
+	case 19:
+		return "Mesa V3DV"
+	case 20:
+		return "Mesa PanVK"
+	case 21:
+		return "Samsung Proprietary"
+	case 22:
+		return "Mesa Venus"
+	case 23:
+		return "Mesa Dozen"
+	case 24:
+		return "Mesa NVK"
+	default:
+		return fmt.Sprintf("Unknown (%d)", id)
+	}
+}
+
+func hasExtension(extNames []string, name string) bool {
+	for _, extName := range extNames {
+		if extName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureNullTerminated appends the terminator C expects, unless the string has one already.
+func ensureNullTerminated(s string) string {
+	if strings.HasSuffix(s, "\x00") {
+		return s
+	}
+	return s + "\x00"
+}
+
+// nullTerminated returns a copy of the extension or layer names with each one terminated,
+// for the PpEnabled*Names fields, so plain Go strings can be passed around.
+func nullTerminated(names []string) []string {
+	terminated := make([]string, len(names))
+	for i, name := range names {
+		terminated[i] = ensureNullTerminated(name)
+	}
+	return terminated
+}
@@ -0,0 +1,83 @@
+package main
+
+/*
+#include <stdint.h>
+
+// vkGetPhysicalDevicePresentRectanglesKHR is missing from the bindings, it's called
+// through these self-contained typedefs. The surface handle is read through a pointer
+// since it's a pointer on 64-bit and a uint64_t on 32-bit platforms.
+
+typedef struct {
+	int32_t  x;
+	int32_t  y;
+	uint32_t width;
+	uint32_t height;
+} rect2D;
+
+typedef int32_t (*getPresentRectanglesFn)(void* gpu, uint64_t surface,
+	uint32_t* count, rect2D* rects);
+
+static int32_t callGetPresentRectangles(void* fn, void* gpu, const void* surface,
+	uint32_t* count, rect2D* rects) {
+
+	return ((getPresentRectanglesFn)fn)(gpu, *(const uint64_t*)surface, count, rects);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+
+	vk "github.com/vulkan-go/vulkan"
+)
+
+// getPresentRectangles queries the regions of the surface that the GPU presents to,
+// e.g. one per display when several physical displays show a single surface on foldables.
+// ok is false when the GPU has neither VK_KHR_device_group nor Vulkan 1.1.
+func getPresentRectangles(instance vk.Instance, gpu vk.PhysicalDevice,
+	surface vk.Surface) (rects []vk.Rect2D, ok bool, err error) {
+
+	var gpuProperties vk.PhysicalDeviceProperties
+	vk.GetPhysicalDeviceProperties(gpu, &gpuProperties)
+	gpuProperties.Deref()
+	if gpuProperties.ApiVersion < vk.MakeVersion(1, 1, 0) &&
+		!hasExtension(getDeviceExtensions(gpu), "VK_KHR_device_group") {
+		return nil, false, nil
+	}
+	fn := instanceProcAddr(instance, "vkGetPhysicalDevicePresentRectanglesKHR")
+	if fn == nil {
+		return nil, false, nil
+	}
+	var count C.uint32_t
+	ret := vk.Result(C.callGetPresentRectangles(fn, unsafe.Pointer(gpu),
+		unsafe.Pointer(&surface), &count, nil))
+	if err := vk.Error(ret); err != nil {
+		err = fmt.Errorf("vkGetPhysicalDevicePresentRectanglesKHR failed with %s", err)
+		return nil, true, err
+	}
+	if count == 0 {
+		return nil, true, nil
+	}
+	cRects := make([]C.rect2D, count)
+	ret = vk.Result(C.callGetPresentRectangles(fn, unsafe.Pointer(gpu),
+		unsafe.Pointer(&surface), &count, &cRects[0]))
+	if err := vk.Error(ret); err != nil {
+		err = fmt.Errorf("vkGetPhysicalDevicePresentRectanglesKHR failed with %s", err)
+		return nil, true, err
+	}
+	rects = make([]vk.Rect2D, count)
+	for i := range rects {
+		rects[i] = vk.Rect2D{
+			Offset: vk.Offset2D{X: int32(cRects[i].x), Y: int32(cRects[i].y)},
+			Extent: vk.Extent2D{Width: uint32(cRects[i].width), Height: uint32(cRects[i].height)},
+		}
+	}
+	return rects, true, nil
+}
+
+// rectString formats a present rectangle, e.g. "1080x2400 at (0, 0)".
+func rectString(rect vk.Rect2D) string {
+	return fmt.Sprintf("%dx%d at (%d, %d)", rect.Extent.Width, rect.Extent.Height,
+		rect.Offset.X, rect.Offset.Y)
+}
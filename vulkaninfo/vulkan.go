@@ -14,6 +14,9 @@ type VulkanDeviceInfo struct {
 	instance vk.Instance
 	surface  vk.Surface
 	device   vk.Device
+
+	// hasProperties2 is set when VK_KHR_get_physical_device_properties2 is enabled
+	hasProperties2 bool
 }
 
 func NewVulkanDevice(appInfo *vk.ApplicationInfo,
@@ -23,14 +26,20 @@ func NewVulkanDevice(appInfo *vk.ApplicationInfo,
 
 	// step 1: create a Vulkan instance.
 	instanceExtensions := []string{
-		"VK_KHR_surface\x00",
-		"VK_KHR_android_surface\x00",
+		"VK_KHR_surface",
+		"VK_KHR_android_surface",
+	}
+	// needed to query the driver properties, see driver.go
+	if hasExtension(getInstanceExtensions(), "VK_KHR_get_physical_device_properties2") {
+		instanceExtensions = append(instanceExtensions,
+			"VK_KHR_get_physical_device_properties2")
+		v.hasProperties2 = true
 	}
 	instanceCreateInfo := &vk.InstanceCreateInfo{
 		SType:                   vk.StructureTypeInstanceCreateInfo,
 		PApplicationInfo:        appInfo,
 		EnabledExtensionCount:   uint32(len(instanceExtensions)),
-		PpEnabledExtensionNames: instanceExtensions,
+		PpEnabledExtensionNames: nullTerminated(instanceExtensions),
 	}
 	err := vk.Error(vk.CreateInstance(instanceCreateInfo, nil, &v.instance))
 	if err != nil {
@@ -55,6 +64,14 @@ func NewVulkanDevice(appInfo *vk.ApplicationInfo,
 		vk.DestroyInstance(v.instance, nil)
 		return nil, err
 	}
+	// the first GPU is used, it's pointless to go on if none can present
+	if !anyCanPresent(v.gpuDevices, v.surface) {
+		v.gpuDevices = nil
+		vk.DestroySurface(v.instance, v.surface, nil)
+		vk.DestroyInstance(v.instance, nil)
+		err = fmt.Errorf("NewVulkanDevice: no GPU can present to this surface")
+		return nil, err
+	}
 
 	// step 3: create a logical device from the first GPU available.
 	queueCreateInfos := []vk.DeviceQueueCreateInfo{{
@@ -63,14 +80,14 @@ func NewVulkanDevice(appInfo *vk.ApplicationInfo,
 		PQueuePriorities: []float32{1.0},
 	}}
 	deviceExtensions := []string{
-		"VK_KHR_swapchain\x00",
+		"VK_KHR_swapchain",
 	}
 	deviceCreateInfo := &vk.DeviceCreateInfo{
 		SType:                   vk.StructureTypeDeviceCreateInfo,
 		QueueCreateInfoCount:    uint32(len(queueCreateInfos)),
 		PQueueCreateInfos:       queueCreateInfos,
 		EnabledExtensionCount:   uint32(len(deviceExtensions)),
-		PpEnabledExtensionNames: deviceExtensions,
+		PpEnabledExtensionNames: nullTerminated(deviceExtensions),
 	}
 	var device vk.Device
 	err = vk.Error(vk.CreateDevice(v.gpuDevices[0], deviceCreateInfo, nil, &device))
@@ -117,34 +134,60 @@ func getPhysicalDevices(instance vk.Instance) ([]vk.PhysicalDevice, error) {
 	return gpuList, nil
 }
 
-func getInstanceLayers() (layerNames []string) {
+// anyCanPresent checks whether a queue family of any of the GPUs can present to the surface.
+func anyCanPresent(gpus []vk.PhysicalDevice, surface vk.Surface) bool {
+	for _, gpu := range gpus {
+		var queueCount uint32
+		vk.GetPhysicalDeviceQueueFamilyProperties(gpu, &queueCount, nil)
+		for i := uint32(0); i < queueCount; i++ {
+			var supported vk.Bool32
+			vk.GetPhysicalDeviceSurfaceSupport(gpu, i, surface, &supported)
+			if supported == vk.Bool32(vk.True) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// layerInfo names a layer available to the instance or device.
+type layerInfo struct {
+	name        string
+	description string
+}
+
+func getInstanceLayers() (layers []layerInfo) {
 	var instanceLayerLen uint32
-	err := vk.EnumerateInstanceLayerProperties(&instanceLayerLen, nil)
-	orPanic(err)
+	ret := vk.EnumerateInstanceLayerProperties(&instanceLayerLen, nil)
+	orPanic(ret)
 	instanceLayers := make([]vk.LayerProperties, instanceLayerLen)
-	err = vk.EnumerateInstanceLayerProperties(&instanceLayerLen, instanceLayers)
-	orPanic(err)
+	ret = vk.EnumerateInstanceLayerProperties(&instanceLayerLen, instanceLayers)
+	orPanic(ret)
 	for _, layer := range instanceLayers {
 		layer.Deref()
-		layerNames = append(layerNames,
-			vk.ToString(layer.LayerName[:]))
+		layers = append(layers, layerInfo{
+			name:        vk.ToString(layer.LayerName[:]),
+			description: vk.ToString(layer.Description[:]),
+		})
 	}
-	return layerNames
+	return layers
 }
 
-func getDeviceLayers(gpu vk.PhysicalDevice) (layerNames []string) {
+func getDeviceLayers(gpu vk.PhysicalDevice) (layers []layerInfo) {
 	var deviceLayerLen uint32
-	err := vk.EnumerateDeviceLayerProperties(gpu, &deviceLayerLen, nil)
-	orPanic(err)
+	ret := vk.EnumerateDeviceLayerProperties(gpu, &deviceLayerLen, nil)
+	orPanic(ret)
 	deviceLayers := make([]vk.LayerProperties, deviceLayerLen)
-	err = vk.EnumerateDeviceLayerProperties(gpu, &deviceLayerLen, deviceLayers)
-	orPanic(err)
+	ret = vk.EnumerateDeviceLayerProperties(gpu, &deviceLayerLen, deviceLayers)
+	orPanic(ret)
 	for _, layer := range deviceLayers {
 		layer.Deref()
-		layerNames = append(layerNames,
-			vk.ToString(layer.LayerName[:]))
+		layers = append(layers, layerInfo{
+			name:        vk.ToString(layer.LayerName[:]),
+			description: vk.ToString(layer.Description[:]),
+		})
 	}
-	return layerNames
+	return layers
 }
 
 func getInstanceExtensions() (extNames []string) {
@@ -181,6 +224,7 @@ func printInfo(v *VulkanDeviceInfo) {
 	var gpuProperties vk.PhysicalDeviceProperties
 	vk.GetPhysicalDeviceProperties(v.gpuDevices[0], &gpuProperties)
 	gpuProperties.Deref()
+	gpuProperties.Limits.Deref()
 
 	table := tablewriter.CreateTable()
 	table.UTF8Box()
@@ -194,35 +238,81 @@ func printInfo(v *VulkanDeviceInfo) {
 	table.AddRow("API Version", vk.Version(gpuProperties.ApiVersion))
 	table.AddRow("API Version Supported", vk.Version(gpuProperties.ApiVersion))
 	table.AddRow("Driver Version", vk.Version(gpuProperties.DriverVersion))
+	if driver, ok := v.driverProperties(); ok {
+		table.AddRow("Driver ID", driverIDName(driver.id))
+		table.AddRow("Driver Name", driver.name)
+		table.AddRow("Driver Info", driver.info)
+		table.AddRow("Conformance Version", driver.conformanceVersion)
+	} else {
+		table.AddRow("Driver ID", "N/A")
+	}
 
-	var surfaceCapabilities vk.SurfaceCapabilities
-	vk.GetPhysicalDeviceSurfaceCapabilities(v.gpuDevices[0], v.surface, &surfaceCapabilities)
-	surfaceCapabilities.Deref()
-	surfaceCapabilities.CurrentExtent.Deref()
-	surfaceCapabilities.MinImageExtent.Deref()
-	surfaceCapabilities.MaxImageExtent.Deref()
+	table.AddRow("Color sample counts",
+		sampleCountsString(gpuProperties.Limits.FramebufferColorSampleCounts))
+	table.AddRow("Depth sample counts",
+		sampleCountsString(gpuProperties.Limits.FramebufferDepthSampleCounts))
 
 	table.AddSeparator()
-	table.AddRow("Image count", fmt.Sprintf("%d - %d",
-		surfaceCapabilities.MinImageCount, surfaceCapabilities.MaxImageCount))
-	table.AddRow("Array layers", fmt.Sprintf("%d",
-		surfaceCapabilities.MaxImageArrayLayers))
-	table.AddRow("Image size (current)", fmt.Sprintf("%dx%d",
-		surfaceCapabilities.CurrentExtent.Width, surfaceCapabilities.CurrentExtent.Height))
-	table.AddRow("Image size (extent)", fmt.Sprintf("%dx%d - %dx%d",
-		surfaceCapabilities.MinImageExtent.Width, surfaceCapabilities.MinImageExtent.Height,
-		surfaceCapabilities.MaxImageExtent.Width, surfaceCapabilities.MaxImageExtent.Height))
-	table.AddRow("Usage flags", fmt.Sprintf("%02x",
-		surfaceCapabilities.SupportedUsageFlags))
-	table.AddRow("Current transform", fmt.Sprintf("%02x",
-		surfaceCapabilities.CurrentTransform))
-	table.AddRow("Allowed transforms", fmt.Sprintf("%02x",
-		surfaceCapabilities.SupportedTransforms))
+	var surfaceCapabilities vk.SurfaceCapabilities
+	ret := vk.GetPhysicalDeviceSurfaceCapabilities(v.gpuDevices[0], v.surface, &surfaceCapabilities)
+	if err := vk.Error(ret); err != nil {
+		// the struct stays zeroed, don't print it as if it were valid
+		table.AddRow("Surface capabilities", fmt.Sprintf("N/A (%s)", err))
+	} else {
+		surfaceCapabilities.Deref()
+		surfaceCapabilities.CurrentExtent.Deref()
+		surfaceCapabilities.MinImageExtent.Deref()
+		surfaceCapabilities.MaxImageExtent.Deref()
+
+		table.AddRow("Image count", fmt.Sprintf("%d - %d",
+			surfaceCapabilities.MinImageCount, surfaceCapabilities.MaxImageCount))
+		table.AddRow("Array layers", fmt.Sprintf("%d",
+			surfaceCapabilities.MaxImageArrayLayers))
+		table.AddRow("Image size (current)", fmt.Sprintf("%dx%d",
+			surfaceCapabilities.CurrentExtent.Width, surfaceCapabilities.CurrentExtent.Height))
+		table.AddRow("Image size (extent)", fmt.Sprintf("%dx%d - %dx%d",
+			surfaceCapabilities.MinImageExtent.Width, surfaceCapabilities.MinImageExtent.Height,
+			surfaceCapabilities.MaxImageExtent.Width, surfaceCapabilities.MaxImageExtent.Height))
+		table.AddRow("Usage flags",
+			usageFlagsString(surfaceCapabilities.SupportedUsageFlags))
+		table.AddRow("Current transform",
+			transformFlagsString(vk.SurfaceTransformFlags(surfaceCapabilities.CurrentTransform)))
+		table.AddRow("Allowed transforms",
+			transformFlagsString(surfaceCapabilities.SupportedTransforms))
+	}
+	rects, ok, err := getPresentRectangles(v.instance, v.gpuDevices[0], v.surface)
+	switch {
+	case !ok:
+		table.AddRow("Present rectangles", "not supported")
+	case err != nil:
+		table.AddRow("Present rectangles", fmt.Sprintf("N/A (%s)", err))
+	case len(rects) == 0:
+		table.AddRow("Present rectangles", "none")
+	default:
+		for i, rect := range rects {
+			table.AddRow(fmt.Sprintf("Present rectangle %d", i+1), rectString(rect))
+		}
+	}
 	var formatCount uint32
-	vk.GetPhysicalDeviceSurfaceFormats(v.gpuDevices[0], v.surface, &formatCount, nil)
-	table.AddRow("Surface formats", fmt.Sprintf("%d of %d", formatCount, vk.FormatRangeSize))
+	ret = vk.GetPhysicalDeviceSurfaceFormats(v.gpuDevices[0], v.surface, &formatCount, nil)
+	if err := vk.Error(ret); err != nil {
+		table.AddRow("Surface formats", fmt.Sprintf("N/A (%s)", err))
+	} else {
+		table.AddRow("Surface formats", fmt.Sprintf("%d of %d", formatCount, vk.FormatRangeSize))
+	}
 	table.AddSeparator()
 
+	var features vk.PhysicalDeviceFeatures
+	vk.GetPhysicalDeviceFeatures(v.gpuDevices[0], &features)
+	features.Deref()
+	for _, group := range featureGroups(&features) {
+		table.AddRow(group.title, "")
+		for _, f := range group.features {
+			table.AddRow(f.name, yesNo(f.value))
+		}
+		table.AddSeparator()
+	}
+
 	table.AddRow("INSTANCE EXTENSIONS", "")
 	instanceExt := getInstanceExtensions()
 	for i, extName := range instanceExt {
@@ -240,8 +330,8 @@ func printInfo(v *VulkanDeviceInfo) {
 	if len(instanceLayers) > 0 {
 		table.AddSeparator()
 		table.AddRow("INSTANCE LAYERS")
-		for i, layerName := range instanceLayers {
-			table.AddRow(i+1, layerName)
+		for i, layer := range instanceLayers {
+			table.AddRow(i+1, fmt.Sprintf("%s: %s", layer.name, layer.description))
 		}
 	}
 
@@ -249,14 +339,84 @@ func printInfo(v *VulkanDeviceInfo) {
 	if len(deviceLayers) > 0 {
 		table.AddSeparator()
 		table.AddRow("DEVICE LAYERS")
-		for i, layerName := range deviceLayers {
-			table.AddRow(i+1, layerName)
+		for i, layer := range deviceLayers {
+			table.AddRow(i+1, fmt.Sprintf("%s: %s", layer.name, layer.description))
 		}
 	}
 
 	fmt.Println("\n\n" + table.Render())
 }
 
+type deviceFeature struct {
+	name  string
+	value vk.Bool32
+}
+
+type deviceFeatureGroup struct {
+	title    string
+	features []deviceFeature
+}
+
+// featureGroups picks the commonly needed features out of vk.PhysicalDeviceFeatures.
+func featureGroups(f *vk.PhysicalDeviceFeatures) []deviceFeatureGroup {
+	return []deviceFeatureGroup{{
+		title: "SHADER STAGE FEATURES",
+		features: []deviceFeature{
+			{"Geometry shader", f.GeometryShader},
+			{"Tessellation shader", f.TessellationShader},
+			{"Shader float64", f.ShaderFloat64},
+			{"Shader int64", f.ShaderInt64},
+			{"Shader int16", f.ShaderInt16},
+			{"Shader clip distance", f.ShaderClipDistance},
+		},
+	}, {
+		title: "SAMPLING FEATURES",
+		features: []deviceFeature{
+			{"Sampler anisotropy", f.SamplerAnisotropy},
+			{"Texture compression ETC2", f.TextureCompressionETC2},
+			{"Texture compression ASTC LDR", f.TextureCompressionASTC_LDR},
+			{"Texture compression BC", f.TextureCompressionBC},
+			{"Image cube array", f.ImageCubeArray},
+		},
+	}, {
+		title: "DRAWING FEATURES",
+		features: []deviceFeature{
+			{"Multi draw indirect", f.MultiDrawIndirect},
+			{"Draw indirect first instance", f.DrawIndirectFirstInstance},
+			{"Full draw index uint32", f.FullDrawIndexUint32},
+			{"Independent blend", f.IndependentBlend},
+			{"Dual source blend", f.DualSrcBlend},
+			{"Logic op", f.LogicOp},
+			{"Fill mode non-solid", f.FillModeNonSolid},
+			{"Wide lines", f.WideLines},
+			{"Large points", f.LargePoints},
+			{"Depth clamp", f.DepthClamp},
+			{"Depth bias clamp", f.DepthBiasClamp},
+			{"Depth bounds", f.DepthBounds},
+			{"Multi viewport", f.MultiViewport},
+			{"Sample rate shading", f.SampleRateShading},
+			{"Pipeline statistics query", f.PipelineStatisticsQuery},
+			{"Occlusion query precise", f.OcclusionQueryPrecise},
+		},
+	}, {
+		title: "SPARSE FEATURES",
+		features: []deviceFeature{
+			{"Sparse binding", f.SparseBinding},
+			{"Sparse residency buffer", f.SparseResidencyBuffer},
+			{"Sparse residency image 2D", f.SparseResidencyImage2D},
+			{"Sparse residency image 3D", f.SparseResidencyImage3D},
+			{"Sparse residency aliased", f.SparseResidencyAliased},
+		},
+	}}
+}
+
+func yesNo(v vk.Bool32) string {
+	if v == vk.Bool32(vk.True) {
+		return "Yes"
+	}
+	return "No"
+}
+
 func physicalDeviceType(dev vk.PhysicalDeviceType) string {
 	switch dev {
 	case vk.PhysicalDeviceTypeIntegratedGpu: